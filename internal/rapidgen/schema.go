@@ -0,0 +1,192 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rapidgen provides rapid generators for arbitrary shim.SchemaMap shapes and for Pulumi values that conform
+// to them, for property-based tests of the value converters.
+//
+// Generated schemas are built from pkg/tfshim/schema, so they do not depend on a particular Terraform SDK.
+package rapidgen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"pgregory.net/rapid"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// SchemaMap is a generated shim.SchemaMap.
+//
+// It renders as an indented tree under %#v, which keeps the output of failed and shrunk rapid tests readable.
+type SchemaMap struct {
+	shim.SchemaMap
+}
+
+func (m SchemaMap) GoString() string {
+	var b strings.Builder
+	writeSchemaMap(&b, m.SchemaMap, 0)
+	return b.String()
+}
+
+// SchemaMapGen generates schema maps with up to depth levels of nested blocks and collections.
+//
+// Generated properties cover every shim.ValueType, nested blocks backed by lists and sets, MaxItems=1 blocks and
+// collections, and the Required, Optional, Computed and Sensitive flags.
+func SchemaMapGen(depth int) *rapid.Generator[SchemaMap] {
+	return rapid.Custom(func(t *rapid.T) SchemaMap {
+		return SchemaMap{schemaMapGen(depth).Draw(t, "schemaMap")}
+	})
+}
+
+// PropertyNameGen generates Terraform property names. The names are chosen to exercise the camel-casing and
+// pluralization done by the bridge when naming Pulumi properties.
+func PropertyNameGen() *rapid.Generator[string] {
+	return rapid.SampledFrom([]string{"name", "rule", "ip_address", "setting"})
+}
+
+func schemaMapGen(depth int) *rapid.Generator[shim.SchemaMap] {
+	return rapid.Custom(func(t *rapid.T) shim.SchemaMap {
+		fields := rapid.MapOfN(PropertyNameGen(), schemaGen(depth), 1, 3).Draw(t, "fields")
+		m := schema.SchemaMap{}
+		for k, v := range fields {
+			m[k] = v.Shim()
+		}
+		return m
+	})
+}
+
+func schemaGen(depth int) *rapid.Generator[*schema.Schema] {
+	if depth <= 1 {
+		return scalarGen()
+	}
+	return rapid.OneOf(scalarGen(), collectionGen(depth), blockGen(depth))
+}
+
+func scalarGen() *rapid.Generator[*schema.Schema] {
+	return rapid.Custom(func(t *rapid.T) *schema.Schema {
+		s := &schema.Schema{
+			Type: rapid.SampledFrom([]shim.ValueType{
+				shim.TypeBool, shim.TypeInt, shim.TypeFloat, shim.TypeString,
+			}).Draw(t, "type"),
+		}
+		setFlags(t, s)
+		return s
+	})
+}
+
+// collectionGen generates lists, sets and maps of attributes. As in SDKv2, map elements are always scalars.
+func collectionGen(depth int) *rapid.Generator[*schema.Schema] {
+	return rapid.Custom(func(t *rapid.T) *schema.Schema {
+		s := &schema.Schema{
+			Type: rapid.SampledFrom([]shim.ValueType{
+				shim.TypeList, shim.TypeSet, shim.TypeMap,
+			}).Draw(t, "type"),
+		}
+		elem := schemaGen(depth - 1)
+		if s.Type == shim.TypeMap {
+			elem = scalarGen()
+		} else if rapid.Bool().Draw(t, "maxItemsOne") {
+			s.MaxItems = 1
+		}
+		s.Elem = elemGen(elem).Draw(t, "elem").Shim()
+		setFlags(t, s)
+		return s
+	})
+}
+
+// elemGen generates the element schema of a collection. Flags are not set on elements, matching the SDKs.
+func elemGen(g *rapid.Generator[*schema.Schema]) *rapid.Generator[*schema.Schema] {
+	return rapid.Custom(func(t *rapid.T) *schema.Schema {
+		s := g.Draw(t, "schema")
+		s.Required, s.Optional, s.Computed, s.Sensitive = false, false, false, false
+		return s
+	})
+}
+
+// blockGen generates nested blocks, which are lists or sets of objects.
+func blockGen(depth int) *rapid.Generator[*schema.Schema] {
+	return rapid.Custom(func(t *rapid.T) *schema.Schema {
+		s := &schema.Schema{
+			Type: rapid.SampledFrom([]shim.ValueType{shim.TypeList, shim.TypeSet}).Draw(t, "type"),
+			Elem: (&schema.Resource{
+				Schema: schemaMapGen(depth-1).Draw(t, "block"),
+			}).Shim(),
+		}
+		if rapid.Bool().Draw(t, "maxItemsOne") {
+			s.MaxItems = 1
+		}
+		setFlags(t, s)
+		return s
+	})
+}
+
+func setFlags(t *rapid.T, s *schema.Schema) {
+	switch rapid.SampledFrom([]string{"required", "optional", "computed", "optional+computed"}).Draw(t, "kind") {
+	case "required":
+		s.Required = true
+	case "optional":
+		s.Optional = true
+	case "computed":
+		s.Computed = true
+	case "optional+computed":
+		s.Optional, s.Computed = true, true
+	}
+	s.Sensitive = rapid.Bool().Draw(t, "sensitive")
+}
+
+func writeSchemaMap(b *strings.Builder, m shim.SchemaMap, indent int) {
+	var keys []string
+	m.Range(func(k string, _ shim.Schema) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+
+	b.WriteString("{\n")
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s%s: ", strings.Repeat("  ", indent+1), k)
+		writeSchema(b, m.Get(k), indent+1)
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(b, "%s}", strings.Repeat("  ", indent))
+}
+
+func writeSchema(b *strings.Builder, s shim.Schema, indent int) {
+	b.WriteString(s.Type().String())
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{
+		{s.Required(), "Required"},
+		{s.Optional(), "Optional"},
+		{s.Computed(), "Computed"},
+		{s.Sensitive(), "Sensitive"},
+		{s.MaxItems() == 1, "MaxItems=1"},
+	} {
+		if flag.set {
+			b.WriteString(" " + flag.name)
+		}
+	}
+	switch elem := s.Elem().(type) {
+	case shim.Schema:
+		b.WriteString(" of ")
+		writeSchema(b, elem, indent)
+	case shim.Resource:
+		b.WriteString(" of ")
+		writeSchemaMap(b, elem.Schema(), indent)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rapidgen
+
+import (
+	"sort"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"pgregory.net/rapid"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/propertyvalue"
+)
+
+// ValueOptions controls the values generated by PropertyMapGen.
+type ValueOptions struct {
+	// PulumiName maps the Terraform name of a property of sm to its Pulumi name. If nil, Terraform names are used
+	// as is.
+	//
+	// Tests of the bridge typically pass tfbridge.TerraformToPulumiNameV2. It is not called directly since this
+	// package is used by the tests of package tfbridge itself.
+	PulumiName func(name string, sm shim.SchemaMap) string
+
+	// Allow values to be marked secret.
+	Secrets bool
+
+	// Allow values to be replaced by unknowns.
+	Unknowns bool
+}
+
+func (o ValueOptions) pulumiName(name string, sm shim.SchemaMap) string {
+	if o.PulumiName == nil {
+		return name
+	}
+	return o.PulumiName(name, sm)
+}
+
+// PropertyMap is a generated resource.PropertyMap. Like SchemaMap, it renders readably under %#v.
+type PropertyMap struct {
+	resource.PropertyMap
+}
+
+func (m PropertyMap) GoString() string {
+	return resource.NewObjectProperty(m.PropertyMap).String()
+}
+
+// PropertyMapGen generates Pulumi inputs that conform to sm.
+//
+// Computed-only properties are never set, and optional properties may be missing. Values of properties with
+// MaxItems=1 are flattened, as they are in Pulumi schemas generated by the bridge.
+func PropertyMapGen(sm shim.SchemaMap, opts ValueOptions) *rapid.Generator[PropertyMap] {
+	return rapid.Custom(func(t *rapid.T) PropertyMap {
+		return PropertyMap{objectGen(sm, opts).Draw(t, "value")}
+	})
+}
+
+func objectGen(sm shim.SchemaMap, opts ValueOptions) *rapid.Generator[resource.PropertyMap] {
+	return rapid.Custom(func(t *rapid.T) resource.PropertyMap {
+		var keys []string
+		sm.Range(func(k string, _ shim.Schema) bool {
+			keys = append(keys, k)
+			return true
+		})
+		sort.Strings(keys)
+
+		m := resource.PropertyMap{}
+		for _, k := range keys {
+			s := sm.Get(k)
+			// Presence is drawn even for computed-only properties, so that every object draws from the bitstream.
+			if !s.Required() && !rapid.Bool().Draw(t, k+".present") {
+				continue
+			}
+			if s.Computed() && !s.Optional() {
+				continue
+			}
+			m[resource.PropertyKey(opts.pulumiName(k, sm))] = valueGen(s, opts).Draw(t, k)
+		}
+		return m
+	})
+}
+
+func valueGen(s shim.Schema, opts ValueOptions) *rapid.Generator[resource.PropertyValue] {
+	return rapid.Custom(func(t *rapid.T) resource.PropertyValue {
+		if opts.Unknowns && rapid.Bool().Draw(t, "unknown") {
+			return resource.MakeComputed(resource.NewStringProperty(""))
+		}
+		v := plainValueGen(s, opts).Draw(t, "plain")
+		if opts.Secrets && rapid.Bool().Draw(t, "secret") {
+			return resource.MakeSecret(v)
+		}
+		return v
+	})
+}
+
+func plainValueGen(s shim.Schema, opts ValueOptions) *rapid.Generator[resource.PropertyValue] {
+	switch s.Type() {
+	case shim.TypeBool:
+		return rapid.Map(rapid.Bool(), resource.NewBoolProperty)
+	case shim.TypeInt:
+		return rapid.Map(rapid.IntRange(-1000, 1000), func(i int) resource.PropertyValue {
+			return resource.NewNumberProperty(float64(i))
+		})
+	case shim.TypeFloat:
+		return rapid.Map(rapid.Float64Range(-1000, 1000), resource.NewNumberProperty)
+	case shim.TypeString:
+		return rapid.Map(rapid.StringMatching(`[a-z0-9 ]{0,4}`), resource.NewStringProperty)
+	case shim.TypeMap:
+		return rapid.Custom(func(t *rapid.T) resource.PropertyValue {
+			m := rapid.MapOfN(rapid.StringMatching(`[a-z]{1,3}`), elemValueGen(s, opts), 0, 3).Draw(t, "map")
+			pm := resource.PropertyMap{}
+			for k, v := range m {
+				pm[resource.PropertyKey(k)] = v
+			}
+			return resource.NewObjectProperty(pm)
+		})
+	case shim.TypeList, shim.TypeSet:
+		return rapid.Custom(func(t *rapid.T) resource.PropertyValue {
+			if s.MaxItems() == 1 {
+				return elemValueGen(s, opts).Draw(t, "elem")
+			}
+			elems := elemValueGen(s, opts)
+			var arr []resource.PropertyValue
+			if s.Type() == shim.TypeSet {
+				// Set elements must stay distinct once secrets are removed during conversion.
+				arr = rapid.SliceOfNDistinct(elems, 0, 3, func(v resource.PropertyValue) string {
+					return propertyvalue.RemoveSecrets(v).String()
+				}).Draw(t, "set")
+			} else {
+				arr = rapid.SliceOfN(elems, 0, 3).Draw(t, "list")
+			}
+			return resource.NewArrayProperty(arr)
+		})
+	default:
+		contract.Failf("unexpected type %v", s.Type())
+		return nil
+	}
+}
+
+func elemValueGen(s shim.Schema, opts ValueOptions) *rapid.Generator[resource.PropertyValue] {
+	switch elem := s.Elem().(type) {
+	case shim.Schema:
+		return valueGen(elem, opts)
+	case shim.Resource:
+		return rapid.Custom(func(t *rapid.T) resource.PropertyValue {
+			return resource.NewObjectProperty(objectGen(elem.Schema(), opts).Draw(t, "object"))
+		})
+	default:
+		contract.Failf("unexpected element %#v", elem)
+		return nil
+	}
+}
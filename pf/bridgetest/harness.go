@@ -0,0 +1,281 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bridgetest drives bridged providers in memory, for use in tests of providers built with
+// pulumi-terraform-bridge.
+//
+// A test declares a Plugin Framework provider inline with Provider and Resource, or an SDKv2 provider with
+// schema.Provider, wraps it with a ProviderInfo, and then calls Check, Diff, Create, Read, Update and Delete with
+// PropertyMap values:
+//
+//	h := bridgetest.NewPF(t, &bridgetest.Provider{
+//		TypeName: "test",
+//		AllResources: []bridgetest.Resource{{
+//			Name: "res",
+//			ResourceSchema: schema.Schema{...},
+//		}},
+//	}, tfbridge.ProviderInfo{Name: "test"})
+//
+//	id, outputs := h.Create("test:index:Res", resource.PropertyMap{...})
+//
+// Harness.Server returns the underlying gRPC server, which can be passed to testing/x.Replay.
+package bridgetest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	sdkv2schema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	pftfbridge "github.com/pulumi/pulumi-terraform-bridge/pf/tfbridge"
+	pftfgen "github.com/pulumi/pulumi-terraform-bridge/pf/tfgen"
+	replay "github.com/pulumi/pulumi-terraform-bridge/testing/x"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	tfbridgetokens "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/tokens"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfgen"
+	sdkv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+// Harness serves a bridged provider in memory.
+type Harness struct {
+	t      *testing.T
+	info   tfbridge.ProviderInfo
+	server pulumirpc.ResourceProviderServer
+}
+
+// NewPF bridges the Plugin Framework provider p with info and serves it in memory.
+//
+// info.P is set from p. If info.Resources and info.DataSources are both empty, tokens are assigned to all
+// resources and data sources in a single "index" module.
+func NewPF(t *testing.T, p provider.Provider, info tfbridge.ProviderInfo) *Harness {
+	t.Helper()
+	ctx := context.Background()
+
+	info.P = pftfbridge.ShimProvider(p)
+	info = prepareInfo(t, info)
+
+	generated, err := pftfgen.GenerateSchema(ctx, pftfgen.GenerateSchemaOptions{
+		ProviderInfo:    info,
+		DiagnosticsSink: testSink(t),
+	})
+	require.NoError(t, err)
+
+	server, err := pftfbridge.NewProviderServer(ctx, nil, info, generated.ProviderMetadata)
+	require.NoError(t, err)
+	return &Harness{t: t, info: info, server: server}
+}
+
+// NewSDKv2 bridges the SDKv2 provider p with info and serves it in memory.
+//
+// info.P is set from p. If info.Resources and info.DataSources are both empty, tokens are assigned to all
+// resources and data sources in a single "index" module.
+func NewSDKv2(t *testing.T, p *sdkv2schema.Provider, info tfbridge.ProviderInfo) *Harness {
+	t.Helper()
+	ctx := context.Background()
+
+	info.P = sdkv2.NewProvider(p)
+	info = prepareInfo(t, info)
+
+	spec, err := tfgen.GenerateSchema(info, testSink(t))
+	require.NoError(t, err)
+	schema, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	server := tfbridge.NewProvider(ctx, nil, info.Name, info.Version, info.P, info, schema)
+	return &Harness{t: t, info: info, server: server}
+}
+
+func prepareInfo(t *testing.T, info tfbridge.ProviderInfo) tfbridge.ProviderInfo {
+	require.NotEmpty(t, info.Name, "ProviderInfo.Name is required")
+	if info.Version == "" {
+		info.Version = "0.0.1"
+	}
+	if info.MetadataInfo == nil {
+		info.MetadataInfo = tfbridge.NewProviderMetadata(nil)
+	}
+	if len(info.Resources) == 0 && len(info.DataSources) == 0 {
+		info.MustComputeTokens(tfbridgetokens.SingleModule(info.GetResourcePrefix()+"_", "index",
+			tfbridgetokens.MakeStandard(info.Name)))
+	}
+	return info
+}
+
+func testSink(t *testing.T) diag.Sink {
+	var stdout, stderr bytes.Buffer
+	sink := diag.DefaultSink(&stdout, &stderr, diag.FormatOptions{Color: colors.Never})
+	t.Cleanup(func() {
+		if s := strings.TrimSpace(stdout.String()); s != "" {
+			t.Logf("%s\n", s)
+		}
+		if s := strings.TrimSpace(stderr.String()); s != "" {
+			t.Logf("%s\n", s)
+		}
+	})
+	return sink
+}
+
+// Server returns the gRPC server of the bridged provider, for example to pass to testing/x.Replay.
+func (h *Harness) Server() pulumirpc.ResourceProviderServer {
+	return h.server
+}
+
+// ProviderInfo returns the ProviderInfo the provider was bridged with, including computed tokens.
+func (h *Harness) ProviderInfo() tfbridge.ProviderInfo {
+	return h.info
+}
+
+// Replay executes a request from a provider operation log against the provider, see testing/x.Replay.
+func (h *Harness) Replay(jsonLog string) {
+	h.t.Helper()
+	replay.Replay(h.t, h.server, jsonLog)
+}
+
+// URN returns the URN used for a resource of type tok in requests made by the harness.
+func (h *Harness) URN(tok string) resource.URN {
+	return resource.NewURN("test", "test", "", tokens.Type(tok), "test")
+}
+
+// Configure configures the provider with config, after checking it with CheckConfig.
+func (h *Harness) Configure(config resource.PropertyMap) {
+	h.t.Helper()
+	ctx := context.Background()
+	urn := string(resource.NewURN("test", "test", "", tokens.Type("pulumi:providers:"+h.info.Name), "test"))
+
+	checked, err := h.server.CheckConfig(ctx, &pulumirpc.CheckRequest{
+		Urn:  urn,
+		Olds: h.marshal(nil),
+		News: h.marshal(config),
+	})
+	require.NoError(h.t, err)
+	require.Empty(h.t, checked.GetFailures(), "CheckConfig failed")
+
+	_, err = h.server.Configure(ctx, &pulumirpc.ConfigureRequest{
+		Args:            checked.GetInputs(),
+		AcceptSecrets:   true,
+		AcceptResources: true,
+	})
+	require.NoError(h.t, err)
+}
+
+// Check validates news for a resource of type tok, returning the checked inputs and any check failures.
+func (h *Harness) Check(tok string, olds, news resource.PropertyMap) (resource.PropertyMap, []*pulumirpc.CheckFailure) {
+	h.t.Helper()
+	resp, err := h.server.Check(context.Background(), &pulumirpc.CheckRequest{
+		Urn:  string(h.URN(tok)),
+		Olds: h.marshal(olds),
+		News: h.marshal(news),
+	})
+	require.NoError(h.t, err)
+	return h.unmarshal(resp.GetInputs()), resp.GetFailures()
+}
+
+// Diff compares the prior state olds of a resource of type tok with the checked inputs news.
+func (h *Harness) Diff(tok, id string, olds, news resource.PropertyMap) *pulumirpc.DiffResponse {
+	h.t.Helper()
+	resp, err := h.server.Diff(context.Background(), &pulumirpc.DiffRequest{
+		Id:   id,
+		Urn:  string(h.URN(tok)),
+		Olds: h.marshal(olds),
+		News: h.marshal(news),
+	})
+	require.NoError(h.t, err)
+	return resp
+}
+
+// Create creates a resource of type tok from the checked inputs props, returning its ID and outputs.
+func (h *Harness) Create(tok string, props resource.PropertyMap) (string, resource.PropertyMap) {
+	h.t.Helper()
+	resp, err := h.server.Create(context.Background(), &pulumirpc.CreateRequest{
+		Urn:        string(h.URN(tok)),
+		Properties: h.marshal(props),
+	})
+	require.NoError(h.t, err)
+	return resp.GetId(), h.unmarshal(resp.GetProperties())
+}
+
+// Read refreshes a resource of type tok, returning its outputs and inputs. A nil state reads the resource as an
+// import.
+func (h *Harness) Read(tok, id string, state resource.PropertyMap) (resource.PropertyMap, resource.PropertyMap) {
+	h.t.Helper()
+	resp, err := h.server.Read(context.Background(), &pulumirpc.ReadRequest{
+		Id:         id,
+		Urn:        string(h.URN(tok)),
+		Properties: h.marshal(state),
+	})
+	require.NoError(h.t, err)
+	return h.unmarshal(resp.GetProperties()), h.unmarshal(resp.GetInputs())
+}
+
+// Update updates a resource of type tok from its prior state olds to the checked inputs news, returning its new
+// outputs.
+func (h *Harness) Update(tok, id string, olds, news resource.PropertyMap) resource.PropertyMap {
+	h.t.Helper()
+	resp, err := h.server.Update(context.Background(), &pulumirpc.UpdateRequest{
+		Id:   id,
+		Urn:  string(h.URN(tok)),
+		Olds: h.marshal(olds),
+		News: h.marshal(news),
+	})
+	require.NoError(h.t, err)
+	return h.unmarshal(resp.GetProperties())
+}
+
+// Delete deletes a resource of type tok with the given state.
+func (h *Harness) Delete(tok, id string, state resource.PropertyMap) {
+	h.t.Helper()
+	_, err := h.server.Delete(context.Background(), &pulumirpc.DeleteRequest{
+		Id:         id,
+		Urn:        string(h.URN(tok)),
+		Properties: h.marshal(state),
+	})
+	require.NoError(h.t, err)
+}
+
+var marshalOptions = plugin.MarshalOptions{
+	KeepUnknowns:  true,
+	KeepSecrets:   true,
+	KeepResources: true,
+}
+
+func (h *Harness) marshal(m resource.PropertyMap) *structpb.Struct {
+	h.t.Helper()
+	if m == nil {
+		return nil
+	}
+	s, err := plugin.MarshalProperties(m, marshalOptions)
+	require.NoError(h.t, err)
+	return s
+}
+
+func (h *Harness) unmarshal(s *structpb.Struct) resource.PropertyMap {
+	h.t.Helper()
+	if s == nil {
+		return nil
+	}
+	m, err := plugin.UnmarshalProperties(s, marshalOptions)
+	require.NoError(h.t, err)
+	return m
+}
@@ -0,0 +1,146 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bridgetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	sdkv2schema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+func TestHarnessPF(t *testing.T) {
+	t.Parallel()
+
+	h := NewPF(t, &Provider{
+		TypeName: "test",
+		AllResources: []Resource{{
+			Name: "res",
+			ResourceSchema: rschema.Schema{
+				Attributes: map[string]rschema.Attribute{
+					"id": rschema.StringAttribute{
+						Computed: true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"name": rschema.StringAttribute{Required: true},
+				},
+			},
+			CreateFunc: func(ctx context.Context, req fwresource.CreateRequest, resp *fwresource.CreateResponse) {
+				resp.State.Raw = req.Plan.Raw
+				resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), types.StringValue("id-1"))...)
+			},
+		}},
+	}, tfbridge.ProviderInfo{Name: "test"})
+
+	tok := "test:index/res:Res"
+	h.Configure(resource.PropertyMap{})
+
+	inputs, failures := h.Check(tok, nil, resource.PropertyMap{"name": resource.NewStringProperty("a")})
+	assert.Empty(t, failures)
+
+	id, outputs := h.Create(tok, inputs)
+	assert.Equal(t, "id-1", id)
+	assert.Equal(t, resource.NewStringProperty("a"), outputs["name"])
+
+	news, _ := h.Check(tok, inputs, resource.PropertyMap{"name": resource.NewStringProperty("b")})
+	diff := h.Diff(tok, id, outputs, news)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, diff.GetChanges())
+	assert.Equal(t, []string{"name"}, diff.GetDiffs())
+
+	outputs = h.Update(tok, id, outputs, news)
+	assert.Equal(t, resource.NewStringProperty("b"), outputs["name"])
+
+	read, _ := h.Read(tok, id, outputs)
+	assert.Equal(t, resource.NewStringProperty("b"), read["name"])
+
+	h.Delete(tok, id, outputs)
+
+	h.Replay(`
+	{
+	  "method": "/pulumirpc.ResourceProvider/Check",
+	  "request": {
+	    "urn": "urn:pulumi:test::test::test:index/res:Res::test",
+	    "olds": {},
+	    "news": {"name": "c"}
+	  },
+	  "response": {
+	    "inputs": {"name": "c"}
+	  }
+	}`)
+}
+
+func TestHarnessSDKv2(t *testing.T) {
+	t.Parallel()
+
+	h := NewSDKv2(t, &sdkv2schema.Provider{
+		ResourcesMap: map[string]*sdkv2schema.Resource{
+			"test_res": {
+				Schema: map[string]*sdkv2schema.Schema{
+					"name": {Type: sdkv2schema.TypeString, Required: true, ForceNew: true},
+				},
+				CreateContext: func(
+					_ context.Context, d *sdkv2schema.ResourceData, _ interface{},
+				) diag.Diagnostics {
+					d.SetId("id-1")
+					return nil
+				},
+				ReadContext: func(
+					context.Context, *sdkv2schema.ResourceData, interface{},
+				) diag.Diagnostics {
+					return nil
+				},
+				DeleteContext: func(
+					context.Context, *sdkv2schema.ResourceData, interface{},
+				) diag.Diagnostics {
+					return nil
+				},
+			},
+		},
+	}, tfbridge.ProviderInfo{Name: "test"})
+
+	tok := "test:index/res:Res"
+	h.Configure(resource.PropertyMap{})
+
+	_, failures := h.Check(tok, nil, resource.PropertyMap{})
+	assert.Len(t, failures, 1)
+
+	inputs, failures := h.Check(tok, nil, resource.PropertyMap{"name": resource.NewStringProperty("a")})
+	assert.Empty(t, failures)
+
+	id, outputs := h.Create(tok, inputs)
+	assert.Equal(t, "id-1", id)
+	assert.Equal(t, resource.NewStringProperty("a"), outputs["name"])
+
+	news, _ := h.Check(tok, inputs, resource.PropertyMap{"name": resource.NewStringProperty("b")})
+	diff := h.Diff(tok, id, outputs, news)
+	assert.Equal(t, []string{"name"}, diff.GetReplaces())
+
+	h.Delete(tok, id, outputs)
+}
@@ -0,0 +1,139 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bridgetest
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	pschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// Provider is a Plugin Framework provider declared inline, for use in tests.
+type Provider struct {
+	TypeName       string
+	Version        string
+	ProviderSchema pschema.Schema
+	AllResources   []Resource
+
+	// Optional. Called when the provider is configured.
+	ConfigureFunc func(context.Context, provider.ConfigureRequest, *provider.ConfigureResponse)
+}
+
+var _ provider.Provider = (*Provider)(nil)
+
+func (impl *Provider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = impl.TypeName
+	resp.Version = impl.Version
+}
+
+func (impl *Provider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = impl.ProviderSchema
+}
+
+func (impl *Provider) Configure(
+	ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse,
+) {
+	if impl.ConfigureFunc != nil {
+		impl.ConfigureFunc(ctx, req, resp)
+	}
+}
+
+func (*Provider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{}
+}
+
+func (impl *Provider) Resources(ctx context.Context) []func() resource.Resource {
+	r := make([]func() resource.Resource, len(impl.AllResources))
+	for i := 0; i < len(impl.AllResources); i++ {
+		i := i
+		r[i] = func() resource.Resource {
+			return &impl.AllResources[i]
+		}
+	}
+	return r
+}
+
+// Resource is a Plugin Framework resource declared inline, for use in tests.
+//
+// CRUD functions are optional. By default, Create and Update store the planned state, Read keeps the prior state,
+// and Delete removes the resource.
+type Resource struct {
+	Name           string
+	ResourceSchema rschema.Schema
+
+	CreateFunc func(context.Context, resource.CreateRequest, *resource.CreateResponse)
+	ReadFunc   func(context.Context, resource.ReadRequest, *resource.ReadResponse)
+	UpdateFunc func(context.Context, resource.UpdateRequest, *resource.UpdateResponse)
+	DeleteFunc func(context.Context, resource.DeleteRequest, *resource.DeleteResponse)
+
+	// Optional. By default, imports pass the ID through to the "id" attribute.
+	ImportStateFunc func(context.Context, resource.ImportStateRequest, *resource.ImportStateResponse)
+}
+
+var (
+	_ resource.Resource                = (*Resource)(nil)
+	_ resource.ResourceWithImportState = (*Resource)(nil)
+)
+
+func (r *Resource) Metadata(ctx context.Context, req resource.MetadataRequest, re *resource.MetadataResponse) {
+	re.TypeName = req.ProviderTypeName + "_" + r.Name
+}
+
+func (r *Resource) Schema(ctx context.Context, _ resource.SchemaRequest, re *resource.SchemaResponse) {
+	re.Schema = r.ResourceSchema
+}
+
+func (r *Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.CreateFunc != nil {
+		r.CreateFunc(ctx, req, resp)
+		return
+	}
+	resp.State.Raw = req.Plan.Raw
+}
+
+func (r *Resource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.ReadFunc != nil {
+		r.ReadFunc(ctx, req, resp)
+	}
+}
+
+func (r *Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.UpdateFunc != nil {
+		r.UpdateFunc(ctx, req, resp)
+		return
+	}
+	resp.State.Raw = req.Plan.Raw
+}
+
+func (r *Resource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.DeleteFunc != nil {
+		r.DeleteFunc(ctx, req, resp)
+	}
+}
+
+func (r *Resource) ImportState(
+	ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse,
+) {
+	if r.ImportStateFunc != nil {
+		r.ImportStateFunc(ctx, req, resp)
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -18,7 +18,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -62,8 +61,13 @@ func ApplyDefaultInfoValues(ctx context.Context, args ApplyDefaultInfoValuesArgs
 		return args.PropertyMap
 	}
 
+	cdOptions := args.ComputeDefaultOptions
+	if cdOptions.ProviderConfig == nil {
+		cdOptions.ProviderConfig = args.ProviderConfig
+	}
+
 	t := &defaultsTransform{
-		computeDefaultOptions: args.ComputeDefaultOptions,
+		computeDefaultOptions: cdOptions,
 		topSchemaMap:          args.SchemaMap,
 		topFieldInfos:         args.SchemaInfos,
 		providerConfig:        args.ProviderConfig,
@@ -78,14 +82,15 @@ func getDefaultValue(
 	property resource.PropertyKey,
 	cdOptions tfbridge.ComputeDefaultOptions,
 	fieldSchema shim.Schema,
-	defaultInfo *tfbridge.DefaultInfo,
+	fieldInfo *tfbridge.SchemaInfo,
 	providerConfig resource.PropertyMap,
 ) (resource.PropertyValue, bool) {
 	na := resource.NewNullProperty()
 
-	if defaultInfo == nil {
+	if fieldInfo == nil || fieldInfo.Default == nil {
 		return na, false
 	}
+	defaultInfo := fieldInfo.Default
 
 	// Conditional order follows old code v3/tfbridge but may be relaxed in the future, for instance allowing
 	// defaultInfo.Value to kick in as fallback when Config is specified but does not match.
@@ -94,7 +99,7 @@ func getDefaultValue(
 			// Following code in v3/tfbridge, ignoring set but empty env vars.
 			if str, ok := os.LookupEnv(n); ok && str != "" {
 
-				v, err := parseValueFromEnv(fieldSchema, str)
+				v, err := parseValueFromEnv(fieldSchema, fieldInfo, str)
 				if err != nil {
 					msg := fmt.Errorf("Cannot parse the value of environment variable '%s'"+
 						" to populate property '%s' with a default value: %w",
@@ -173,36 +178,19 @@ func getDefaultValue(
 	return na, false
 }
 
-func parseValueFromEnv(sch shim.Schema, str string) (resource.PropertyValue, error) {
+// Parses an environment variable into a default value. Besides scalars, lists may be given as JSON or as comma
+// separated values, and maps and blocks may be given as JSON objects.
+func parseValueFromEnv(sch shim.Schema, info *tfbridge.SchemaInfo, str string) (resource.PropertyValue, error) {
 	contract.Assertf(str != "", "parseValueFromEnv only works on non-empty strings")
-
-	switch sch.Type() {
-	case shim.TypeBool:
-		v, err := strconv.ParseBool(str)
-		if err != nil {
-			return resource.NewNullProperty(), err
-		}
-		return resource.NewBoolProperty(v), nil
-	case shim.TypeInt:
-		iv, iverr := strconv.ParseInt(str, 0, 0)
-		if iverr != nil {
-			return resource.NewNullProperty(), iverr
-		}
-		v := int(iv)
-		return resource.NewNumberProperty(float64(v)), nil
-	case shim.TypeFloat:
-		v, err := strconv.ParseFloat(str, 64)
-		if err != nil {
-			return resource.NewNullProperty(), err
-		}
-		return resource.NewNumberProperty(v), nil
-	case shim.TypeString:
-		return resource.NewStringProperty(str), nil
-	default:
-		return resource.NewNullProperty(), fmt.Errorf("unknown type for default value: %v", sch.Type())
+	v, err := tfbridge.ParseEnvDefault(str, sch, info)
+	if err != nil {
+		return resource.NewNullProperty(), err
 	}
+	return v, nil
 }
 
+// Converts a DefaultInfo value to a PropertyValue. Slices, maps and structs are converted recursively and are
+// expected to be in their Pulumi shape, that is keyed by Pulumi property names.
 func recoverDefaultValue(defaultValue any) resource.PropertyValue {
 	if pv, alreadyPV := defaultValue.(resource.PropertyValue); alreadyPV {
 		return pv
@@ -297,9 +285,14 @@ func (du *defaultsTransform) extendPropertyMapWithDefaults(
 			pk,
 			defaultOptionsCopy,
 			fieldSchema,
-			fld.Default,
+			fld,
 			du.providerConfig)
 		if gotDefault {
+			// Composite defaults may themselves contain properties with defaults. These need to be applied
+			// here as the recursion has already visited the children of props.
+			if pv.IsObject() || pv.IsArray() {
+				pv = du.withDefaults(ctx, subPath, pv)
+			}
 			res[pk] = pv
 		}
 	}
@@ -49,6 +49,29 @@ func TestApplyDefaultInfoValues(t *testing.T) {
 				},
 			}).Shim(),
 		}).Shim(),
+
+		"list_prop": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim(),
+
+		"map_prop": (&schema.Schema{
+			Type:     shim.TypeMap,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim(),
+
+		"block_list_prop": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			Elem: (&schema.Resource{
+				Schema: schema.SchemaMap{
+					"x_prop": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+					"y_prop": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+				},
+			}).Shim(),
+		}).Shim(),
 	}
 
 	type testCase struct {
@@ -323,6 +346,22 @@ func TestApplyDefaultInfoValues(t *testing.T) {
 				"stringProp": resource.NewStringProperty("OK"),
 			},
 		},
+		{
+			name: "AutoName is computed from provider configuration",
+			fieldInfos: map[string]*tfbridge.SchemaInfo{
+				"string_prop": tfbridge.AutoName("stringProp", 64, "-"),
+			},
+			computeDefaultOptions: tfbridge.ComputeDefaultOptions{
+				URN:  "urn:pulumi:dev::proj::pkg:index:Res::n1",
+				Seed: []byte("seed"),
+			},
+			providerConfig: resource.PropertyMap{
+				"autonaming": resource.NewStringProperty(`{"pattern": "${project}-${stack}-${name}"}`),
+			},
+			expected: resource.PropertyMap{
+				"stringProp": resource.NewStringProperty("proj-dev-n1"),
+			},
+		},
 		{
 			name: "Empty env var is not a numeric zero",
 			fieldInfos: map[string]*tfbridge.SchemaInfo{
@@ -337,6 +376,138 @@ func TestApplyDefaultInfoValues(t *testing.T) {
 			},
 			expected: resource.PropertyMap{},
 		},
+		{
+			name: "list and map values",
+			fieldInfos: map[string]*tfbridge.SchemaInfo{
+				"list_prop": {
+					Default: &tfbridge.DefaultInfo{
+						Value: []string{"a", "b"},
+					},
+				},
+				"map_prop": {
+					Default: &tfbridge.DefaultInfo{
+						Value: map[string]string{"k": "v"},
+					},
+				},
+			},
+			expected: resource.PropertyMap{
+				"listProps": resource.NewArrayProperty([]resource.PropertyValue{
+					resource.NewStringProperty("a"),
+					resource.NewStringProperty("b"),
+				}),
+				"mapProp": resource.NewObjectProperty(resource.PropertyMap{
+					"k": resource.NewStringProperty("v"),
+				}),
+			},
+		},
+		{
+			name: "block values get nested defaults",
+			fieldInfos: map[string]*tfbridge.SchemaInfo{
+				"object_prop": {
+					Default: &tfbridge.DefaultInfo{
+						Value: struct {
+							X string `pulumi:"xProp"`
+						}{X: "X"},
+					},
+					Fields: map[string]*tfbridge.SchemaInfo{
+						"y_prop": {
+							Default: &tfbridge.DefaultInfo{
+								Value: "Y",
+							},
+						},
+					},
+				},
+				"block_list_prop": {
+					Default: &tfbridge.DefaultInfo{
+						Value: []map[string]any{{"xProp": "X1"}, {"xProp": "X2"}},
+					},
+					Elem: &tfbridge.SchemaInfo{
+						Fields: map[string]*tfbridge.SchemaInfo{
+							"y_prop": {
+								Default: &tfbridge.DefaultInfo{
+									Value: "Y",
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: resource.PropertyMap{
+				"objectProp": resource.NewObjectProperty(resource.PropertyMap{
+					"xProp": resource.NewStringProperty("X"),
+					"yProp": resource.NewStringProperty("Y"),
+				}),
+				"blockListProps": resource.NewArrayProperty([]resource.PropertyValue{
+					resource.NewObjectProperty(resource.PropertyMap{
+						"xProp": resource.NewStringProperty("X1"),
+						"yProp": resource.NewStringProperty("Y"),
+					}),
+					resource.NewObjectProperty(resource.PropertyMap{
+						"xProp": resource.NewStringProperty("X2"),
+						"yProp": resource.NewStringProperty("Y"),
+					}),
+				}),
+			},
+		},
+		{
+			name: "list, map and block props can be set from environment",
+			fieldInfos: map[string]*tfbridge.SchemaInfo{
+				"list_prop": {
+					Default: &tfbridge.DefaultInfo{
+						EnvVars: []string{"LIST_PROP"},
+					},
+				},
+				"map_prop": {
+					Default: &tfbridge.DefaultInfo{
+						EnvVars: []string{"MAP_PROP"},
+					},
+				},
+				"object_prop": {
+					Default: &tfbridge.DefaultInfo{
+						EnvVars: []string{"OBJECT_PROP"},
+					},
+					Fields: map[string]*tfbridge.SchemaInfo{
+						"y_prop": {
+							Default: &tfbridge.DefaultInfo{
+								Value: "Y",
+							},
+						},
+					},
+				},
+			},
+			env: map[string]string{
+				"LIST_PROP":   "a,b",
+				"MAP_PROP":    `{"k": "v"}`,
+				"OBJECT_PROP": `{"x_prop": "X"}`,
+			},
+			expected: resource.PropertyMap{
+				"listProps": resource.NewArrayProperty([]resource.PropertyValue{
+					resource.NewStringProperty("a"),
+					resource.NewStringProperty("b"),
+				}),
+				"mapProp": resource.NewObjectProperty(resource.PropertyMap{
+					"k": resource.NewStringProperty("v"),
+				}),
+				"objectProp": resource.NewObjectProperty(resource.PropertyMap{
+					"xProp": resource.NewStringProperty("X"),
+					"yProp": resource.NewStringProperty("Y"),
+				}),
+			},
+		},
+		{
+			name: "invalid JSON from environment is ignored",
+			fieldInfos: map[string]*tfbridge.SchemaInfo{
+				"map_prop": {
+					Default: &tfbridge.DefaultInfo{
+						EnvVars: []string{"MAP_PROP"},
+					},
+				},
+			},
+			env: map[string]string{
+				"MAP_PROP": `{"k": `,
+			},
+			expected: resource.PropertyMap{},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -28,6 +28,7 @@ import (
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	shimSchema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 	"github.com/pulumi/pulumi-terraform-bridge/x/muxer"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 )
@@ -154,6 +155,7 @@ func (m *ProviderShim) ResolveDispatch(info *tfbridge.ProviderInfo) (muxer.Dispa
 	var dispatch muxer.DispatchTable
 	dispatch.Resources = map[string]int{}
 	dispatch.Functions = map[string]int{}
+	dispatch.Config = info.MuxConfigPolicies
 
 	unbackedResources := resolveDispatchMap(m, dispatch.Resources, info.Resources,
 		func(p shim.Provider) shim.ResourceMap { return p.ResourcesMap() })
@@ -179,6 +181,44 @@ func (m *ProviderShim) ResolveDispatch(info *tfbridge.ProviderInfo) (muxer.Dispa
 	}
 }
 
+// MergeMuxWith layers the schemas of the ProviderInfo.MuxWith providers on top of the schema of the muxed
+// provider.
+//
+// specs must hold the schema of m followed by the schemas of each MuxWith provider in order, and dispatch must
+// be the result of ResolveDispatch. The returned dispatch table routes tokens served by m as dispatch does, and
+// tokens served by MuxWith[i] to index len(m.MuxedProviders)+i, which is where their servers are placed at
+// runtime.
+func (m *ProviderShim) MergeMuxWith(
+	specs []schema.PackageSpec, dispatch muxer.DispatchTable,
+) (schema.PackageSpec, muxer.DispatchTable, error) {
+	mixins, spec, err := muxer.MergeSchemasAndComputeDispatchTable(specs)
+	if err != nil {
+		return schema.PackageSpec{}, muxer.DispatchTable{}, err
+	}
+
+	layer := func(dst map[string]int, src, muxed map[string]int) {
+		for tk, i := range src {
+			if i == 0 {
+				// Tokens that ResolveDispatch could not back stay unmapped, as they would be without
+				// MuxWith.
+				if j, ok := muxed[tk]; ok {
+					dst[tk] = j
+				}
+				continue
+			}
+			dst[tk] = len(m.MuxedProviders) + i - 1
+		}
+	}
+
+	var result muxer.DispatchTable
+	result.Resources = map[string]int{}
+	result.Functions = map[string]int{}
+	result.Config = dispatch.Config
+	layer(result.Resources, mixins.Resources, dispatch.Resources)
+	layer(result.Functions, mixins.Functions, dispatch.Functions)
+	return spec, result, nil
+}
+
 // Resolve either resources or datasoruces into their originating providers.
 //
 // A resource/datasource is considered "from" a provider if the provider serves a
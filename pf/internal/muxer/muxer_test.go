@@ -0,0 +1,88 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package muxer
+
+import (
+	"testing"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/x/muxer"
+)
+
+func TestMergeMuxWith(t *testing.T) {
+	t.Parallel()
+
+	// Two muxed providers, as created by AugmentShimWithPF.
+	m := &ProviderShim{
+		MuxedProviders: []shim.Provider{nil, nil},
+	}
+	var dispatch muxer.DispatchTable
+	dispatch.Resources = map[string]int{
+		"test:index:Sdk":        0,
+		"test:index:Pf":         1,
+		"test:index:Overridden": 1,
+	}
+	dispatch.Functions = map[string]int{
+		"test:index:getPf": 1,
+	}
+	dispatch.Config = map[string]muxer.ConfigPolicy{"region": muxer.PreferServer(1)}
+
+	bridged := schema.PackageSpec{
+		Name: "test",
+		Resources: map[string]schema.ResourceSpec{
+			"test:index:Sdk":        {},
+			"test:index:Pf":         {},
+			"test:index:Overridden": {},
+			"test:index:Unbacked":   {},
+		},
+		Functions: map[string]schema.FunctionSpec{
+			"test:index:getPf": {},
+		},
+	}
+	mixin1 := schema.PackageSpec{
+		Name: "test",
+		Resources: map[string]schema.ResourceSpec{
+			"test:index:Overridden": {},
+			"test:index:Mixin1":     {},
+		},
+	}
+	mixin2 := schema.PackageSpec{
+		Name: "test",
+		Functions: map[string]schema.FunctionSpec{
+			"test:index:getMixin2": {},
+		},
+	}
+
+	spec, table, err := m.MergeMuxWith([]schema.PackageSpec{bridged, mixin1, mixin2}, dispatch)
+	require.NoError(t, err)
+
+	assert.Len(t, spec.Resources, 5)
+	assert.Len(t, spec.Functions, 2)
+	assert.Equal(t, map[string]int{
+		"test:index:Sdk":        0,
+		"test:index:Pf":         1,
+		"test:index:Overridden": 2,
+		"test:index:Mixin1":     2,
+	}, table.Resources)
+	assert.Equal(t, map[string]int{
+		"test:index:getPf":     1,
+		"test:index:getMixin2": 3,
+	}, table.Functions)
+	assert.Equal(t, dispatch.Config, table.Config)
+}
@@ -39,6 +39,7 @@ type Attr interface {
 	Nested() map[string]Attr
 	NestingMode() NestingMode
 	HasNestedObject() bool
+	IsWriteOnly() bool
 }
 
 type AttrLike interface {
@@ -90,6 +91,13 @@ func (a *attrAdapter) HasNestedObject() bool {
 	}
 }
 
+// IsWriteOnly reports whether the attribute is write-only. Versions of the framework that support write-only
+// attributes implement IsWriteOnly on fwschema.Attribute, older versions have no write-only attributes.
+func (a *attrAdapter) IsWriteOnly() bool {
+	w, ok := a.AttrLike.(interface{ IsWriteOnly() bool })
+	return ok && w.IsWriteOnly()
+}
+
 func (a *attrAdapter) IsNested() bool {
 	return a.nested != nil
 }
@@ -0,0 +1,34 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pfutils
+
+import (
+	"testing"
+
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// Mimics attributes of framework versions that support write-only attributes.
+type writeOnlyStringAttribute struct {
+	rschema.StringAttribute
+}
+
+func (writeOnlyStringAttribute) IsWriteOnly() bool { return true }
+
+func TestAttrIsWriteOnly(t *testing.T) {
+	assert.False(t, FromResourceAttribute(rschema.StringAttribute{Optional: true}).IsWriteOnly())
+	assert.True(t, FromAttrLike(writeOnlyStringAttribute{rschema.StringAttribute{Optional: true}}).IsWriteOnly())
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -42,13 +43,29 @@ func checkDiagsForErrors(diag diag.Diagnostics) error {
 	return nil
 }
 
+// An entry of a collection. The schema of the entry is only computed on first use, since instantiating the schemas
+// of every resource of a large provider is expensive and most operations need only one.
 type entry[T any] struct {
+	t          T
+	loadSchema func() (Schema, diag.Diagnostics, error)
+
+	once        sync.Once
 	schema      Schema
-	t           T
 	diagnostics diag.Diagnostics
+	err         error
+}
+
+func newEntry[T any](t T, loadSchema func() (Schema, diag.Diagnostics, error)) *entry[T] {
+	return &entry[T]{t: t, loadSchema: loadSchema}
 }
 
-type collection[T any] map[TypeName]entry[T]
+func (e *entry[T]) load() {
+	e.once.Do(func() {
+		e.schema, e.diagnostics, e.err = e.loadSchema()
+	})
+}
+
+type collection[T any] map[TypeName]*entry[T]
 
 func (c collection[T]) All() []TypeName {
 	if c == nil {
@@ -69,12 +86,22 @@ func (c collection[T]) Has(name TypeName) bool {
 	return ok
 }
 
-func (c collection[T]) Schema(name TypeName) Schema {
-	return c[name].schema
+func (c collection[T]) Schema(name TypeName) (Schema, error) {
+	e, ok := c[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown type name: %s", name)
+	}
+	e.load()
+	return e.schema, e.err
 }
 
 func (c collection[T]) Diagnostics(name TypeName) diag.Diagnostics {
-	return c[name].diagnostics
+	e, ok := c[name]
+	if !ok {
+		return nil
+	}
+	e.load()
+	return e.diagnostics
 }
 
 func (c collection[T]) AllDiagnostics() diag.Diagnostics {
@@ -23,11 +23,11 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 )
 
-// Represents all provider's datasources pre-indexed by TypeName.
+// Represents all provider's datasources pre-indexed by TypeName. Schemas are only computed on first use.
 type DataSources interface {
 	All() []TypeName
 	Has(TypeName) bool
-	Schema(TypeName) Schema
+	Schema(TypeName) (Schema, error)
 	Diagnostics(TypeName) diag.Diagnostics
 	AllDiagnostics() diag.Diagnostics
 	DataSource(TypeName) datasource.DataSource
@@ -45,20 +45,17 @@ func GatherDatasources(ctx context.Context, prov provider.Provider) (DataSources
 			ProviderTypeName: provMetadata.TypeName,
 		}, &meta)
 
-		schemaResponse := &datasource.SchemaResponse{}
-		dataSource.Schema(ctx, datasource.SchemaRequest{}, schemaResponse)
+		ds[TypeName(meta.TypeName)] = newEntry(makeDataSource, func() (Schema, diag.Diagnostics, error) {
+			schemaResponse := &datasource.SchemaResponse{}
+			dataSource.Schema(ctx, datasource.SchemaRequest{}, schemaResponse)
 
-		dataSourceSchema := schemaResponse.Schema
-		diag := schemaResponse.Diagnostics
-		if err := checkDiagsForErrors(diag); err != nil {
-			return nil, fmt.Errorf("Resource %s GetSchema() error: %w", meta.TypeName, err)
-		}
-
-		ds[TypeName(meta.TypeName)] = entry[func() datasource.DataSource]{
-			t:           makeDataSource,
-			schema:      FromDataSourceSchema(dataSourceSchema),
-			diagnostics: diag,
-		}
+			dataSourceSchema := schemaResponse.Schema
+			diag := schemaResponse.Diagnostics
+			if err := checkDiagsForErrors(diag); err != nil {
+				return nil, diag, fmt.Errorf("Resource %s GetSchema() error: %w", meta.TypeName, err)
+			}
+			return FromDataSourceSchema(dataSourceSchema), diag, nil
+		})
 	}
 
 	return &dataSources{collection: ds}, nil
@@ -23,17 +23,19 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 )
 
-// Represents all provider's resources pre-indexed by TypeName.
+// Represents all provider's resources pre-indexed by TypeName. Schemas are only computed on first use.
 type Resources interface {
 	All() []TypeName
 	Has(TypeName) bool
-	Schema(TypeName) Schema
+	Schema(TypeName) (Schema, error)
 	Diagnostics(TypeName) diag.Diagnostics
 	AllDiagnostics() diag.Diagnostics
 	Resource(TypeName) resource.Resource
 }
 
 // Collects all resources from prov and indexes them by TypeName.
+//
+// Only the metadata of the resources is queried eagerly, their schemas are computed when first requested.
 func GatherResources(ctx context.Context, prov provider.Provider) (Resources, error) {
 	provMetadata := queryProviderMetadata(ctx, prov)
 	rs := make(collection[func() resource.Resource])
@@ -46,19 +48,16 @@ func GatherResources(ctx context.Context, prov provider.Provider) (Resources, er
 			ProviderTypeName: provMetadata.TypeName,
 		}, &meta)
 
-		schemaResponse := &resource.SchemaResponse{}
-		res.Schema(ctx, resource.SchemaRequest{}, schemaResponse)
-
-		resSchema, diag := schemaResponse.Schema, schemaResponse.Diagnostics
-		if err := checkDiagsForErrors(diag); err != nil {
-			return nil, fmt.Errorf("Resource %s GetSchema() error: %w", meta.TypeName, err)
-		}
+		rs[TypeName(meta.TypeName)] = newEntry(makeResource, func() (Schema, diag.Diagnostics, error) {
+			schemaResponse := &resource.SchemaResponse{}
+			res.Schema(ctx, resource.SchemaRequest{}, schemaResponse)
 
-		rs[TypeName(meta.TypeName)] = entry[func() resource.Resource]{
-			t:           makeResource,
-			schema:      FromResourceSchema(resSchema),
-			diagnostics: diag,
-		}
+			resSchema, diag := schemaResponse.Schema, schemaResponse.Diagnostics
+			if err := checkDiagsForErrors(diag); err != nil {
+				return nil, diag, fmt.Errorf("Resource %s GetSchema() error: %w", meta.TypeName, err)
+			}
+			return FromResourceSchema(resSchema), diag, nil
+		})
 	}
 
 	return &resources{collection: rs}, nil
@@ -0,0 +1,97 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pfutils
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatherResourcesLoadsSchemasLazily(t *testing.T) {
+	ctx := context.Background()
+	schemaCalls := map[string]int{}
+	prov := &testProvider{resources: []func() resource.Resource{
+		func() resource.Resource { return &testResource{name: "a", schemaCalls: schemaCalls} },
+		func() resource.Resource { return &testResource{name: "b", schemaCalls: schemaCalls} },
+		func() resource.Resource { return &testResource{name: "broken", schemaCalls: schemaCalls} },
+	}}
+
+	rs, err := GatherResources(ctx, prov)
+	require.NoError(t, err)
+	assert.Equal(t, []TypeName{"test_a", "test_b", "test_broken"}, rs.All())
+	assert.True(t, rs.Has("test_a"))
+	assert.False(t, rs.Has("test_c"))
+	assert.Empty(t, schemaCalls, "gathering resources should not compute their schemas")
+
+	for i := 0; i < 2; i++ {
+		s, err := rs.Schema("test_a")
+		require.NoError(t, err)
+		_, hasAttr := s.Attrs()["x"]
+		assert.True(t, hasAttr)
+	}
+	assert.Equal(t, map[string]int{"a": 1}, schemaCalls, "schemas should be computed once on first use")
+
+	_, err = rs.Schema("test_broken")
+	assert.ErrorContains(t, err, "Resource test_broken GetSchema() error")
+	assert.True(t, rs.Diagnostics("test_broken").HasError())
+
+	_, err = rs.Schema("test_c")
+	assert.ErrorContains(t, err, "unknown type name: test_c")
+}
+
+type testProvider struct {
+	provider.Provider
+	resources []func() resource.Resource
+}
+
+func (p *testProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "test"
+}
+
+func (p *testProvider) Resources(context.Context) []func() resource.Resource {
+	return p.resources
+}
+
+func (p *testProvider) DataSources(context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+type testResource struct {
+	resource.Resource
+	name        string
+	schemaCalls map[string]int
+}
+
+func (r *testResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + r.name
+}
+
+func (r *testResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	r.schemaCalls[r.name]++
+	if r.name == "broken" {
+		resp.Diagnostics.AddError("broken schema", "the schema of this resource is broken")
+		return
+	}
+	resp.Schema = rschema.Schema{Attributes: map[string]rschema.Attribute{
+		"x": rschema.StringAttribute{Optional: true},
+	}}
+}
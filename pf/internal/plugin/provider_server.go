@@ -27,6 +27,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	pl "github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil/rpcerror"
 	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
 
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
@@ -348,8 +349,11 @@ func (p *providerServer) Create(ctx context.Context, req *pulumirpc.CreateReques
 		return nil, err
 	}
 
-	id, state, _, err := p.provider.CreateWithContext(ctx, urn, inputs, req.GetTimeout(), req.GetPreview())
+	id, state, st, err := p.provider.CreateWithContext(ctx, urn, inputs, req.GetTimeout(), req.GetPreview())
 	if err != nil {
+		if st == resource.StatusPartialFailure && state != nil {
+			return nil, p.partialFailure(id, state, err)
+		}
 		return nil, err
 	}
 
@@ -364,6 +368,20 @@ func (p *providerServer) Create(ctx context.Context, req *pulumirpc.CreateReques
 	}, nil
 }
 
+// partialFailure reports err along with the state of a resource that was created or updated despite err, so that
+// the engine records the state.
+func (p *providerServer) partialFailure(id resource.ID, state resource.PropertyMap, err error) error {
+	rpcState, merr := pl.MarshalProperties(state, p.marshalOptions("newState"))
+	if merr != nil {
+		return err
+	}
+	return rpcerror.WithDetails(rpcerror.New(codes.Unknown, err.Error()), &pulumirpc.ErrorResourceInitFailed{
+		Id:         string(id),
+		Properties: rpcState,
+		Reasons:    []string{err.Error()},
+	})
+}
+
 func (p *providerServer) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
 	urn, requestID := resource.URN(req.GetUrn()), resource.ID(req.GetId())
 
@@ -412,9 +430,12 @@ func (p *providerServer) Update(ctx context.Context, req *pulumirpc.UpdateReques
 		return nil, err
 	}
 
-	newState, _, err := p.provider.UpdateWithContext(ctx, urn, id, state, inputs, req.GetTimeout(), req.GetIgnoreChanges(),
-		req.GetPreview())
+	newState, st, err := p.provider.UpdateWithContext(ctx, urn, id, state, inputs, req.GetTimeout(),
+		req.GetIgnoreChanges(), req.GetPreview())
 	if err != nil {
+		if st == resource.StatusPartialFailure && newState != nil {
+			return nil, p.partialFailure(id, newState, err)
+		}
 		return nil, err
 	}
 
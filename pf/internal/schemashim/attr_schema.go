@@ -29,7 +29,7 @@ type attrSchema struct {
 	attr pfutils.Attr
 }
 
-var _ shim.Schema = (*attrSchema)(nil)
+var _ shim.SchemaWithWriteOnly = (*attrSchema)(nil)
 
 func (s *attrSchema) Type() shim.ValueType {
 	ty := s.attr.GetType()
@@ -67,6 +67,10 @@ func (s *attrSchema) Description() string {
 	return s.attr.GetDescription()
 }
 
+func (s *attrSchema) WriteOnly() bool {
+	return s.attr.IsWriteOnly()
+}
+
 func (s *attrSchema) Computed() bool {
 	return s.attr.IsComputed()
 }
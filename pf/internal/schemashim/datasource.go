@@ -20,13 +20,23 @@ import (
 )
 
 type schemaOnlyDataSource struct {
-	tf pfutils.Schema
+	name        pfutils.TypeName
+	dataSources pfutils.DataSources
 }
 
 var _ shim.Resource = (*schemaOnlyDataSource)(nil)
 
+// tf computes the schema of the data source on first use, see schemaOnlyResource.tf.
+func (r *schemaOnlyDataSource) tf() pfutils.Schema {
+	s, err := r.dataSources.Schema(r.name)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
 func (r *schemaOnlyDataSource) Schema() shim.SchemaMap {
-	return newSchemaMap(r.tf)
+	return newSchemaMap(r.tf())
 }
 
 func (*schemaOnlyDataSource) SchemaVersion() int {
@@ -34,7 +44,7 @@ func (*schemaOnlyDataSource) SchemaVersion() int {
 }
 
 func (r *schemaOnlyDataSource) DeprecationMessage() string {
-	return r.tf.DeprecationMessage()
+	return r.tf().DeprecationMessage()
 }
 
 func (*schemaOnlyDataSource) Importer() shim.ImportFunc {
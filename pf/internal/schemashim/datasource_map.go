@@ -29,9 +29,9 @@ func (m *schemaOnlyDataSourceMap) Len() int {
 	return len(m.dataSources.All())
 }
 
+// Get returns the data source without computing its schema, which is only computed when first used.
 func (m *schemaOnlyDataSourceMap) Get(key string) shim.Resource {
-	s := m.dataSources.Schema(pfutils.TypeName(key))
-	return &schemaOnlyDataSource{s}
+	return &schemaOnlyDataSource{name: pfutils.TypeName(key), dataSources: m.dataSources}
 }
 
 func (m *schemaOnlyDataSourceMap) GetOk(key string) (shim.Resource, bool) {
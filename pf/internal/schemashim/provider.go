@@ -16,6 +16,9 @@ package schemashim
 
 import (
 	"context"
+	"errors"
+	"sync"
+
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 
 	pfprovider "github.com/hashicorp/terraform-plugin-framework/provider"
@@ -23,8 +26,18 @@ import (
 )
 
 type SchemaOnlyProvider struct {
-	ctx context.Context
-	tf  pfprovider.Provider
+	ctx   context.Context
+	tf    pfprovider.Provider
+	index *providerIndex // caches the indexed resources and data sources, if not nil
+}
+
+type providerIndex struct {
+	resourcesOnce   sync.Once
+	resources       pfutils.Resources
+	resourcesErr    error
+	dataSourcesOnce sync.Once
+	dataSources     pfutils.DataSources
+	dataSourcesErr  error
 }
 
 func (p *SchemaOnlyProvider) PfProvider() pfprovider.Provider {
@@ -43,8 +56,55 @@ func (p *SchemaOnlyProvider) Schema() shim.SchemaMap {
 	return newSchemaMap(pfutils.FromProviderSchema(schemaResp.Schema))
 }
 
+// Resources indexes the resources of the provider. The index is computed once and shared by all callers.
+func (p *SchemaOnlyProvider) Resources() (pfutils.Resources, error) {
+	if p.index == nil {
+		return pfutils.GatherResources(context.TODO(), p.tf)
+	}
+	p.index.resourcesOnce.Do(func() {
+		p.index.resources, p.index.resourcesErr = pfutils.GatherResources(context.TODO(), p.tf)
+	})
+	return p.index.resources, p.index.resourcesErr
+}
+
+// DataSources indexes the data sources of the provider. The index is computed once and shared by all callers.
+func (p *SchemaOnlyProvider) DataSources() (pfutils.DataSources, error) {
+	if p.index == nil {
+		return pfutils.GatherDatasources(context.TODO(), p.tf)
+	}
+	p.index.dataSourcesOnce.Do(func() {
+		p.index.dataSources, p.index.dataSourcesErr = pfutils.GatherDatasources(context.TODO(), p.tf)
+	})
+	return p.index.dataSources, p.index.dataSourcesErr
+}
+
+// CheckSchemas computes the schemas of all the resources and data sources of the provider and reports the errors.
+// Schemas are otherwise computed on first use, so this is how schema generation surfaces them all upfront.
+func (p *SchemaOnlyProvider) CheckSchemas() error {
+	resources, err := p.Resources()
+	if err != nil {
+		return err
+	}
+	dataSources, err := p.DataSources()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, name := range resources.All() {
+		if _, err := resources.Schema(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, name := range dataSources.All() {
+		if _, err := dataSources.Schema(name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (p *SchemaOnlyProvider) ResourcesMap() shim.ResourceMap {
-	resources, err := pfutils.GatherResources(context.TODO(), p.tf)
+	resources, err := p.Resources()
 	if err != nil {
 		panic(err)
 	}
@@ -52,7 +112,7 @@ func (p *SchemaOnlyProvider) ResourcesMap() shim.ResourceMap {
 }
 
 func (p *SchemaOnlyProvider) DataSourcesMap() shim.ResourceMap {
-	dataSources, err := pfutils.GatherDatasources(context.TODO(), p.tf)
+	dataSources, err := p.DataSources()
 	if err != nil {
 		panic(err)
 	}
@@ -20,13 +20,24 @@ import (
 )
 
 type schemaOnlyResource struct {
-	tf pfutils.Schema
+	name      pfutils.TypeName
+	resources pfutils.Resources
 }
 
 var _ shim.Resource = (*schemaOnlyResource)(nil)
 
+// tf computes the schema of the resource on first use. Runtime operations check for schema errors with
+// pfutils.Resources before using the resource, so the panic is only reachable while generating the schema.
+func (r *schemaOnlyResource) tf() pfutils.Schema {
+	s, err := r.resources.Schema(r.name)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
 func (r *schemaOnlyResource) Schema() shim.SchemaMap {
-	return newSchemaMap(r.tf)
+	return newSchemaMap(r.tf())
 }
 
 func (*schemaOnlyResource) SchemaVersion() int {
@@ -34,7 +45,7 @@ func (*schemaOnlyResource) SchemaVersion() int {
 }
 
 func (r *schemaOnlyResource) DeprecationMessage() string {
-	return r.tf.DeprecationMessage()
+	return r.tf().DeprecationMessage()
 }
 
 func (*schemaOnlyResource) Importer() shim.ImportFunc {
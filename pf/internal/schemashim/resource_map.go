@@ -29,10 +29,9 @@ func (m *schemaOnlyResourceMap) Len() int {
 	return len(m.resources.All())
 }
 
+// Get returns the resource without computing its schema, which is only computed when first used.
 func (m *schemaOnlyResourceMap) Get(key string) shim.Resource {
-	n := pfutils.TypeName(key)
-	s := m.resources.Schema(n)
-	return &schemaOnlyResource{s}
+	return &schemaOnlyResource{name: pfutils.TypeName(key), resources: m.resources}
 }
 
 func (m *schemaOnlyResourceMap) GetOk(key string) (shim.Resource, bool) {
@@ -23,7 +23,8 @@ import (
 
 func ShimSchemaOnlyProvider(ctx context.Context, provider pfprovider.Provider) shim.Provider {
 	return &SchemaOnlyProvider{
-		ctx: ctx,
-		tf:  provider,
+		ctx:   ctx,
+		tf:    provider,
+		index: &providerIndex{},
 	}
 }
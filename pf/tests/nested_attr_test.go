@@ -33,7 +33,9 @@ func TestNestedType(t *testing.T) {
 	res, err := pfutils.GatherResources(ctx, info.P.(*schemashim.SchemaOnlyProvider).PfProvider())
 	require.NoError(t, err)
 	testresTypeName := pfutils.TypeName("testbridge_testres")
-	testresType := res.Schema(testresTypeName).Type().TerraformType(ctx)
+	testresSchema, err := res.Schema(testresTypeName)
+	require.NoError(t, err)
+	testresType := testresSchema.Type().TerraformType(ctx)
 
 	obj := testresType.(tftypes.Object).AttributeTypes["services"].(tftypes.List).ElementType.(tftypes.Object)
 	assert.True(t, obj.AttributeTypes["protocol"].Is(tftypes.String))
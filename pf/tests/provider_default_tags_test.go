@@ -0,0 +1,140 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridgetests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	pschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi-terraform-bridge/pf/bridgetest"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+func TestDefaultTags(t *testing.T) {
+	t.Parallel()
+
+	var upstreamTags types.Map
+	captureTags := func(ctx context.Context, plan tfsdk.Plan, state *tfsdk.State) diag.Diagnostics {
+		state.Raw = plan.Raw
+		diags := plan.GetAttribute(ctx, path.Root("tags"), &upstreamTags)
+		return append(diags, state.SetAttribute(ctx, path.Root("id"), types.StringValue("id-1"))...)
+	}
+
+	h := bridgetest.NewPF(t, &bridgetest.Provider{
+		TypeName: "test",
+		ProviderSchema: pschema.Schema{
+			Attributes: map[string]pschema.Attribute{
+				"default_tags": pschema.MapAttribute{ElementType: types.StringType, Optional: true},
+			},
+		},
+		AllResources: []bridgetest.Resource{{
+			Name: "res",
+			ResourceSchema: rschema.Schema{
+				Attributes: map[string]rschema.Attribute{
+					"id": rschema.StringAttribute{
+						Computed: true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"tags": rschema.MapAttribute{ElementType: types.StringType, Optional: true},
+				},
+			},
+			CreateFunc: func(ctx context.Context, req fwresource.CreateRequest, resp *fwresource.CreateResponse) {
+				resp.Diagnostics.Append(captureTags(ctx, req.Plan, &resp.State)...)
+			},
+			UpdateFunc: func(ctx context.Context, req fwresource.UpdateRequest, resp *fwresource.UpdateResponse) {
+				resp.Diagnostics.Append(captureTags(ctx, req.Plan, &resp.State)...)
+			},
+		}},
+	}, tfbridge.ProviderInfo{
+		Name: "test",
+		DefaultTags: &tfbridge.DefaultTagsInfo{
+			ConfigKey: "defaultTags",
+			Resources: map[string]*tfbridge.ResourceTagsInfo{"test_res": {}},
+		},
+	})
+
+	tok := "test:index/res:Res"
+	configure := func(team string) {
+		h.Configure(resource.PropertyMap{
+			"defaultTags": resource.NewObjectProperty(resource.PropertyMap{
+				"env":  resource.NewStringProperty("dev"),
+				"team": resource.NewStringProperty(team),
+			}),
+		})
+	}
+	tags := func(kvs ...string) resource.PropertyValue {
+		m := resource.PropertyMap{}
+		for i := 0; i < len(kvs); i += 2 {
+			m[resource.PropertyKey(kvs[i])] = resource.NewStringProperty(kvs[i+1])
+		}
+		return resource.NewObjectProperty(m)
+	}
+	upstream := func() map[string]string {
+		m := map[string]string{}
+		for k, v := range upstreamTags.Elements() {
+			m[k] = v.(types.String).ValueString()
+		}
+		return m
+	}
+
+	configure("infra")
+	resourceTags := tags("env", "prod")
+
+	inputs, failures := h.Check(tok, nil, resource.PropertyMap{"tags": resourceTags})
+	assert.Empty(t, failures)
+	assert.Equal(t, resourceTags, inputs["tags"], "Check keeps the default tags out of tags")
+
+	id, outputs := h.Create(tok, inputs)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "infra"}, upstream())
+	assert.Equal(t, resourceTags, outputs["tags"])
+	assert.Equal(t, tags("env", "prod", "team", "infra"), outputs["tagsAll"])
+
+	diff := h.Diff(tok, id, outputs, inputs)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_NONE, diff.GetChanges())
+
+	read, readInputs := h.Read(tok, id, outputs)
+	assert.Equal(t, resourceTags, read["tags"])
+	assert.Equal(t, tags("env", "prod", "team", "infra"), read["tagsAll"])
+	assert.NotContains(t, readInputs, resource.PropertyKey("tagsAll"))
+
+	configure("platform")
+	diff = h.Diff(tok, id, outputs, inputs)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, diff.GetChanges())
+	assert.Equal(t, []string{"tagsAll"}, diff.GetDiffs(), "default-only changes are reported on tagsAll")
+
+	updated := h.Update(tok, id, outputs, inputs)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "platform"}, upstream())
+	assert.Equal(t, resourceTags, updated["tags"])
+	assert.Equal(t, tags("env", "prod", "team", "platform"), updated["tagsAll"])
+
+	news := resource.PropertyMap{"tags": tags("env", "staging")}
+	diff = h.Diff(tok, id, updated, news)
+	assert.Equal(t, []string{"tags"}, diff.GetDiffs())
+}
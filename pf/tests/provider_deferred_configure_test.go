@@ -0,0 +1,111 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridgetests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	pschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/pf/bridgetest"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+func TestDeferConfigureWithUnknowns(t *testing.T) {
+	t.Parallel()
+
+	var configureCalls int
+	h := bridgetest.NewPF(t, &bridgetest.Provider{
+		TypeName: "test",
+		ProviderSchema: pschema.Schema{
+			Attributes: map[string]pschema.Attribute{
+				"endpoint": pschema.StringAttribute{Optional: true},
+			},
+		},
+		ConfigureFunc: func(context.Context, provider.ConfigureRequest, *provider.ConfigureResponse) {
+			configureCalls++
+		},
+		AllResources: []bridgetest.Resource{{
+			Name: "res",
+			ResourceSchema: rschema.Schema{
+				Attributes: map[string]rschema.Attribute{
+					"id": rschema.StringAttribute{Computed: true},
+					"name": rschema.StringAttribute{
+						Required: true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.RequiresReplace(),
+						},
+					},
+					"endpoint": rschema.StringAttribute{Computed: true},
+				},
+			},
+		}},
+	}, tfbridge.ProviderInfo{
+		Name:                       "test",
+		DeferConfigureWithUnknowns: true,
+	})
+
+	tok := "test:index/res:Res"
+	unknown := resource.MakeComputed(resource.NewStringProperty(""))
+	h.Configure(resource.PropertyMap{"endpoint": unknown})
+	assert.Zero(t, configureCalls)
+
+	opts := plugin.MarshalOptions{KeepUnknowns: true}
+	props, err := plugin.MarshalProperties(resource.PropertyMap{"name": resource.NewStringProperty("n")}, opts)
+	require.NoError(t, err)
+
+	resp, err := h.Server().Create(context.Background(), &pulumirpc.CreateRequest{
+		Urn:        string(h.URN(tok)),
+		Properties: props,
+		Preview:    true,
+	})
+	require.NoError(t, err)
+	outputs, err := plugin.UnmarshalProperties(resp.GetProperties(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, resource.PropertyMap{
+		"id":       unknown,
+		"name":     resource.NewStringProperty("n"),
+		"endpoint": unknown,
+	}, outputs)
+
+	_, err = h.Server().Create(context.Background(), &pulumirpc.CreateRequest{
+		Urn:        string(h.URN(tok)),
+		Properties: props,
+	})
+	assert.ErrorContains(t, err, tfbridge.ErrConfigurationUnknown.Error())
+
+	diff := h.Diff(tok, "id-1", resource.PropertyMap{
+		"id":       resource.NewStringProperty("id-1"),
+		"name":     resource.NewStringProperty("n"),
+		"endpoint": resource.NewStringProperty("e"),
+	}, resource.PropertyMap{"name": resource.NewStringProperty("m")})
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, diff.GetChanges())
+	assert.Equal(t, []string{"name"}, diff.GetDiffs())
+	// RequiresReplace plan modifiers are not visible in the schema, so the change is reported as an update.
+	assert.Empty(t, diff.GetReplaces())
+
+	h.Configure(resource.PropertyMap{"endpoint": resource.NewStringProperty("https://example.com")})
+	assert.Equal(t, 1, configureCalls)
+}
@@ -1,11 +1,20 @@
 package tfbridgetests
 
 import (
+	"context"
 	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+
 	"github.com/pulumi/pulumi-terraform-bridge/pf/tests/internal/testprovider"
 	"github.com/pulumi/pulumi-terraform-bridge/pf/tests/internal/testprovider/sdkv2randomprovider"
+	tfpf "github.com/pulumi/pulumi-terraform-bridge/pf/tfbridge"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
-	"testing"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge/tokens"
 )
 
 // Benchmark tests based on init of an example large provider
@@ -60,3 +69,68 @@ func BenchmarkLoadMetadata(b *testing.B) {
 		tfbridge.NewProviderMetadata(testprovider.BigMetadata)
 	}
 }
+
+// BenchmarkSizedPFProviderInit measures the startup of a Plugin Framework provider with many resources: shimming
+// the provider, computing the tokens of its resources and building the runtime provider.
+func BenchmarkSizedPFProviderInit(b *testing.B) {
+	for _, size := range []int{100, 10000} {
+		b.Run(fmt.Sprintf("resources_size_%d", size), func(b *testing.B) {
+			ctx := context.Background()
+			prov := &sizedPFProvider{size: size}
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				info := tfbridge.ProviderInfo{
+					Name:         "sized",
+					Version:      "0.0.1",
+					P:            tfpf.ShimProvider(prov),
+					MetadataInfo: tfbridge.NewProviderMetadata(nil),
+				}
+				info.MustComputeTokens(tokens.SingleModule("sized_", "index", tokens.MakeStandard("sized")))
+				if _, err := tfpf.NewProvider(ctx, info, tfpf.ProviderMetadata{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// sizedPFProvider is a Plugin Framework provider with size resources, whose schemas are built on demand like those
+// of real providers.
+type sizedPFProvider struct {
+	provider.Provider
+	size int
+}
+
+func (p *sizedPFProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "sized"
+}
+
+func (p *sizedPFProvider) Schema(context.Context, provider.SchemaRequest, *provider.SchemaResponse) {}
+
+func (p *sizedPFProvider) DataSources(context.Context) []func() datasource.DataSource { return nil }
+
+func (p *sizedPFProvider) Resources(context.Context) []func() resource.Resource {
+	rs := make([]func() resource.Resource, p.size)
+	for i := range rs {
+		i := i
+		rs[i] = func() resource.Resource { return &sizedPFResource{index: i} }
+	}
+	return rs
+}
+
+type sizedPFResource struct {
+	resource.Resource
+	index int
+}
+
+func (r *sizedPFResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_res%d", req.ProviderTypeName, r.index)
+}
+
+func (r *sizedPFResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attrs := map[string]rschema.Attribute{"id": rschema.StringAttribute{Computed: true}}
+	for i := 0; i < 50; i++ {
+		attrs[fmt.Sprintf("attr%d", i)] = rschema.StringAttribute{Optional: true}
+	}
+	resp.Schema = rschema.Schema{Attributes: attrs}
+}
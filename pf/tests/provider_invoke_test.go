@@ -15,10 +15,14 @@
 package tfbridgetests
 
 import (
+	"context"
 	"testing"
 
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
 	testutils "github.com/pulumi/providertest/replay"
 	"github.com/pulumi/pulumi-terraform-bridge/pf/tests/internal/testprovider"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 )
 
 func TestBasicInvoke(t *testing.T) {
@@ -86,3 +90,41 @@ func TestInvokeWithInvalidData(t *testing.T) {
         `
 	testutils.Replay(t, server, testCase)
 }
+
+func TestInvokeHooks(t *testing.T) {
+	p := testprovider.SyntheticTestBridgeProvider()
+	p.DataSources["testbridge_echo"] = &tfbridge.DataSourceInfo{
+		Tok: "testbridge:index/echo:Echo",
+		PreInvokeCallback: func(
+			_ context.Context, args, _ resource.PropertyMap,
+		) (resource.PropertyMap, error) {
+			args = args.Copy()
+			args["input"] = resource.NewStringProperty("pre-" + args["input"].StringValue())
+			return args, nil
+		},
+		TransformOutputs: func(_ context.Context, pm resource.PropertyMap) (resource.PropertyMap, error) {
+			pm = pm.Copy()
+			pm["output"] = resource.NewStringProperty(pm["output"].StringValue() + "-post")
+			return pm, nil
+		},
+	}
+	server := newProviderServer(t, p)
+
+	testutils.Replay(t, server, `
+	{
+	  "method": "/pulumirpc.ResourceProvider/Invoke",
+	  "request": {
+	    "tok": "testbridge:index/echo:Echo",
+	    "args": {
+	      "input": "hello"
+	    }
+	  },
+	  "response": {
+	    "return": {
+	      "input": "pre-hello",
+	      "output": "pre-hello-post",
+	      "sensitive": "*"
+	    }
+	  }
+	}`)
+}
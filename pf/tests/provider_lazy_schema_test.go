@@ -0,0 +1,104 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridgetests
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/pulumi/pulumi-terraform-bridge/pf/bridgetest"
+	tfpf "github.com/pulumi/pulumi-terraform-bridge/pf/tfbridge"
+	pftfgen "github.com/pulumi/pulumi-terraform-bridge/pf/tfgen"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+// Schemas are computed on first use: a broken schema fails schema generation, and at runtime only fails the
+// operations on its own resource.
+func TestSchemaErrorsAreReportedOnUse(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	prov := &brokenSchemaProvider{Provider: bridgetest.Provider{
+		TypeName: "test",
+		AllResources: []bridgetest.Resource{{
+			Name: "ok",
+			ResourceSchema: rschema.Schema{Attributes: map[string]rschema.Attribute{
+				"id": rschema.StringAttribute{Computed: true},
+			}},
+		}},
+	}}
+	info := tfbridge.ProviderInfo{
+		Name:         "test",
+		Version:      "0.0.1",
+		P:            tfpf.ShimProvider(prov),
+		MetadataInfo: tfbridge.NewProviderMetadata(nil),
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"test_ok":     {Tok: "test:index:Ok"},
+			"test_broken": {Tok: "test:index:Broken"},
+		},
+	}
+
+	_, err := pftfgen.GenerateSchema(ctx, pftfgen.GenerateSchemaOptions{
+		ProviderInfo:    info,
+		DiagnosticsSink: diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never}),
+	})
+	assert.ErrorContains(t, err, "Resource test_broken GetSchema() error")
+
+	server, err := tfpf.NewProviderServer(ctx, nil, info, tfpf.ProviderMetadata{})
+	require.NoError(t, err)
+
+	check := func(tok string) error {
+		_, err := server.Check(ctx, &pulumirpc.CheckRequest{
+			Urn:  "urn:pulumi:dev::proj::" + tok + "::r",
+			Olds: &structpb.Struct{},
+			News: &structpb.Struct{},
+		})
+		return err
+	}
+	assert.NoError(t, check("test:index:Ok"))
+	assert.ErrorContains(t, check("test:index:Broken"), "Resource test_broken GetSchema() error")
+}
+
+type brokenSchemaProvider struct {
+	bridgetest.Provider
+}
+
+func (p *brokenSchemaProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return append(p.Provider.Resources(ctx), func() resource.Resource { return &brokenSchemaResource{} })
+}
+
+type brokenSchemaResource struct {
+	bridgetest.Resource
+}
+
+func (r *brokenSchemaResource) Metadata(
+	_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse,
+) {
+	resp.TypeName = req.ProviderTypeName + "_broken"
+}
+
+func (r *brokenSchemaResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Diagnostics.AddError("broken schema", "the schema of this resource is broken")
+}
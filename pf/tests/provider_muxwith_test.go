@@ -0,0 +1,137 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridgetests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	testutils "github.com/pulumi/providertest/replay"
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	pfmuxer "github.com/pulumi/pulumi-terraform-bridge/pf/internal/muxer"
+	"github.com/pulumi/pulumi-terraform-bridge/pf/tests/internal/testprovider"
+	"github.com/pulumi/pulumi-terraform-bridge/pf/tfbridge"
+	tfbridge0 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/metadata"
+)
+
+func TestMuxedMuxWith(t *testing.T) {
+	ctx := context.Background()
+	info := testprovider.MuxedRandomProvider()
+
+	mixin := &mixinProvider{spec: pschema.PackageSpec{
+		Name: "muxedrandom",
+		Resources: map[string]pschema.ResourceSpec{
+			"muxedrandom:index:Mixin": {},
+		},
+	}}
+
+	// Lay out the schema and dispatch table as pf/tfgen.MainWithMuxer does.
+	var spec pschema.PackageSpec
+	require.NoError(t, json.Unmarshal(genSDKSchema(t, info), &spec))
+	shim := info.P.(*pfmuxer.ProviderShim)
+	dispatch, err := shim.ResolveDispatch(&info)
+	require.NoError(t, err)
+	mixinSpec, err := mixin.GetSpec(ctx, "muxedrandom", info.Version)
+	require.NoError(t, err)
+	merged, table, err := shim.MergeMuxWith([]pschema.PackageSpec{spec, mixinSpec}, dispatch)
+	require.NoError(t, err)
+	require.NoError(t, metadata.Set(info.GetMetadata(), "mux", table))
+	assert.Equal(t, len(shim.MuxedProviders), table.Resources["muxedrandom:index:Mixin"])
+	assert.Contains(t, merged.Resources, "muxedrandom:index/randomInteger:RandomInteger")
+
+	schema, err := json.Marshal(merged)
+	require.NoError(t, err)
+
+	info.MuxWith = []tfbridge0.MuxProvider{mixin}
+	server, err := tfbridge.MakeMuxedServer(ctx, "muxedrandom", info, schema)(nil)
+	require.NoError(t, err)
+
+	t.Run("mixin", func(t *testing.T) {
+		testutils.Replay(t, server, `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Create",
+		  "request": {
+		    "urn": "urn:pulumi:dev::test::muxedrandom:index:Mixin::m",
+		    "properties": {}
+		  },
+		  "response": {
+		    "id": "mixin-id",
+		    "properties": {
+		      "fromMixin": true
+		    }
+		  }
+		}`)
+	})
+
+	t.Run("pf", func(t *testing.T) {
+		testutils.Replay(t, server, `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Create",
+		  "request": {
+		    "urn": "urn:pulumi:dev::test::muxedrandom:index/randomInteger:RandomInteger::r",
+		    "properties": {
+		      "min": 1,
+		      "max": 1
+		    },
+		    "preview": false
+		  },
+		  "response": {
+		    "id": "1",
+		    "properties": {
+		      "id": "1",
+		      "min": 1,
+		      "max": 1,
+		      "result": 1
+		    }
+		  }
+		}`)
+	})
+}
+
+type mixinProvider struct {
+	spec pschema.PackageSpec
+}
+
+func (m *mixinProvider) GetSpec(context.Context, string, string) (pschema.PackageSpec, error) {
+	return m.spec, nil
+}
+
+func (m *mixinProvider) GetInstance(
+	context.Context, string, string, *provider.HostClient,
+) (pulumirpc.ResourceProviderServer, error) {
+	return &mixinServer{}, nil
+}
+
+type mixinServer struct {
+	pulumirpc.UnimplementedResourceProviderServer
+}
+
+func (*mixinServer) Create(
+	context.Context, *pulumirpc.CreateRequest,
+) (*pulumirpc.CreateResponse, error) {
+	props, err := structpb.NewStruct(map[string]interface{}{"fromMixin": true})
+	if err != nil {
+		return nil, err
+	}
+	return &pulumirpc.CreateResponse{Id: "mixin-id", Properties: props}, nil
+}
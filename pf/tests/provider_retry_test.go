@@ -0,0 +1,123 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridgetests
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/status"
+
+	"github.com/pulumi/pulumi-terraform-bridge/pf/bridgetest"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+func TestRetryCreate(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	h := bridgetest.NewPF(t, &bridgetest.Provider{
+		TypeName: "test",
+		AllResources: []bridgetest.Resource{{
+			Name: "res",
+			ResourceSchema: rschema.Schema{
+				Attributes: map[string]rschema.Attribute{
+					"id":   rschema.StringAttribute{Computed: true},
+					"name": rschema.StringAttribute{Optional: true},
+				},
+			},
+			CreateFunc: func(ctx context.Context, req fwresource.CreateRequest, resp *fwresource.CreateResponse) {
+				calls++
+				if calls == 1 {
+					resp.Diagnostics.AddError("creating", "role is not yet consistent")
+					return
+				}
+				resp.State.Raw = req.Plan.Raw
+				resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), types.StringValue("id-1"))...)
+			},
+		}},
+	}, tfbridge.ProviderInfo{
+		Name: "test",
+		RetryPolicy: &tfbridge.RetryPolicy{
+			ErrorMessages:  []*regexp.Regexp{regexp.MustCompile("not yet consistent")},
+			InitialBackoff: time.Millisecond,
+		},
+	})
+
+	h.Configure(resource.PropertyMap{})
+	id, _ := h.Create("test:index/res:Res", resource.PropertyMap{"name": resource.NewStringProperty("n")})
+	assert.Equal(t, "id-1", id)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetryCreateWithPartialState(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	h := bridgetest.NewPF(t, &bridgetest.Provider{
+		TypeName: "test",
+		AllResources: []bridgetest.Resource{{
+			Name: "res",
+			ResourceSchema: rschema.Schema{
+				Attributes: map[string]rschema.Attribute{
+					"id":   rschema.StringAttribute{Computed: true},
+					"name": rschema.StringAttribute{Optional: true},
+				},
+			},
+			CreateFunc: func(ctx context.Context, req fwresource.CreateRequest, resp *fwresource.CreateResponse) {
+				calls++
+				resp.State.Raw = req.Plan.Raw
+				resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), types.StringValue("id-1"))...)
+				resp.Diagnostics.AddError("tagging", "role is not yet consistent")
+			},
+		}},
+	}, tfbridge.ProviderInfo{
+		Name: "test",
+		RetryPolicy: &tfbridge.RetryPolicy{
+			ErrorMessages:  []*regexp.Regexp{regexp.MustCompile("not yet consistent")},
+			InitialBackoff: time.Millisecond,
+		},
+	})
+
+	h.Configure(resource.PropertyMap{})
+	props, err := plugin.MarshalProperties(resource.PropertyMap{"name": resource.NewStringProperty("n")},
+		plugin.MarshalOptions{})
+	require.NoError(t, err)
+	_, err = h.Server().Create(context.Background(), &pulumirpc.CreateRequest{
+		Urn:        string(h.URN("test:index/res:Res")),
+		Properties: props,
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, calls, "a Create that returned a resource must not be retried")
+
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	initErr, ok := details[0].(*pulumirpc.ErrorResourceInitFailed)
+	require.True(t, ok)
+	assert.Equal(t, "id-1", initErr.GetId())
+	assert.Equal(t, "n", initErr.GetProperties().GetFields()["name"].GetStringValue())
+	assert.Contains(t, initErr.GetReasons()[0], "not yet consistent")
+}
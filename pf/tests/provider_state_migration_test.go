@@ -0,0 +1,180 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridgetests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutils "github.com/pulumi/providertest/replay"
+	"github.com/pulumi/pulumi-terraform-bridge/pf/tests/internal/testprovider"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+)
+
+func TestStateMigrations(t *testing.T) {
+	provider := func(t *testing.T) pulumirpc.ResourceProviderServer {
+		p := testprovider.AssertProvider(func(config tfsdk.Config, old, new *tfsdk.State) {
+			ctx := context.Background()
+			path := path.Root("string_property_value")
+			if raw := old.Raw; !raw.IsNull() {
+				var s string
+				old.GetAttribute(ctx, path, &s)
+				assert.Equal(t, "MIGRATED", s)
+			}
+			err := new.SetAttribute(ctx, path, "SET")
+			require.Zero(t, err)
+		})
+
+		p.Resources["assert_echo"] = &tfbridge.ResourceInfo{
+			Tok: "assert:index/echo:Echo",
+			StateMigrations: []tfbridge.StateMigration{{
+				Description: "rename oldStringPropertyValue",
+				Migrate: func(_ context.Context, pm resource.PropertyMap) (resource.PropertyMap, error) {
+					if _, ok := pm["oldStringPropertyValue"]; !ok {
+						return nil, fmt.Errorf("already migrated")
+					}
+					pm["stringPropertyValue"] = pm["oldStringPropertyValue"]
+					delete(pm, "oldStringPropertyValue")
+					return pm, nil
+				},
+			}},
+		}
+
+		return newProviderServer(t, p)
+	}
+
+	t.Run("Read (Refresh)", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::assert:index/echo:Echo::exres",
+		    "properties": {
+		      "oldStringPropertyValue": "MIGRATED"
+		    }
+		  },
+		  "response": {
+		    "id": "0",
+		    "inputs": "*",
+		    "properties": {
+		      "id": "0",
+		      "stringPropertyValue": "SET",
+		      "__meta": "{\"pulumi_schema_version\":\"1\"}"
+		    }
+		  }
+		}`)
+	})
+
+	t.Run("Read (Refresh) migrates the prior inputs", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::assert:index/echo:Echo::exres",
+		    "properties": {
+		      "oldStringPropertyValue": "MIGRATED"
+		    },
+		    "inputs": {
+		      "oldStringPropertyValue": "MIGRATED"
+		    }
+		  },
+		  "response": {
+		    "id": "0",
+		    "inputs": {
+		      "stringPropertyValue": "SET"
+		    },
+		    "properties": "*"
+		  }
+		}`)
+	})
+
+	t.Run("Check does not migrate the prior inputs", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Check",
+		  "request": {
+		    "urn": "urn:pulumi:dev::teststack::assert:index/echo:Echo::exres",
+		    "olds": {
+		      "stringPropertyValue": "OLD"
+		    },
+		    "news": {
+		      "stringPropertyValue": "NEW"
+		    }
+		  },
+		  "response": {
+		    "inputs": {
+		      "stringPropertyValue": "NEW"
+		    }
+		  }
+		}`)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Update",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::assert:index/echo:Echo::exres",
+		    "olds": {
+		      "oldStringPropertyValue": "MIGRATED"
+		    },
+		    "news": {
+		      "stringPropertyValue": "NEW"
+		    }
+		  },
+		  "response": {
+		    "properties": {
+		      "stringPropertyValue": "SET",
+		      "__meta": "{\"pulumi_schema_version\":\"1\"}"
+		    }
+		  }
+		}`)
+	})
+
+	t.Run("Migrated state is not migrated again", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::assert:index/echo:Echo::exres",
+		    "properties": {
+		      "stringPropertyValue": "MIGRATED",
+		      "__meta": "{\"pulumi_schema_version\":\"1\"}"
+		    }
+		  },
+		  "response": {
+		    "id": "0",
+		    "inputs": "*",
+		    "properties": {
+		      "id": "0",
+		      "stringPropertyValue": "SET",
+		      "__meta": "{\"pulumi_schema_version\":\"1\"}"
+		    }
+		  }
+		}`)
+	})
+}
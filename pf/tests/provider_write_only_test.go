@@ -0,0 +1,137 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridgetests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/pulumi/pulumi-terraform-bridge/pf/bridgetest"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/propertyvalue"
+)
+
+func TestWriteOnlyValuesAreNeverStored(t *testing.T) {
+	t.Parallel()
+	const password = "hunter2-write-only"
+
+	var created, updated string
+	h := bridgetest.NewPF(t, &bridgetest.Provider{
+		TypeName: "test",
+		AllResources: []bridgetest.Resource{{
+			Name: "res",
+			ResourceSchema: rschema.Schema{
+				Attributes: map[string]rschema.Attribute{
+					"id": rschema.StringAttribute{
+						Computed: true,
+						PlanModifiers: []planmodifier.String{
+							stringplanmodifier.UseStateForUnknown(),
+						},
+					},
+					"name":     rschema.StringAttribute{Optional: true},
+					"password": rschema.StringAttribute{Optional: true},
+				},
+			},
+			CreateFunc: func(ctx context.Context, req fwresource.CreateRequest, resp *fwresource.CreateResponse) {
+				var p types.String
+				resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("password"), &p)...)
+				created = p.ValueString()
+				resp.State.Raw = req.Plan.Raw
+				resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), types.StringValue("id-1"))...)
+			},
+			UpdateFunc: func(ctx context.Context, req fwresource.UpdateRequest, resp *fwresource.UpdateResponse) {
+				var p types.String
+				resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("password"), &p)...)
+				updated = p.ValueString()
+				resp.State.Raw = req.Plan.Raw
+			},
+		}},
+	}, tfbridge.ProviderInfo{
+		Name: "test",
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"test_res": {
+				Tok:    "test:index/res:Res",
+				Fields: map[string]*tfbridge.SchemaInfo{"password": {WriteOnly: true}},
+			},
+		},
+	})
+	tok := "test:index/res:Res"
+
+	assertNotStored := func(pm resource.PropertyMap) {
+		t.Helper()
+		plain := propertyvalue.RemoveSecrets(resource.NewObjectProperty(pm))
+		assert.NotContains(t, fmt.Sprint(plain), "hunter2")
+	}
+	check := func(name string) resource.PropertyMap {
+		inputs, failures := h.Check(tok, nil, resource.PropertyMap{
+			"name":     resource.NewStringProperty(name),
+			"password": resource.NewStringProperty(password),
+		})
+		assert.Empty(t, failures)
+		assertNotStored(inputs)
+		return inputs
+	}
+
+	id, outputs := h.Create(tok, check("a"))
+	assertNotStored(outputs)
+	assert.Equal(t, password, created, "Create should receive the write-only value")
+
+	inputs := check("b")
+	diff := h.Diff(tok, id, outputs, inputs)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_SOME, diff.GetChanges())
+	assert.Equal(t, []string{"name"}, diff.GetDiffs())
+
+	outputs = h.Update(tok, id, outputs, inputs)
+	assertNotStored(outputs)
+	assert.Equal(t, password, updated, "Update should receive the write-only value")
+
+	read, readInputs := h.Read(tok, id, outputs)
+	assertNotStored(read)
+	assertNotStored(readInputs)
+
+	// Changing only the write-only value does not trigger an update.
+	h.Check(tok, nil, resource.PropertyMap{
+		"name":     resource.NewStringProperty("b"),
+		"password": resource.NewStringProperty("changed"),
+	})
+	diff = h.Diff(tok, id, outputs, inputs)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_NONE, diff.GetChanges())
+
+	// The diff without changes drops the write-only values, so an Update that does not follow a Check in this
+	// process fails instead of sending no value to the provider.
+	marshal := func(pm resource.PropertyMap) *structpb.Struct {
+		m, err := plugin.MarshalProperties(pm, plugin.MarshalOptions{KeepSecrets: true})
+		require.NoError(t, err)
+		return m
+	}
+	_, err := h.Server().Update(context.Background(), &pulumirpc.UpdateRequest{
+		Id: id, Urn: string(h.URN(tok)), Olds: marshal(outputs), News: marshal(inputs),
+	})
+	assert.ErrorContains(t, err, "write-only values of "+string(h.URN(tok))+" are missing")
+}
@@ -30,6 +30,7 @@ import (
 	pfmuxer "github.com/pulumi/pulumi-terraform-bridge/pf/internal/muxer"
 	"github.com/pulumi/pulumi-terraform-bridge/pf/internal/schemashim"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/grpcrecorder"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/metadata"
 	"github.com/pulumi/pulumi-terraform-bridge/x/muxer"
 )
@@ -104,9 +105,6 @@ func handleFlags(
 //
 // This is an experimental API.
 func MainWithMuxer(ctx context.Context, pkg string, info tfbridge.ProviderInfo, schema []byte) {
-	if len(info.MuxWith) > 0 {
-		panic("mixin providers via tfbridge.ProviderInfo.MuxWith is currently not supported")
-	}
 	handleFlags(ctx, info.Version, func() (*tfbridge.MarshallableProviderInfo, error) {
 		info := info
 		return tfbridge.MarshalProviderInfo(&info), nil
@@ -114,7 +112,13 @@ func MainWithMuxer(ctx context.Context, pkg string, info tfbridge.ProviderInfo,
 
 	f := MakeMuxedServer(ctx, pkg, info, schema)
 
-	err := rprovider.Main(pkg, f)
+	err := rprovider.Main(pkg, func(host *rprovider.HostClient) (pulumirpc.ResourceProviderServer, error) {
+		server, err := f(host)
+		if err != nil {
+			return nil, err
+		}
+		return grpcrecorder.FromEnv(server, grpcrecorder.WithSecretConfigKeys(tfbridge.SecretConfigKeys(&info)...))
+	})
 	if err != nil {
 		cmdutil.ExitError(err.Error())
 	}
@@ -179,10 +183,21 @@ func MakeMuxedServer(
 			default:
 				m.Servers = append(m.Servers, muxer.Endpoint{
 					Server: func(host *rprovider.HostClient) (pulumirpc.ResourceProviderServer, error) {
-						return tfbridge.NewProvider(ctx, host, pkg, version, prov, info, schema), nil
+						// MuxWith providers are served by m, not by each muxed provider.
+						infoCopy := info
+						infoCopy.MuxWith = nil
+						return tfbridge.NewProvider(ctx, host, pkg, version, prov, infoCopy, schema), nil
 					}})
 			}
 		}
+		// MuxWith providers follow the muxed providers, as laid out by pf/tfgen.MainWithMuxer.
+		for _, f := range info.MuxWith {
+			f := f
+			m.Servers = append(m.Servers, muxer.Endpoint{
+				Server: func(host *rprovider.HostClient) (pulumirpc.ResourceProviderServer, error) {
+					return f.GetInstance(ctx, pkg, version, host)
+				}})
+		}
 		return m.Server(host, pkg, version)
 	}
 }
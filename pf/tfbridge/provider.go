@@ -17,6 +17,7 @@ package tfbridge
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/blang/semver"
 
@@ -65,6 +66,15 @@ type provider struct {
 	lastKnownProviderConfig resource.PropertyMap
 
 	schemaOnlyProvider shim.Provider
+
+	// Retries and rate-limits calls into tfServer that create, read, update or delete resources.
+	operations *tfbridge.OperationRunner
+
+	// True if ConfigureProvider has not been called yet, see tfbridge.ProviderInfo.DeferConfigureWithUnknowns.
+	configDeferred bool
+
+	// Write-only values kept from Check until Create or Update sends them to the provider.
+	writeOnlyInputs tfbridge.WriteOnlyInputs
 }
 
 var _ pl.ProviderWithContext = &provider{}
@@ -107,12 +117,12 @@ func newProviderWithContext(ctx context.Context, info tfbridge.ProviderInfo,
 	if err != nil {
 		return nil, fmt.Errorf("Fatal failure starting a provider server: %w", err)
 	}
-	resources, err := pfutils.GatherResources(ctx, p)
+	resources, err := schemaOnlyProvider.Resources()
 	if err != nil {
 		return nil, fmt.Errorf("Fatal failure gathering resource metadata: %w", err)
 	}
 
-	datasources, err := pfutils.GatherDatasources(ctx, p)
+	datasources, err := schemaOnlyProvider.DataSources()
 	if err != nil {
 		return nil, fmt.Errorf("Fatal failure gathering datasource metadata: %w", err)
 	}
@@ -156,6 +166,7 @@ func newProviderWithContext(ctx context.Context, info tfbridge.ProviderInfo,
 		version:       semverVersion,
 
 		schemaOnlyProvider: schemaOnlyProvider,
+		operations:         tfbridge.NewOperationRunner(&info),
 	}, nil
 }
 
@@ -249,12 +260,18 @@ func newProviderServer6(ctx context.Context, p pfprovider.Provider) (tfprotov6.P
 	newServer6 := providerserver.NewProtocol6(p)
 	server6 := newServer6()
 
-	// Somehow this GetProviderSchema call needs to happen at least once to avoid Resource Type Not Found in the
+	// Somehow this GetMetadata call needs to happen at least once to avoid Resource Type Not Found in the
 	// tfServer, to init it properly to remember provider name and compute correct resource names like
-	// random_integer instead of _integer (unknown provider name).
-	if _, err := server6.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{}); err != nil {
+	// random_integer instead of _integer (unknown provider name). Unlike GetProviderSchema, it does not compute the
+	// schemas of all the resources, which the server computes on first use.
+	if _, err := server6.GetMetadata(ctx, &tfprotov6.GetMetadataRequest{}); err != nil {
 		return nil, err
 	}
 
 	return server6, nil
 }
+
+// timeoutDuration converts the timeout in seconds of a Pulumi request to a time.Duration, 0 meaning no timeout.
+func timeoutDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -46,6 +46,8 @@ func (p *provider) CheckWithContext(
 		return checkedInputs, []plugin.CheckFailure{}, err
 	}
 
+	// priorState holds the prior inputs, which have no __meta block to tell which StateMigrations they need, so
+	// they are not migrated.
 	priorState, err = transformFromState(ctx, rh, priorState)
 	if err != nil {
 		return checkedInputs, []plugin.CheckFailure{}, err
@@ -81,6 +83,15 @@ func (p *provider) CheckWithContext(
 	schemaInfos := rh.pulumiResourceInfo.GetFields()
 	news = tfbridge.MarkSchemaSecrets(ctx, schemaMap, schemaInfos, resource.NewObjectProperty(news)).ObjectValue()
 
+	// The engine records the checked inputs in the state, so they must not carry write-only values. Failed checks
+	// are never followed by Create or Update, so there is no need to keep the values.
+	if err == nil && len(checkFailures) == 0 {
+		news = p.writeOnlyInputs.Stash(urn, schemaMap, schemaInfos, news)
+	} else {
+		p.writeOnlyInputs.Forget(urn)
+		news = tfbridge.RemoveWriteOnlyValues(schemaMap, schemaInfos, news)
+	}
+
 	if err != nil {
 		return news, checkFailures, err
 	}
@@ -52,14 +52,14 @@ func (p *provider) CheckConfigWithContext(
 ) (resource.PropertyMap, []plugin.CheckFailure, error) {
 	ctx = p.initLogging(ctx, p.logSink, urn)
 
-	inputsText := showMap(inputs)
+	inputsText := p.showConfig(ctx, inputs)
 	checkConfigSpan, ctx := opentracing.StartSpanFromContext(ctx, "pf.CheckConfig",
 		opentracing.Tag{Key: "provider", Value: p.info.Name},
 		opentracing.Tag{Key: "version", Value: p.version.String()},
-		opentracing.Tag{Key: "inputs", Value: inputsText},
 		opentracing.Tag{Key: "urn", Value: string(urn)},
 	)
 	defer checkConfigSpan.Finish()
+	setPayloadTag(checkConfigSpan, "inputs", inputsText)
 
 	// Transform news to apply Pulumi-level defaults.
 	news := defaults.ApplyDefaultInfoValues(ctx, defaults.ApplyDefaultInfoValuesArgs{
@@ -69,9 +69,9 @@ func (p *provider) CheckConfigWithContext(
 		ProviderConfig: inputs,
 	})
 
-	newsText := showMap(news)
+	newsText := p.showConfig(ctx, news)
 	if newsText != inputsText {
-		checkConfigSpan.SetTag("inputsWithPulumiDefaults", newsText)
+		setPayloadTag(checkConfigSpan, "inputsWithPulumiDefaults", newsText)
 	}
 
 	// It is currently a breaking change to call PreConfigureCallback with unknown values. The user code does not
@@ -95,8 +95,8 @@ func (p *provider) CheckConfigWithContext(
 		}
 	}
 
-	if n := showMap(news); n != newsText {
-		checkConfigSpan.SetTag("inputsAfterCallbacks", n)
+	if n := p.showConfig(ctx, news); n != newsText {
+		setPayloadTag(checkConfigSpan, "inputsAfterCallbacks", n)
 	}
 
 	// Store for use in subsequent ApplyDefaultInfoValues.
@@ -112,11 +112,18 @@ func (p *provider) CheckConfigWithContext(
 		}
 	}
 
+	if err := tfbridge.CheckAutonamingConfig(news); err != nil {
+		checkFailures = append(checkFailures, plugin.CheckFailure{
+			Property: tfbridge.AutonamingConfigKey,
+			Reason:   err.Error(),
+		})
+	}
+
 	// Ensure properties marked secret in the schema have secret values.
 	secretNews := tfbridge.MarkSchemaSecrets(ctx, p.schemaOnlyProvider.Schema(), p.info.Config,
 		resource.NewObjectProperty(news)).ObjectValue()
 
-	checkConfigSpan.SetTag("checkedInputs", showMap(secretNews))
+	setPayloadTag(checkConfigSpan, "checkedInputs", p.showConfig(ctx, secretNews))
 	return secretNews, checkFailures, nil
 }
 
@@ -194,7 +201,7 @@ func (p *provider) validateProviderConfig(
 			continue
 		}
 		// Ignoring version key as it seems to be special.
-		if k == "version" || k == "pluginDownloadURL" {
+		if k == "version" || k == "pluginDownloadURL" || k == tfbridge.AutonamingConfigKey {
 			continue
 		}
 		n := tfbridge.PulumiToTerraformName(string(k), p.schemaOnlyProvider.Schema(), p.info.GetConfig())
@@ -228,6 +235,17 @@ func (wc *wrappedConfig) IsSet(key string) bool {
 
 var _ shim.ResourceConfig = &wrappedConfig{}
 
-func showMap(pm resource.PropertyMap) string {
-	return resource.NewObjectProperty(pm).String()
+// Renders provider configuration for tracing spans with secrets redacted, or returns "" unless
+// tfbridge.TracePayloads opts in to tracing payloads.
+func (p *provider) showConfig(ctx context.Context, pm resource.PropertyMap) string {
+	if !tfbridge.TracePayloads() {
+		return ""
+	}
+	return tfbridge.SanitizeTracePayload(ctx, p.schemaOnlyProvider.Schema(), p.info.Config, pm)
+}
+
+func setPayloadTag(span opentracing.Span, key, text string) {
+	if text != "" {
+		span.SetTag(key, text)
+	}
 }
@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/opentracing/opentracing-go"
 
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
@@ -74,12 +75,18 @@ func (p *provider) ConfigureWithContext(ctx context.Context, inputs resource.Pro
 	configureSpan, ctx := opentracing.StartSpanFromContext(ctx, "pf.Configure",
 		opentracing.Tag{Key: "provider", Value: p.info.Name},
 		opentracing.Tag{Key: "version", Value: p.version.String()},
-		opentracing.Tag{Key: "inputs", Value: resource.NewObjectProperty(inputs).String()},
+		tfbridge.TracePayloadTag(ctx, "inputs", p.schemaOnlyProvider.Schema(), p.info.Config, inputs),
 	)
 	defer configureSpan.Finish()
 
 	p.lastKnownProviderConfig = inputs
 
+	p.configDeferred = p.info.DeferConfigureWithUnknowns && inputs.ContainsUnknowns()
+	if p.configDeferred {
+		tflog.Debug(ctx, "[pf/tfbridge] Deferring Configure: the configuration contains unknown values")
+		return nil
+	}
+
 	config, err := convert.EncodePropertyMapToDynamic(p.configEncoder, p.configType, inputs)
 	if err != nil {
 		return fmt.Errorf("cannot encode provider configuration to call ConfigureProvider: %w", err)
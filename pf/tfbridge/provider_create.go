@@ -16,6 +16,7 @@ package tfbridge
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
@@ -23,6 +24,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/convert"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 )
 
 // Create allocates a new instance of the provided resource and returns its unique resource ID.
@@ -34,17 +36,34 @@ func (p *provider) CreateWithContext(
 	preview bool,
 ) (resource.ID, resource.PropertyMap, resource.Status, error) {
 	ctx = p.initLogging(ctx, p.logSink, urn)
+	// The write-only values kept by Check are sent at most once, see [tfbridge.WriteOnlyInputs].
+	defer p.writeOnlyInputs.Forget(urn)
 
 	rh, err := p.resourceHandle(ctx, urn)
 	if err != nil {
 		return "", nil, 0, err
 	}
 
+	checkedInputs, err = p.writeOnlyInputs.Restore(urn, rh.schemaOnlyShimResource.Schema(),
+		rh.pulumiResourceInfo.GetFields(), checkedInputs)
+	if err != nil {
+		return "", nil, 0, err
+	}
+
+	if p.configDeferred {
+		if !preview {
+			return "", nil, 0, tfbridge.DeferredConfigureError(fmt.Sprintf("create %s", urn))
+		}
+		return "", tfbridge.DeferredPreviewOutputs(rh.schemaOnlyShimResource.Schema(),
+			rh.pulumiResourceInfo.GetFields(), checkedInputs), resource.StatusOK, nil
+	}
+
 	tfType := rh.schema.Type().TerraformType(ctx).(tftypes.Object)
 
 	priorState := newResourceState(ctx, &rh, nil /*private state*/)
 
-	checkedInputsValue, err := convert.EncodePropertyMap(rh.encoder, checkedInputs)
+	checkedInputsValue, err := convert.EncodePropertyMap(rh.encoder,
+		rh.defaultTags.Merge(checkedInputs, p.lastKnownProviderConfig))
 	if err != nil {
 		return "", nil, 0, err
 	}
@@ -95,38 +114,55 @@ func (p *provider) CreateWithContext(
 		// See https://www.terraform.io/internals/provider-meta
 	}
 
-	resp, err := p.tfServer.ApplyResourceChange(ctx, &req)
+	var resp *tfprotov6.ApplyResourceChangeResponse
+	err = p.operations.Run(ctx, rh.pulumiResourceInfo, timeoutDuration(timeout), func(ctx context.Context) error {
+		var err error
+		resp, err = p.tfServer.ApplyResourceChange(ctx, &req)
+		if err != nil {
+			return err
+		}
+		if err := p.processDiagnostics(resp.Diagnostics); err != nil {
+			if hasNewState(ctx, &rh, resp) {
+				// The resource exists despite the error: report it instead of retrying.
+				return tfbridge.Permanent(err)
+			}
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		return "", nil, 0, err
-	}
-
-	if err := p.processDiagnostics(resp.Diagnostics); err != nil {
-		return "", nil, 0, err
+		if !hasNewState(ctx, &rh, resp) {
+			return "", nil, 0, err
+		}
+		// The resource was created but the provider failed to finish configuring it. Report it as a partial
+		// failure so that the engine records it instead of leaking it.
+		createdID, createdStateMap, serr := p.createdState(ctx, &rh, resp, checkedInputs)
+		if serr != nil {
+			return "", nil, 0, err
+		}
+		return createdID, createdStateMap, resource.StatusPartialFailure, err
 	}
 
-	createdState, err := parseResourceStateFromTF(ctx, &rh, resp.NewState, resp.Private)
+	createdID, createdStateMap, err := p.createdState(ctx, &rh, resp, checkedInputs)
 	if err != nil {
 		return "", nil, 0, err
 	}
+	return createdID, createdStateMap, resource.StatusOK, nil
+}
 
-	createdStateMap, err := createdState.ToPropertyMap(&rh)
+func (p *provider) createdState(
+	ctx context.Context, rh *resourceHandle, resp *tfprotov6.ApplyResourceChangeResponse,
+	inputs resource.PropertyMap,
+) (resource.ID, resource.PropertyMap, error) {
+	createdStateMap, err := appliedState(ctx, rh, resp, inputs, p.lastKnownProviderConfig)
 	if err != nil {
-		return "", nil, 0, err
-	}
-
-	if rh.pulumiResourceInfo.TransformOutputs != nil {
-		var err error
-		createdStateMap, err = rh.pulumiResourceInfo.TransformOutputs(ctx, createdStateMap)
-		if err != nil {
-			return "", nil, 0, err
-		}
+		return "", nil, err
 	}
 
 	rn := rh.terraformResourceName
 	createdID, err := extractID(ctx, rn, rh.pulumiResourceInfo, createdStateMap)
 	if err != nil {
-		return "", nil, 0, err
+		return "", nil, err
 	}
-
-	return createdID, createdStateMap, resource.StatusOK, nil
+	return createdID, createdStateMap, nil
 }
@@ -46,7 +46,10 @@ func (p *provider) datasourceHandle(ctx context.Context, token tokens.ModuleMemb
 	}
 
 	typeName := pfutils.TypeName(dsName)
-	schema := p.datasources.Schema(typeName)
+	schema, err := p.datasources.Schema(typeName)
+	if err != nil {
+		return datasourceHandle{}, err
+	}
 
 	makeDataSource := func() datasource.DataSource {
 		return p.datasources.DataSource(typeName)
@@ -16,6 +16,7 @@ package tfbridge
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
@@ -23,6 +24,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/convert"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 )
 
 func (p *provider) DeleteWithContext(
@@ -41,7 +43,11 @@ func (p *provider) DeleteWithContext(
 		return resource.StatusOK, err
 	}
 
-	props, err := transformFromState(ctx, rh, outputs)
+	if p.configDeferred {
+		return resource.StatusOK, tfbridge.DeferredConfigureError(fmt.Sprintf("delete %s", urn))
+	}
+
+	props, err := fromState(ctx, rh, outputs)
 	if err != nil {
 		return resource.StatusOK, err
 	}
@@ -62,16 +68,23 @@ func (p *provider) DeleteWithContext(
 		PriorState: priorState,
 	}
 
-	resp, err := p.tfServer.ApplyResourceChange(ctx, &req)
-	if err != nil {
-		return resource.StatusOK, err
-	}
-
 	// NOTE: no need to handle resp.Private in Delete.
-
-	if err := p.processDiagnostics(resp.Diagnostics); err != nil {
+	var diagErr error
+	err = p.operations.Run(ctx, rh.pulumiResourceInfo, timeoutDuration(timeout), func(ctx context.Context) error {
+		resp, err := p.tfServer.ApplyResourceChange(ctx, &req)
+		if err != nil {
+			diagErr = nil
+			return err
+		}
+		diagErr = p.processDiagnostics(resp.Diagnostics)
+		return diagErr
+	})
+	if diagErr != nil {
 		return resource.StatusPartialFailure, err
 	}
+	if err != nil {
+		return resource.StatusOK, err
+	}
 
 	// In one example that was tested, resp.NewState after a
 	// successful delete seem to have a record with all null
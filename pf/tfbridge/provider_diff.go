@@ -45,6 +45,22 @@ func (p *provider) DiffWithContext(
 	checkedInputs resource.PropertyMap,
 	allowUnknowns bool,
 	ignoreChanges []string,
+) (plugin.DiffResult, error) {
+	diff, err := p.diff(ctx, urn, id, priorStateMap, checkedInputs, ignoreChanges)
+	if err != nil || diff.Changes == plugin.DiffNone {
+		// Neither Create nor Update follow, so the write-only values kept by Check are no longer needed.
+		p.writeOnlyInputs.Forget(urn)
+	}
+	return diff, err
+}
+
+func (p *provider) diff(
+	ctx context.Context,
+	urn resource.URN,
+	id resource.ID,
+	priorStateMap resource.PropertyMap,
+	checkedInputs resource.PropertyMap,
+	ignoreChanges []string,
 ) (plugin.DiffResult, error) {
 	ctx = p.initLogging(ctx, p.logSink, urn)
 	rh, err := p.resourceHandle(ctx, urn)
@@ -52,16 +68,29 @@ func (p *provider) DiffWithContext(
 		return plugin.DiffResult{}, err
 	}
 
-	priorStateMap, err = transformFromState(ctx, rh, priorStateMap)
+	priorOutputs := priorStateMap
+	priorStateMap, err = fromState(ctx, rh, priorStateMap)
 	if err != nil {
 		return plugin.DiffResult{}, err
 	}
 
+	if p.configDeferred {
+		return tfbridge.DeferredDiff(rh.schemaOnlyShimResource.Schema(), rh.pulumiResourceInfo.GetFields(),
+			priorStateMap, checkedInputs, ignoreChanges)
+	}
+
 	checkedInputs, err = propertyvalue.ApplyIgnoreChanges(priorStateMap, checkedInputs, ignoreChanges)
 	if err != nil {
 		return plugin.DiffResult{}, fmt.Errorf("failed to apply ignore changes: %w", err)
 	}
 
+	// Write-only values are absent from the checked inputs and the prior state. Restore them to the inputs and
+	// assume they are unchanged.
+	checkedInputs = p.writeOnlyInputs.Peek(urn, rh.schemaOnlyShimResource.Schema(),
+		rh.pulumiResourceInfo.GetFields(), checkedInputs)
+	priorStateMap = tfbridge.CopyWriteOnlyValues(rh.schemaOnlyShimResource.Schema(),
+		rh.pulumiResourceInfo.GetFields(), checkedInputs, priorStateMap)
+
 	rawPriorState, err := parseResourceState(&rh, priorStateMap)
 	if err != nil {
 		return plugin.DiffResult{}, err
@@ -74,7 +103,8 @@ func (p *provider) DiffWithContext(
 
 	tfType := rh.schema.Type().TerraformType(ctx).(tftypes.Object)
 
-	checkedInputsValue, err := convert.EncodePropertyMap(rh.encoder, checkedInputs)
+	checkedInputsValue, err := convert.EncodePropertyMap(rh.encoder,
+		rh.defaultTags.Merge(checkedInputs, p.lastKnownProviderConfig))
 	if err != nil {
 		return plugin.DiffResult{}, err
 	}
@@ -107,7 +137,7 @@ func (p *provider) DiffWithContext(
 	resSchemaMap := rh.schemaOnlyShimResource.Schema()
 	resFields := rh.pulumiResourceInfo.GetFields()
 	replaceKeys := topLevelPropertyKeySet(resSchemaMap, resFields, planResp.RequiresReplace)
-	changedKeys := topLevelPropertyKeySet(resSchemaMap, resFields, diffAttributePaths(tfDiff))
+	changedKeys := changedPropertyKeys(&rh, diffAttributePaths(tfDiff), priorOutputs, checkedInputs)
 
 	// TODO[pulumi/pulumi-terraform-bridge#823] nameRequiresDeleteBeforeReplace intricacies
 	deleteBeforeReplace := false
@@ -160,6 +190,42 @@ func topLevelPropertyKeySet(
 	return keys
 }
 
+// changedPropertyKeys is topLevelPropertyKeySet for the changes at paths, except that changes to the tags that come
+// only from the default tags are reported on tagsAll. See [tfbridge.ResourceDefaultTags.DiffKey].
+func changedPropertyKeys(
+	rh *resourceHandle, paths []*tftypes.AttributePath, priorOutputs, checkedInputs resource.PropertyMap,
+) []resource.PropertyKey {
+	sch, ps := rh.schemaOnlyShimResource.Schema(), rh.pulumiResourceInfo.GetFields()
+	var tagsAll resource.PropertyKey
+	var changed []*tftypes.AttributePath
+	for _, path := range paths {
+		steps := path.Steps()
+		if len(steps) > 0 {
+			if name, ok := steps[0].(tftypes.AttributeName); ok {
+				pp := resource.PropertyPath{tfbridge.TerraformToPulumiNameV2(string(name), sch, ps)}
+				if len(steps) > 1 {
+					if k, ok := steps[1].(tftypes.ElementKeyString); ok {
+						pp = append(pp, string(k))
+					}
+				}
+				if reported := rh.defaultTags.DiffKey(pp, priorOutputs, checkedInputs); reported[0] != pp[0] {
+					tagsAll = resource.PropertyKey(reported[0].(string))
+					continue
+				}
+			}
+		}
+		changed = append(changed, path)
+	}
+	keys := topLevelPropertyKeySet(sch, ps, changed)
+	if tagsAll != "" {
+		keys = append(keys, tagsAll)
+		sort.SliceStable(keys, func(i, j int) bool {
+			return keys[i] < keys[j]
+		})
+	}
+	return keys
+}
+
 func diffAttributePaths(tfDiff []tftypes.ValueDiff) []*tftypes.AttributePath {
 	paths := []*tftypes.AttributePath{}
 	for _, diff := range tfDiff {
@@ -25,6 +25,7 @@ import (
 
 	"github.com/pulumi/pulumi-terraform-bridge/pf/internal/defaults"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/convert"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/propertyvalue"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
@@ -44,8 +45,19 @@ func (p *provider) InvokeWithContext(
 		return nil, nil, err
 	}
 
+	if p.configDeferred {
+		return nil, nil, tfbridge.DeferredConfigureError(fmt.Sprintf("invoke %s", tok))
+	}
+
 	typ := handle.schema.Type().TerraformType(ctx).(tftypes.Object)
 
+	if info := handle.pulumiDataSourceInfo; info != nil && info.PreInvokeCallback != nil {
+		args, err = info.PreInvokeCallback(ctx, args, p.lastKnownProviderConfig.Copy())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Transform args to apply Pulumi-level defaults.
 	argsWithDefaults := defaults.ApplyDefaultInfoValues(ctx, defaults.ApplyDefaultInfoValuesArgs{
 		SchemaMap:      handle.schemaOnlyShim.Schema(),
@@ -64,7 +76,19 @@ func (p *provider) InvokeWithContext(
 		return nil, failures, err
 	}
 
-	return p.readDataSource(ctx, handle, config)
+	result, failures, err := p.readDataSource(ctx, handle, config)
+	if err != nil || len(failures) > 0 {
+		return result, failures, err
+	}
+
+	if info := handle.pulumiDataSourceInfo; info != nil && info.TransformOutputs != nil {
+		result, err = info.TransformOutputs(ctx, result)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return result, nil, nil
 }
 
 func (p *provider) validateDataResourceConfig(ctx context.Context, handle datasourceHandle,
@@ -91,12 +115,17 @@ func (p *provider) readDataSource(ctx context.Context, handle datasourceHandle,
 		// TODO[pulumi/pulumi-terraform-bridge#794] set ProviderMeta
 	}
 
-	resp, err := p.tfServer.ReadDataSource(ctx, req)
-	if err != nil {
-		return nil, nil, fmt.Errorf("error calling ReadDataSource: %w", err)
-	}
-
-	failures, err := p.processInvokeDiagnostics(handle, resp.Diagnostics)
+	var resp *tfprotov6.ReadDataSourceResponse
+	var failures []plugin.CheckFailure
+	err := p.operations.Run(ctx, nil, 0, func(ctx context.Context) error {
+		var err error
+		resp, err = p.tfServer.ReadDataSource(ctx, req)
+		if err != nil {
+			return fmt.Errorf("error calling ReadDataSource: %w", err)
+		}
+		failures, err = p.processInvokeDiagnostics(handle, resp.Diagnostics)
+		return err
+	})
 	if err != nil || len(failures) > 0 {
 		return nil, failures, err
 	}
@@ -51,6 +51,10 @@ func (p *provider) ReadWithContext(
 		return plugin.ReadResult{}, 0, err
 	}
 
+	if p.configDeferred {
+		return plugin.ReadResult{}, 0, tfbridge.DeferredConfigureError(fmt.Sprintf("read %s", urn))
+	}
+
 	// Both "get" and "refresh" scenarios call Read. Detect and dispatch.
 	isRefresh := len(currentStateMap) != 0
 
@@ -59,7 +63,11 @@ func (p *provider) ReadWithContext(
 	if isRefresh {
 		// If we are in a refresh, then currentStateMap was read from the state
 		// and should be transformed.
-		currentStateMap, err = transformFromState(ctx, rh, currentStateMap)
+		oldInputs, err = tfbridge.MigrateInputs(ctx, rh.pulumiResourceInfo, currentStateMap, oldInputs)
+		if err != nil {
+			return plugin.ReadResult{}, 0, err
+		}
+		currentStateMap, err = fromState(ctx, rh, currentStateMap)
 		if err != nil {
 			return plugin.ReadResult{}, 0, err
 		}
@@ -72,6 +80,14 @@ func (p *provider) ReadWithContext(
 		return result, ignoredStatus, err
 	}
 
+	if result.Outputs != nil {
+		var knownInputs resource.PropertyMap
+		if isRefresh {
+			knownInputs = oldInputs
+		}
+		result.Outputs = rh.defaultTags.SplitOutputs(result.Outputs, knownInputs, p.lastKnownProviderConfig)
+	}
+
 	if result.Outputs != nil && rh.pulumiResourceInfo.TransformOutputs != nil {
 		var err error
 		result.Outputs, err = rh.pulumiResourceInfo.TransformOutputs(ctx, result.Outputs)
@@ -93,6 +109,9 @@ func (p *provider) ReadWithContext(
 
 		// __defaults is not needed for Plugin Framework bridged providers
 		delete(result.Inputs, "__defaults")
+
+		result.Inputs = tfbridge.RemoveWriteOnlyValues(rh.schemaOnlyShimResource.Schema(),
+			rh.pulumiResourceInfo.GetFields(), result.Inputs)
 	}
 
 	return result, ignoredStatus, err
@@ -128,15 +147,19 @@ func (p *provider) readResource(
 
 	// TODO[pulumi/pulumi-terraform-bridge#794] set ProviderMeta
 
-	resp, err := p.tfServer.ReadResource(ctx, &req)
+	var resp *tfprotov6.ReadResourceResponse
+	err = p.operations.Run(ctx, rh.pulumiResourceInfo, 0, func(ctx context.Context) error {
+		var err error
+		resp, err = p.tfServer.ReadResource(ctx, &req)
+		if err != nil {
+			return err
+		}
+		return p.processDiagnostics(resp.Diagnostics)
+	})
 	if err != nil {
 		return plugin.ReadResult{}, err
 	}
 
-	if err := p.processDiagnostics(resp.Diagnostics); err != nil {
-		return plugin.ReadResult{}, err
-	}
-
 	if resp.NewState == nil {
 		return plugin.ReadResult{}, nil
 	}
@@ -39,6 +39,7 @@ type resourceHandle struct {
 	encoder                convert.Encoder
 	decoder                convert.Decoder
 	schemaOnlyShimResource shim.Resource
+	defaultTags            *tfbridge.ResourceDefaultTags // optional
 }
 
 func (p *provider) resourceHandle(ctx context.Context, urn pulumiresource.URN) (resourceHandle, error) {
@@ -50,7 +51,10 @@ func (p *provider) resourceHandle(ctx context.Context, urn pulumiresource.URN) (
 	}
 
 	n := pfutils.TypeName(typeName)
-	schema := resources.Schema(n)
+	schema, err := resources.Schema(n)
+	if err != nil {
+		return resourceHandle{}, err
+	}
 
 	result := resourceHandle{
 		makeResource: func() pfresource.Resource {
@@ -86,12 +90,29 @@ func (p *provider) resourceHandle(ctx context.Context, urn pulumiresource.URN) (
 	result.token = token
 
 	result.schemaOnlyShimResource, _ = p.schemaOnlyProvider.ResourcesMap().GetOk(typeName)
+	if result.schemaOnlyShimResource != nil {
+		result.defaultTags = tfbridge.NewResourceDefaultTags(p.info.DefaultTags, typeName,
+			result.schemaOnlyShimResource.Schema(), result.pulumiResourceInfo.GetFields())
+	}
 	return result, nil
 }
 
+// fromState prepares a resource state recorded by Pulumi for use with the upstream resource, applying
+// StateMigrations before transformFromState.
+func fromState(
+	ctx context.Context, rh resourceHandle, state pulumiresource.PropertyMap,
+) (pulumiresource.PropertyMap, error) {
+	state, err := tfbridge.MigrateState(ctx, rh.pulumiResourceInfo, state)
+	if err != nil {
+		return nil, err
+	}
+	return transformFromState(ctx, rh, state)
+}
+
 func transformFromState(
 	ctx context.Context, rh resourceHandle, state pulumiresource.PropertyMap,
 ) (pulumiresource.PropertyMap, error) {
+	state = rh.defaultTags.JoinState(state)
 	if rh.pulumiResourceInfo == nil {
 		return state, nil
 	}
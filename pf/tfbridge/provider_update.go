@@ -24,6 +24,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/convert"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/propertyvalue"
 )
 
@@ -39,22 +40,42 @@ func (p *provider) UpdateWithContext(
 	preview bool,
 ) (resource.PropertyMap, resource.Status, error) {
 	ctx = p.initLogging(ctx, p.logSink, urn)
+	// The write-only values kept by Check are sent at most once, see [tfbridge.WriteOnlyInputs].
+	defer p.writeOnlyInputs.Forget(urn)
 
 	rh, err := p.resourceHandle(ctx, urn)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	priorStateMap, err = transformFromState(ctx, rh, priorStateMap)
+	priorStateMap, err = fromState(ctx, rh, priorStateMap)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	if p.configDeferred {
+		if !preview {
+			return nil, 0, tfbridge.DeferredConfigureError(fmt.Sprintf("update %s", urn))
+		}
+		return tfbridge.DeferredPreviewOutputs(rh.schemaOnlyShimResource.Schema(),
+			rh.pulumiResourceInfo.GetFields(), checkedInputs), resource.StatusOK, nil
+	}
+
 	checkedInputs, err = propertyvalue.ApplyIgnoreChanges(priorStateMap, checkedInputs, ignoreChanges)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to apply ignore changes: %w", err)
 	}
 
+	// Write-only values are absent from the checked inputs and the prior state. Restore them to the inputs and
+	// assume they are unchanged.
+	checkedInputs, err = p.writeOnlyInputs.Restore(urn, rh.schemaOnlyShimResource.Schema(),
+		rh.pulumiResourceInfo.GetFields(), checkedInputs)
+	if err != nil {
+		return nil, 0, err
+	}
+	priorStateMap = tfbridge.CopyWriteOnlyValues(rh.schemaOnlyShimResource.Schema(),
+		rh.pulumiResourceInfo.GetFields(), checkedInputs, priorStateMap)
+
 	tfType := rh.schema.Type().TerraformType(ctx).(tftypes.Object)
 
 	rawPriorState, err := parseResourceState(&rh, priorStateMap)
@@ -67,7 +88,8 @@ func (p *provider) UpdateWithContext(
 		return nil, 0, err
 	}
 
-	checkedInputsValue, err := convert.EncodePropertyMap(rh.encoder, checkedInputs)
+	checkedInputsValue, err := convert.EncodePropertyMap(rh.encoder,
+		rh.defaultTags.Merge(checkedInputs, p.lastKnownProviderConfig))
 	if err != nil {
 		return nil, 0, err
 	}
@@ -109,32 +131,38 @@ func (p *provider) UpdateWithContext(
 		PlannedPrivate: planResp.PlannedPrivate,
 	}
 
-	resp, err := p.tfServer.ApplyResourceChange(ctx, &req)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	if err := p.processDiagnostics(resp.Diagnostics); err != nil {
-		return nil, 0, err
-	}
-
-	updatedState, err := parseResourceStateFromTF(ctx, &rh, resp.NewState, resp.Private)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	updatedStateMap, err := updatedState.ToPropertyMap(&rh)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	if rh.pulumiResourceInfo.TransformOutputs != nil {
+	var resp *tfprotov6.ApplyResourceChangeResponse
+	err = p.operations.Run(ctx, rh.pulumiResourceInfo, timeoutDuration(timeout), func(ctx context.Context) error {
 		var err error
-		updatedStateMap, err = rh.pulumiResourceInfo.TransformOutputs(ctx, updatedStateMap)
+		resp, err = p.tfServer.ApplyResourceChange(ctx, &req)
 		if err != nil {
+			return err
+		}
+		if err := p.processDiagnostics(resp.Diagnostics); err != nil {
+			if hasNewState(ctx, &rh, resp) {
+				// The resource exists despite the error: report it instead of retrying.
+				return tfbridge.Permanent(err)
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if !hasNewState(ctx, &rh, resp) {
+			return nil, 0, err
+		}
+		// The provider failed part way through the update. Report the state it returned as a partial failure so
+		// that the engine records the changes that were applied.
+		updatedStateMap, serr := appliedState(ctx, &rh, resp, checkedInputs, p.lastKnownProviderConfig)
+		if serr != nil {
 			return nil, 0, err
 		}
+		return updatedStateMap, resource.StatusPartialFailure, err
 	}
 
+	updatedStateMap, err := appliedState(ctx, &rh, resp, checkedInputs, p.lastKnownProviderConfig)
+	if err != nil {
+		return nil, 0, err
+	}
 	return updatedStateMap, resource.StatusOK, nil
 }
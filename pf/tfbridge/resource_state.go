@@ -52,10 +52,18 @@ func (u *upgradedResourceState) ToPropertyMap(rh *resourceHandle) (resource.Prop
 	if err != nil {
 		return nil, err
 	}
-	return updateMeta(propMap, metaState{
+	if rh.schemaOnlyShimResource != nil {
+		propMap = tfbridge.RemoveWriteOnlyValues(rh.schemaOnlyShimResource.Schema(),
+			rh.pulumiResourceInfo.GetFields(), propMap)
+	}
+	propMap, err = updateMeta(propMap, metaState{
 		SchemaVersion: u.state.TFSchemaVersion,
 		PrivateState:  u.state.Private,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return tfbridge.SetStateVersion(rh.pulumiResourceInfo, propMap)
 }
 
 func newResourceState(ctx context.Context, rh *resourceHandle, private []byte) *upgradedResourceState {
@@ -204,3 +212,39 @@ func updateMeta(m resource.PropertyMap, newMeta metaState) (resource.PropertyMap
 	c[metaKey] = resource.NewStringProperty(string(updatedMeta))
 	return c, nil
 }
+
+// hasNewState reports whether resp carries a non-null new state, which means that the resource exists even if
+// ApplyResourceChange failed.
+func hasNewState(ctx context.Context, rh *resourceHandle, resp *tfprotov6.ApplyResourceChangeResponse) bool {
+	if resp == nil || resp.NewState == nil {
+		return false
+	}
+	v, err := resp.NewState.Unmarshal(rh.schema.Type().TerraformType(ctx))
+	return err == nil && !v.IsNull()
+}
+
+// appliedState converts the new state returned by ApplyResourceChange into Pulumi outputs. inputs are the checked
+// inputs of the resource and config the provider configuration, which split the default tags from the outputs.
+func appliedState(
+	ctx context.Context, rh *resourceHandle, resp *tfprotov6.ApplyResourceChangeResponse,
+	inputs, config resource.PropertyMap,
+) (resource.PropertyMap, error) {
+	state, err := parseResourceStateFromTF(ctx, rh, resp.NewState, resp.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	stateMap, err := state.ToPropertyMap(rh)
+	if err != nil {
+		return nil, err
+	}
+	stateMap = rh.defaultTags.SplitOutputs(stateMap, inputs, config)
+
+	if rh.pulumiResourceInfo.TransformOutputs != nil {
+		stateMap, err = rh.pulumiResourceInfo.TransformOutputs(ctx, stateMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stateMap, nil
+}
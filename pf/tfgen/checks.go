@@ -17,11 +17,30 @@ package tfgen
 import (
 	"fmt"
 
+	pfmuxer "github.com/pulumi/pulumi-terraform-bridge/pf/internal/muxer"
+	"github.com/pulumi/pulumi-terraform-bridge/pf/internal/schemashim"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 )
 
+// checkSchemas reports the errors of the schemas of the Plugin Framework resources and data sources of info.P, which
+// are otherwise only computed on first use.
+func checkSchemas(info tfbridge.ProviderInfo) error {
+	providers := []shim.Provider{info.P}
+	if mux, ok := info.P.(*pfmuxer.ProviderShim); ok {
+		providers = mux.MuxedProviders
+	}
+	for _, p := range providers {
+		if p, ok := p.(*schemashim.SchemaOnlyProvider); ok {
+			if err := p.CheckSchemas(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func checkIDProperties(sink diag.Sink, info tfbridge.ProviderInfo) error {
 	errors := 0
 
@@ -50,6 +50,10 @@ func GenerateSchema(_ context.Context, opts GenerateSchemaOptions) (*GenerateSch
 		})
 	}
 
+	if err := checkSchemas(opts.ProviderInfo); err != nil {
+		return nil, err
+	}
+
 	generated, err := realtfgen.GenerateSchemaWithOptions(realtfgen.GenerateSchemaOptions{
 		ProviderInfo:    opts.ProviderInfo,
 		DiagnosticsSink: sink,
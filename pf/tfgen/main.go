@@ -15,14 +15,17 @@
 package tfgen
 
 import (
+	"context"
 	"fmt"
 
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 
 	pfmuxer "github.com/pulumi/pulumi-terraform-bridge/pf/internal/muxer"
 	sdkBridge "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfgen"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/metadata"
+	"github.com/pulumi/pulumi-terraform-bridge/x/muxer"
 )
 
 // Implements main() logic for a provider build-time helper utility. By convention these utilities are named
@@ -48,6 +51,10 @@ func Main(provider string, info sdkBridge.ProviderInfo) {
 			return err
 		}
 
+		if err := checkSchemas(info); err != nil {
+			return err
+		}
+
 		g, err := tfgen.NewGenerator(opts)
 		if err != nil {
 			return err
@@ -72,14 +79,13 @@ func Main(provider string, info sdkBridge.ProviderInfo) {
 // The resulting binary is able to generate [Pulumi Package Schema] as well as provider SDK sources in various
 // programming languages supported by Pulumi such as TypeScript, Go, and Python.
 //
+// Providers listed in info.MuxWith are layered on top of the muxed providers, both in the generated schema and in
+// the dispatch table stored in the "mux" metadata.
+//
 // This is an experimental API.
 //
 // [Pulumi Package Schema]: https://www.pulumi.com/docs/guides/pulumi-packages/schema/
 func MainWithMuxer(provider string, info sdkBridge.ProviderInfo) {
-	if len(info.MuxWith) > 0 {
-		panic("mixin providers via tfbridge.ProviderInfo.MuxWith is currently not supported")
-	}
-
 	shim, ok := info.P.(*pfmuxer.ProviderShim)
 	contract.Assertf(ok, "MainWithMuxer must have a ProviderInfo.P created with AugmentShimWithPF")
 
@@ -93,15 +99,27 @@ func MainWithMuxer(provider string, info sdkBridge.ProviderInfo) {
 		if err != nil {
 			return fmt.Errorf("failed to compute dispatch for muxed provider: %w", err)
 		}
-		err = metadata.Set(info.GetMetadata(), "mux", dispatch)
-		if err != nil {
-			return err
+
+		if len(info.MuxWith) > 0 {
+			opts.ProviderInfo, err = layerMuxWith(context.Background(), shim, provider, opts.ProviderInfo, dispatch)
+			if err != nil {
+				return err
+			}
+		} else {
+			err = metadata.Set(info.GetMetadata(), "mux", dispatch)
+			if err != nil {
+				return err
+			}
 		}
 
 		if err := notSupported(opts.Sink, info); err != nil {
 			return err
 		}
 
+		if err := checkSchemas(info); err != nil {
+			return err
+		}
+
 		g, err := tfgen.NewGenerator(opts)
 		if err != nil {
 			return err
@@ -110,3 +128,38 @@ func MainWithMuxer(provider string, info sdkBridge.ProviderInfo) {
 		return g.Generate()
 	})
 }
+
+// layerMuxWith arranges for the schemas of the info.MuxWith providers to be merged into the generated schema, and
+// for the "mux" metadata to dispatch to both the providers muxed in shim and the MuxWith providers.
+//
+// The merge is done by pf/tfgen rather than pkg/tfgen since the dispatch table computed by pkg/tfgen only knows
+// about a single bridged provider.
+func layerMuxWith(
+	ctx context.Context, shim *pfmuxer.ProviderShim, provider string, info sdkBridge.ProviderInfo,
+	dispatch muxer.DispatchTable,
+) (sdkBridge.ProviderInfo, error) {
+	mixins := make([]pschema.PackageSpec, len(info.MuxWith))
+	for i, p := range info.MuxWith {
+		spec, err := p.GetSpec(ctx, provider, info.Version)
+		if err != nil {
+			return info, err
+		}
+		mixins[i] = spec
+	}
+
+	md := info.GetMetadata()
+	postProcessor := info.SchemaPostProcessor
+	info.MuxWith = nil
+	info.SchemaPostProcessor = func(spec *pschema.PackageSpec) {
+		merged, table, err := shim.MergeMuxWith(append([]pschema.PackageSpec{*spec}, mixins...), dispatch)
+		contract.AssertNoErrorf(err, "failed to create muxer schema")
+		err = metadata.Set(md, "mux", table)
+		contract.AssertNoErrorf(err, "[pf/tfgen] failed to add muxer to MetadataInfo.Data")
+		*spec = merged
+
+		if postProcessor != nil {
+			postProcessor(spec)
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package convert
+
+import (
+	"context"
+	"testing"
+
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/require"
+	"pgregory.net/rapid"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/internal/rapidgen"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/propertyvalue"
+)
+
+var roundTripValueOptions = rapidgen.ValueOptions{
+	PulumiName: func(name string, sm shim.SchemaMap) string {
+		return tfbridge.TerraformToPulumiNameV2(name, sm, nil)
+	},
+}
+
+// encodeDecode encodes m to a tftypes.Value and decodes it back, as Plugin Framework providers do.
+func encodeDecode(t *rapid.T, sm shim.SchemaMap, m resource.PropertyMap) resource.PropertyMap {
+	enc, err := NewObjectEncoder(ObjectSchema{SchemaMap: sm})
+	require.NoError(t, err)
+	dec, err := NewObjectDecoder(ObjectSchema{SchemaMap: sm})
+	require.NoError(t, err)
+
+	v, err := EncodePropertyMap(enc, m)
+	require.NoError(t, err)
+	t.Logf("tftypes value: %v", v)
+	decoded, err := DecodePropertyMap(dec, v)
+	require.NoError(t, err)
+	return decoded
+}
+
+// normalize removes the differences that are expected between the values produced by the conversions:
+//
+//   - secrets, which are restored from schema rather than from the inputs,
+//   - nulls, which the decoder emits for all missing attributes,
+//   - the __defaults bookkeeping of the SDKv2 conversion, and
+//   - the representation of unknowns, which may be either Computed or Output values.
+func normalize(m resource.PropertyMap) resource.PropertyMap {
+	v := propertyvalue.Transform(func(v resource.PropertyValue) resource.PropertyValue {
+		if v.ContainsUnknowns() && (v.IsComputed() || v.IsOutput()) {
+			return resource.MakeComputed(resource.NewStringProperty(""))
+		}
+		if !v.IsObject() {
+			return v
+		}
+		obj := v.ObjectValue().Copy()
+		for k, e := range obj {
+			if e.IsNull() || k == "__defaults" {
+				delete(obj, k)
+			}
+		}
+		return resource.NewObjectProperty(obj)
+	}, propertyvalue.RemoveSecrets(resource.NewObjectProperty(m)))
+	return v.ObjectValue()
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	t.Parallel()
+	opts := roundTripValueOptions
+	opts.Secrets = true
+	opts.Unknowns = true
+	rapid.Check(t, func(t *rapid.T) {
+		sm := rapidgen.SchemaMapGen(3).Draw(t, "schema")
+		inputs := rapidgen.PropertyMapGen(sm, opts).Draw(t, "inputs")
+
+		decoded := encodeDecode(t, sm, inputs.PropertyMap)
+
+		// Unlike the SDKv2 conversion, tftypes values can represent unknowns anywhere, so they survive as is.
+		require.Equal(t, normalize(inputs.PropertyMap), normalize(decoded))
+	})
+}
+
+// The Plugin Framework and SDKv2 conversions must produce the same Pulumi values, so that switching a resource
+// between the two is not visible to users.
+func TestEncodingAgreesWithSDKv2(t *testing.T) {
+	t.Parallel()
+	opts := roundTripValueOptions
+	opts.Secrets = true
+	rapid.Check(t, func(t *rapid.T) {
+		sm := rapidgen.SchemaMapGen(3).Draw(t, "schema")
+		inputs := rapidgen.PropertyMapGen(sm, opts).Draw(t, "inputs")
+		ctx := context.Background()
+
+		pf := encodeDecode(t, sm, inputs.PropertyMap)
+
+		tfInputs, assets, err := tfbridge.MakeTerraformInputs(ctx, nil, nil, nil,
+			propertyvalue.RemoveSecrets(resource.NewObjectProperty(inputs.PropertyMap)).ObjectValue(), sm, nil)
+		require.NoError(t, err)
+		sdkv2 := tfbridge.MakeTerraformOutputs(ctx, shimv2.NewProvider(&schemav2.Provider{}), tfInputs, sm, nil,
+			assets, false, true)
+
+		require.Equal(t, normalize(pf), normalize(sdkv2))
+		requireSameSecrets(t, resource.NewObjectProperty(pf), resource.NewObjectProperty(sdkv2))
+	})
+}
+
+// requireSameSecrets checks that a and b mark the same values secret, ignoring nulls.
+func requireSameSecrets(t *rapid.T, a, b resource.PropertyValue) {
+	if a.IsNull() || b.IsNull() {
+		return
+	}
+	require.Equalf(t, a.IsSecret(), b.IsSecret(), "secretness differs: %v vs %v", a, b)
+	a, b = propertyvalue.RemoveSecrets(a), propertyvalue.RemoveSecrets(b)
+	switch {
+	case a.IsObject() && b.IsObject():
+		for k, v := range a.ObjectValue() {
+			requireSameSecrets(t, v, b.ObjectValue()[k])
+		}
+	case a.IsArray() && b.IsArray():
+		for i, v := range a.ArrayValue() {
+			requireSameSecrets(t, v, b.ArrayValue()[i])
+		}
+	}
+}
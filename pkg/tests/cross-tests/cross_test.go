@@ -37,6 +37,7 @@ import (
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	sdkv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
 	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2/planoracle"
 	pulumidiag "github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
@@ -70,18 +71,16 @@ const (
 )
 
 func runDiffCheck(t *testing.T, tc diffTestCase) {
-	// ctx := context.Background()
-	tfwd := t.TempDir()
-
-	reattachConfig := startTFProvider(t, tc)
-
-	tfWriteJSON(t, tfwd, tc.Config1)
-	p1 := runTFPlan(t, tfwd, reattachConfig)
-	runTFApply(t, tfwd, reattachConfig, p1)
-
-	tfWriteJSON(t, tfwd, tc.Config2)
-	p2 := runTFPlan(t, tfwd, reattachConfig)
-	runTFApply(t, tfwd, reattachConfig, p2)
+	var p2 tfPlan
+	if usePlanOracle() {
+		p2 = runTFOracleDiffCheck(t, tc)
+	} else {
+		p2 = runTFCLIDiffCheck(t, tc)
+		// Terraform CLI is the baseline. Check that the plan oracle agrees with it, so that the oracle can be
+		// trusted when the tests run without Terraform CLI.
+		assert.Equalf(t, parseChangesFromTFPlan(p2), parseChangesFromTFPlan(runTFOracleDiffCheck(t, tc)),
+			"the plan oracle should agree with Terraform CLI")
+	}
 
 	{
 		planBytes, err := json.MarshalIndent(p2.RawPlan, "", "  ")
@@ -119,6 +118,54 @@ func runDiffCheck(t *testing.T, tc diffTestCase) {
 	verifyBasicDiffAgreement(t, p2, x.Summary)
 }
 
+// usePlanOracle reports whether the Terraform side of the tests should run against the in-process plan oracle
+// only, rather than under Terraform CLI. Set CROSS_TESTS_USE_PLAN_ORACLE=true to run the tests without Terraform CLI.
+func usePlanOracle() bool {
+	return os.Getenv("CROSS_TESTS_USE_PLAN_ORACLE") == "true"
+}
+
+func runTFCLIDiffCheck(t *testing.T, tc diffTestCase) tfPlan {
+	tfwd := t.TempDir()
+
+	reattachConfig := startTFProvider(t, tc)
+
+	tfWriteJSON(t, tfwd, tc.Config1)
+	p1 := runTFPlan(t, tfwd, reattachConfig)
+	runTFApply(t, tfwd, reattachConfig, p1)
+
+	tfWriteJSON(t, tfwd, tc.Config2)
+	p2 := runTFPlan(t, tfwd, reattachConfig)
+	runTFApply(t, tfwd, reattachConfig, p2)
+	return p2
+}
+
+// runTFOracleDiffCheck runs the same steps as runTFCLIDiffCheck against the in-process plan oracle. The resulting
+// plan mimics the parts of `terraform show -json` that the tests inspect.
+func runTFOracleDiffCheck(t *testing.T, tc diffTestCase) tfPlan {
+	ctx := context.Background()
+	o, err := planoracle.New(ctx, toTFProvider(prepareTFResource(tc)).GRPCProvider())
+	require.NoError(t, err)
+	r, err := o.Resource(rtype)
+	require.NoError(t, err)
+
+	var p *planoracle.Plan
+	for _, config := range []any{tc.Config1, tc.Config2} {
+		p, err = r.Plan(ctx, config)
+		require.NoErrorf(t, err, "planning %v", config)
+		require.NoErrorf(t, r.Apply(ctx, p), "applying %v", config)
+	}
+
+	return tfPlan{RawPlan: map[string]any{
+		"resource_changes": []any{
+			map[string]any{
+				"change": map[string]any{
+					"actions": p.Actions,
+				},
+			},
+		},
+	}}
+}
+
 func tfWriteJSON(t *testing.T, cwd string, rconfig any) {
 	config := map[string]any{
 		"resource": map[string]any{
@@ -167,7 +214,8 @@ func toTFProvider(tc diffTestCase) *schema.Provider {
 	}
 }
 
-func startTFProvider(t *testing.T, tc diffTestCase) *plugin.ReattachConfig {
+// prepareTFResource fills in the CRUD functions of the resource under test that the test case leaves unset.
+func prepareTFResource(tc diffTestCase) diffTestCase {
 	tc.Resource.CustomizeDiff = func(
 		ctx context.Context, rd *schema.ResourceDiff, i interface{},
 	) error {
@@ -202,7 +250,11 @@ func startTFProvider(t *testing.T, tc diffTestCase) *plugin.ReattachConfig {
 		return diag.Diagnostics{}
 	}
 
-	p := toTFProvider(tc)
+	return tc
+}
+
+func startTFProvider(t *testing.T, tc diffTestCase) *plugin.ReattachConfig {
+	p := toTFProvider(prepareTFResource(tc))
 
 	serverFactory := func() tfprotov5.ProviderServer {
 		return p.GRPCProvider()
@@ -759,6 +811,9 @@ func verifyBasicDiffAgreement(t *testing.T, plan tfPlan, us auto.UpdateSummary)
 }
 
 func skipUnlessLinux(t *testing.T) {
+	if usePlanOracle() {
+		return
+	}
 	if ci, ok := os.LookupEnv("CI"); ok && ci == "true" && !strings.Contains(strings.ToLower(runtime.GOOS), "linux") {
 		t.Skip("Skipping on non-Linux platforms as our CI does not yet install Terraform CLI required for these tests")
 	}
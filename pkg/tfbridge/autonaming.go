@@ -0,0 +1,171 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// AutonamingConfigKey is the provider configuration key that overrides the auto-naming of resources.
+//
+// The key is reserved by the bridge and is not passed to the Terraform provider. Its value is an object, or a
+// JSON string encoding one, such as the one set by:
+//
+//	pulumi config set --path aws:autonaming.pattern '${project}-${stack}-${name}-${hex(6)}'
+//	pulumi config set --path 'aws:autonaming.resources["aws:s3/bucket:Bucket"].mode' verbatim
+//
+// The object has the following fields:
+//
+//   - pattern: a template for names, see below.
+//   - mode: "default" to use the auto-naming of the provider, "verbatim" to use the resource name as is without
+//     a random suffix, or "disabled" to not auto-name resources at all. A pattern cannot be combined with the
+//     "verbatim" and "disabled" modes.
+//   - resources: per-resource-type overrides keyed by Pulumi type token, each an object with pattern and mode
+//     fields. An override replaces the provider-wide settings for its type.
+//
+// A pattern may reference ${name}, the resource name, ${project} and ${stack} from the URN of the resource, and
+// ${hex(n)}, n random hex digits that are stable for the lifetime of the resource. Names computed from a pattern
+// or in verbatim mode are not transformed by [AutoNameOptions], but are still checked against its Maxlen.
+const AutonamingConfigKey = "autonaming"
+
+// The modes of [AutonamingConfigKey].
+const (
+	autonamingModeDefault  = "default"
+	autonamingModeVerbatim = "verbatim"
+	autonamingModeDisabled = "disabled"
+)
+
+type autonamingSettings struct {
+	Pattern string `json:"pattern,omitempty"`
+	Mode    string `json:"mode,omitempty"`
+}
+
+type autonamingConfig struct {
+	autonamingSettings
+	Resources map[string]autonamingSettings `json:"resources,omitempty"`
+}
+
+var autonamingPatternVar = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// Read the value of [AutonamingConfigKey] from the provider configuration. Returns nil if it is not set or not
+// known yet.
+func parseAutonamingConfig(config resource.PropertyMap) (*autonamingConfig, error) {
+	v, ok := config[AutonamingConfigKey]
+	if !ok || v.IsNull() || v.ContainsUnknowns() {
+		return nil, nil
+	}
+	for v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+
+	var raw []byte
+	if v.IsString() {
+		raw = []byte(v.StringValue())
+	} else {
+		bytes, err := json.Marshal(v.Mappable())
+		if err != nil {
+			return nil, err
+		}
+		raw = bytes
+	}
+
+	var c autonamingConfig
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("%s must be an object: %w", AutonamingConfigKey, err)
+	}
+	if err := c.autonamingSettings.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", AutonamingConfigKey, err)
+	}
+	for typ, s := range c.Resources {
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("%s.resources[%q]: %w", AutonamingConfigKey, typ, err)
+		}
+	}
+	return &c, nil
+}
+
+// CheckAutonamingConfig validates the value of [AutonamingConfigKey] in the provider configuration, if any.
+func CheckAutonamingConfig(config resource.PropertyMap) error {
+	_, err := parseAutonamingConfig(config)
+	return err
+}
+
+func (s autonamingSettings) validate() error {
+	switch s.Mode {
+	case "", autonamingModeDefault:
+	case autonamingModeVerbatim, autonamingModeDisabled:
+		if s.Pattern != "" {
+			return fmt.Errorf("a pattern cannot be used with mode %q", s.Mode)
+		}
+	default:
+		return fmt.Errorf("unknown mode %q, expected %q, %q or %q", s.Mode,
+			autonamingModeDefault, autonamingModeVerbatim, autonamingModeDisabled)
+	}
+	// Evaluate the pattern for a placeholder resource to check its syntax.
+	_, err := s.evalPattern("urn:pulumi:stack::project::pkg:index:Res::name", nil)
+	return err
+}
+
+// The settings that apply to resources of type typ.
+func (c *autonamingConfig) settingsFor(typ string) autonamingSettings {
+	if s, ok := c.Resources[typ]; ok {
+		return s
+	}
+	return c.autonamingSettings
+}
+
+// Substitute the variables of the pattern for the resource identified by urn.
+func (s autonamingSettings) evalPattern(urn resource.URN, seed []byte) (string, error) {
+	var err error
+	hexCount := 0
+	name := autonamingPatternVar.ReplaceAllStringFunc(s.Pattern, func(match string) string {
+		expr := autonamingPatternVar.FindStringSubmatch(match)[1]
+		switch expr {
+		case "name":
+			return urn.Name()
+		case "project":
+			return string(urn.Project())
+		case "stack":
+			return string(urn.Stack())
+		}
+
+		var n int
+		if _, scanErr := fmt.Sscanf(expr, "hex(%d)", &n); scanErr != nil || "hex("+strconv.Itoa(n)+")" != expr {
+			err = fmt.Errorf("unknown expression ${%s} in pattern %q", expr, s.Pattern)
+			return match
+		}
+		if n <= 0 {
+			err = fmt.Errorf("hex length must be positive in pattern %q", s.Pattern)
+			return match
+		}
+		// Derive a distinct seed for each ${hex(n)} so that they do not repeat each other.
+		var hexSeed []byte
+		if len(seed) > 0 {
+			hexSeed = append(append(hexSeed, seed...), byte(hexCount))
+		}
+		hexCount++
+		hex, hexErr := resource.NewUniqueName(hexSeed, "", n, 0, nil)
+		if hexErr != nil {
+			err = hexErr
+		}
+		return hex
+	})
+	return name, err
+}
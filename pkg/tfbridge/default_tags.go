@@ -0,0 +1,268 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// DefaultTagsInfo configures tags set at the provider level that apply to all resources that support tags.
+//
+// The tags of a resource only ever hold the tags set on the resource, in its inputs as in its outputs. The bridge
+// merges the default tags into the tags it sends to the upstream resource, with tags set on the resource taking
+// precedence, and reports the merged tags in a computed output, by default tagsAll. Supported resources get that
+// output unless the upstream resource already has one.
+//
+// Changing the default tags updates the resources. Diff reports such changes on tagsAll rather than on tags.
+type DefaultTagsInfo struct {
+	// The Pulumi name of the provider configuration property holding the default tags, a map of strings. It may
+	// be an upstream provider property or a Pulumi-only property declared in [ProviderInfo.ExtraConfig].
+	ConfigKey string
+
+	// The resources that support tags, keyed by Terraform resource type.
+	Resources map[string]*ResourceTagsInfo
+}
+
+// ResourceTagsInfo describes the tags properties of a resource. See [DefaultTagsInfo].
+type ResourceTagsInfo struct {
+	// The Terraform name of the property holding the tags of the resource, a map of strings. Defaults to "tags".
+	Tags string
+
+	// The Terraform name of the computed property holding the tags of the resource merged with the default
+	// tags. Defaults to "tags_all". If the upstream resource does not have such a property, the bridge adds it to
+	// the outputs of the resource.
+	TagsAll string
+}
+
+// ResourceDefaultTags applies [DefaultTagsInfo] to a resource. A nil *ResourceDefaultTags applies nothing.
+type ResourceDefaultTags struct {
+	configKey resource.PropertyKey
+	tags      resource.PropertyKey
+	// merged is the output holding the merged tags, which either the bridge or the upstream resource maintains.
+	merged resource.PropertyKey
+	// tagsAll is empty if the upstream resource computes the merged tags itself.
+	tagsAll   resource.PropertyKey
+	tagsAllTF string
+}
+
+// NewResourceDefaultTags returns the default tags behavior of the resource tfName, or nil if info does not cover it.
+func NewResourceDefaultTags(
+	info *DefaultTagsInfo, tfName string, schema shim.SchemaMap, fields map[string]*SchemaInfo,
+) *ResourceDefaultTags {
+	if info == nil || schema == nil {
+		return nil
+	}
+	r, ok := info.Resources[tfName]
+	if !ok {
+		return nil
+	}
+	tags, tagsAll := "tags", "tags_all"
+	if r != nil && r.Tags != "" {
+		tags = r.Tags
+	}
+	if r != nil && r.TagsAll != "" {
+		tagsAll = r.TagsAll
+	}
+
+	t := &ResourceDefaultTags{
+		configKey: resource.PropertyKey(info.ConfigKey),
+		tags:      resource.PropertyKey(TerraformToPulumiNameV2(tags, schema, fields)),
+		merged:    resource.PropertyKey(TerraformToPulumiNameV2(tagsAll, schema, fields)),
+	}
+	if _, upstream := schema.GetOk(tagsAll); !upstream {
+		t.tagsAll = t.merged
+		t.tagsAllTF = tagsAll
+	}
+	return t
+}
+
+// TagsAll returns the Pulumi name of the merged tags output added by the bridge, or "" if there is none.
+func (t *ResourceDefaultTags) TagsAll() resource.PropertyKey {
+	if t == nil {
+		return ""
+	}
+	return t.tagsAll
+}
+
+// TagsAllTerraformName is the Terraform name of the merged tags output added by the bridge, or "" if there is none.
+func (t *ResourceDefaultTags) TagsAllTerraformName() string {
+	if t == nil {
+		return ""
+	}
+	return t.tagsAllTF
+}
+
+// Merge merges the default tags from the provider configuration config into the resource inputs news, for use as the
+// configuration of the upstream resource. The merged inputs are never returned to Pulumi.
+//
+// The inputs are left as is when there are no default tags, so that configuring no defaults never turns missing
+// tags into empty ones. Unknown default tags make the tags unknown.
+func (t *ResourceDefaultTags) Merge(news, config resource.PropertyMap) resource.PropertyMap {
+	if t == nil {
+		return news
+	}
+	defaults, ok := config[t.configKey]
+	if !ok || defaults.IsNull() {
+		return news
+	}
+	tags := news[t.tags]
+	secret := defaults.IsSecret() || tags.IsSecret()
+	defaults, tags = unwrapSecret(defaults), unwrapSecret(tags)
+	switch {
+	case tags.IsComputed():
+		return news
+	case defaults.IsComputed():
+		result := news.Copy()
+		result[t.tags] = resource.MakeComputed(resource.NewStringProperty(""))
+		return result
+	case !defaults.IsObject() || !(tags.IsObject() || tags.IsNull()):
+		// Malformed maps are reported by CheckConfig and Check.
+		return news
+	case len(defaults.ObjectValue()) == 0:
+		return news
+	}
+
+	merged := defaults.ObjectValue().Copy()
+	if tags.IsObject() {
+		for k, v := range tags.ObjectValue() {
+			merged[k] = v
+		}
+	}
+	result := news.Copy()
+	result[t.tags] = resource.NewObjectProperty(merged)
+	if secret {
+		result[t.tags] = resource.MakeSecret(result[t.tags])
+	}
+	return result
+}
+
+// SplitOutputs separates the default tags from the outputs of the resource, which hold the merged tags stored by the
+// upstream resource. The merged tags go to tagsAll, unless the upstream resource maintains it, and tags keeps the
+// tags set on the resource: those set in inputs, and those whose value differs from the default tags in config.
+//
+// inputs are the resource inputs before Merge, or nil if they are not known, as in an import.
+func (t *ResourceDefaultTags) SplitOutputs(outputs, inputs, config resource.PropertyMap) resource.PropertyMap {
+	if t == nil || outputs == nil {
+		return outputs
+	}
+	tags, ok := outputs[t.tags]
+	if !ok || tags.IsNull() {
+		tags = resource.NewObjectProperty(resource.PropertyMap{})
+	}
+	result := outputs.Copy()
+	if t.tagsAll != "" {
+		result[t.tagsAll] = tags
+	}
+
+	defaults := unwrapSecret(config[t.configKey])
+	secret := tags.IsSecret()
+	tags = unwrapSecret(tags)
+	if !defaults.IsObject() || !tags.IsObject() {
+		return result
+	}
+	set := unwrapSecret(inputs[t.tags])
+	own := tags.ObjectValue().Copy()
+	for k, v := range defaults.ObjectValue() {
+		if set.IsObject() {
+			if _, ok := set.ObjectValue()[k]; ok {
+				continue
+			}
+		}
+		if o, ok := own[k]; ok && o.DeepEquals(v) {
+			delete(own, k)
+		}
+	}
+	switch {
+	case len(own) == 0 && !set.IsObject():
+		result[t.tags] = resource.NewNullProperty()
+	case secret:
+		result[t.tags] = resource.MakeSecret(resource.NewObjectProperty(own))
+	default:
+		result[t.tags] = resource.NewObjectProperty(own)
+	}
+	return result
+}
+
+// JoinState undoes SplitOutputs on a resource state before it reaches the upstream resource, which expects the merged
+// tags it stored: tags gets the merged tags, and tagsAll is removed unless the upstream resource maintains it.
+func (t *ResourceDefaultTags) JoinState(state resource.PropertyMap) resource.PropertyMap {
+	if t == nil {
+		return state
+	}
+	merged, ok := state[t.merged]
+	if !ok {
+		return state
+	}
+	result := state.Copy()
+	if m := unwrapSecret(merged); m.IsObject() && len(m.ObjectValue()) > 0 {
+		result[t.tags] = merged
+	}
+	if t.tagsAll != "" {
+		delete(result, t.tagsAll)
+	}
+	return result
+}
+
+// DiffKey returns the property that Diff reports a change of the merged tags at path on. Changes that come only from
+// the default tags, where the tags set on the resource are the same in olds and news, are reported on tagsAll; all
+// other changes are reported on path.
+//
+// olds are the prior outputs of the resource, before JoinState, and news its inputs, before Merge.
+func (t *ResourceDefaultTags) DiffKey(
+	path resource.PropertyPath, olds, news resource.PropertyMap,
+) resource.PropertyPath {
+	if t == nil || len(path) == 0 || path[0] != string(t.tags) {
+		return path
+	}
+	oldTags, newTags := unwrapSecret(olds[t.tags]), unwrapSecret(news[t.tags])
+	if len(path) > 1 {
+		k, ok := path[1].(string)
+		if !ok {
+			return path
+		}
+		oldTags, newTags = tagValue(oldTags, k), tagValue(newTags, k)
+	}
+	if !sameTags(oldTags, newTags) {
+		return path
+	}
+	return append(resource.PropertyPath{string(t.merged)}, path[1:]...)
+}
+
+func tagValue(tags resource.PropertyValue, k string) resource.PropertyValue {
+	if !tags.IsObject() {
+		return resource.NewNullProperty()
+	}
+	return unwrapSecret(tags.ObjectValue()[resource.PropertyKey(k)])
+}
+
+// sameTags compares tags, considering missing, null and empty tags equal.
+func sameTags(a, b resource.PropertyValue) bool {
+	empty := func(v resource.PropertyValue) bool {
+		return v.IsNull() || (v.IsObject() && len(v.ObjectValue()) == 0)
+	}
+	if empty(a) || empty(b) {
+		return empty(a) && empty(b)
+	}
+	return a.DeepEquals(b)
+}
+
+func unwrapSecret(v resource.PropertyValue) resource.PropertyValue {
+	for v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+	return v
+}
@@ -0,0 +1,423 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutils "github.com/pulumi/providertest/replay"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+func tagsResourceSchema(withTagsAll bool) map[string]*schemav2.Schema {
+	s := map[string]*schemav2.Schema{
+		"name": {Type: schemav2.TypeString, Optional: true},
+		"tags": {Type: schemav2.TypeMap, Optional: true, Elem: &schemav2.Schema{Type: schemav2.TypeString}},
+	}
+	if withTagsAll {
+		s["tags_all"] = &schemav2.Schema{
+			Type: schemav2.TypeMap, Computed: true, Elem: &schemav2.Schema{Type: schemav2.TypeString},
+		}
+	}
+	return s
+}
+
+func TestNewResourceDefaultTags(t *testing.T) {
+	t.Parallel()
+	info := &DefaultTagsInfo{
+		ConfigKey: "defaultTags",
+		Resources: map[string]*ResourceTagsInfo{
+			"res":        nil,
+			"labeled":    {Tags: "labels", TagsAll: "effective_labels"},
+			"upstreamed": {},
+		},
+	}
+
+	assert.Nil(t, NewResourceDefaultTags(nil, "res", shimv2.NewSchemaMap(tagsResourceSchema(false)), nil))
+	assert.Nil(t, NewResourceDefaultTags(info, "other", shimv2.NewSchemaMap(tagsResourceSchema(false)), nil))
+
+	tags := NewResourceDefaultTags(info, "res", shimv2.NewSchemaMap(tagsResourceSchema(false)), nil)
+	assert.Equal(t, resource.PropertyKey("tagsAll"), tags.TagsAll())
+	assert.Equal(t, "tags_all", tags.TagsAllTerraformName())
+
+	labels := NewResourceDefaultTags(info, "labeled", shimv2.NewSchemaMap(map[string]*schemav2.Schema{
+		"labels": {Type: schemav2.TypeMap, Optional: true, Elem: &schemav2.Schema{Type: schemav2.TypeString}},
+	}), nil)
+	assert.Equal(t, resource.PropertyKey("effectiveLabels"), labels.TagsAll())
+
+	// The upstream resource maintains tags_all, so the bridge does not add it.
+	upstreamed := NewResourceDefaultTags(info, "upstreamed", shimv2.NewSchemaMap(tagsResourceSchema(true)), nil)
+	require.NotNil(t, upstreamed)
+	assert.Equal(t, resource.PropertyKey(""), upstreamed.TagsAll())
+}
+
+func TestResourceDefaultTagsMerge(t *testing.T) {
+	t.Parallel()
+	tags := NewResourceDefaultTags(&DefaultTagsInfo{
+		ConfigKey: "defaultTags",
+		Resources: map[string]*ResourceTagsInfo{"res": {}},
+	}, "res", shimv2.NewSchemaMap(tagsResourceSchema(false)), nil)
+
+	obj := func(kv ...string) resource.PropertyValue {
+		m := resource.PropertyMap{}
+		for i := 0; i < len(kv); i += 2 {
+			m[resource.PropertyKey(kv[i])] = resource.NewStringProperty(kv[i+1])
+		}
+		return resource.NewObjectProperty(m)
+	}
+	computed := resource.MakeComputed(resource.NewStringProperty(""))
+
+	tests := []struct {
+		name     string
+		defaults *resource.PropertyValue
+		tags     *resource.PropertyValue
+		expected *resource.PropertyValue
+	}{
+		{name: "no defaults", tags: ref(obj("a", "1")), expected: ref(obj("a", "1"))},
+		{name: "empty defaults", defaults: ref(obj()), expected: nil},
+		{name: "defaults only", defaults: ref(obj("env", "dev")), expected: ref(obj("env", "dev"))},
+		{
+			name:     "resource tags win",
+			defaults: ref(obj("env", "dev", "team", "x")),
+			tags:     ref(obj("env", "prod")),
+			expected: ref(obj("env", "prod", "team", "x")),
+		},
+		{name: "unknown defaults", defaults: &computed, tags: ref(obj("a", "1")), expected: &computed},
+		{name: "unknown tags", defaults: ref(obj("env", "dev")), tags: &computed, expected: &computed},
+		{
+			name:     "secret defaults",
+			defaults: ref(resource.MakeSecret(obj("env", "dev"))),
+			tags:     ref(obj("a", "1")),
+			expected: ref(resource.MakeSecret(obj("env", "dev", "a", "1"))),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			config := resource.PropertyMap{}
+			if tt.defaults != nil {
+				config["defaultTags"] = *tt.defaults
+			}
+			news := resource.PropertyMap{"name": resource.NewStringProperty("n")}
+			if tt.tags != nil {
+				news["tags"] = *tt.tags
+			}
+
+			actual := tags.Merge(news, config)
+
+			expected := resource.PropertyMap{"name": resource.NewStringProperty("n")}
+			if tt.expected != nil {
+				expected["tags"] = *tt.expected
+			}
+			assert.Equal(t, expected, actual)
+		})
+	}
+}
+
+func TestResourceDefaultTagsSplitAndJoin(t *testing.T) {
+	t.Parallel()
+	tags := NewResourceDefaultTags(&DefaultTagsInfo{
+		ConfigKey: "defaultTags",
+		Resources: map[string]*ResourceTagsInfo{"res": {}},
+	}, "res", shimv2.NewSchemaMap(tagsResourceSchema(false)), nil)
+
+	obj := func(kv ...string) resource.PropertyValue {
+		m := resource.PropertyMap{}
+		for i := 0; i < len(kv); i += 2 {
+			m[resource.PropertyKey(kv[i])] = resource.NewStringProperty(kv[i+1])
+		}
+		return resource.NewObjectProperty(m)
+	}
+	config := resource.PropertyMap{"defaultTags": obj("env", "dev", "team", "infra")}
+	upstream := resource.PropertyMap{"tags": obj("env", "dev", "team", "infra", "app", "web")}
+
+	// A tag set on the resource with the default value stays in tags.
+	outputs := tags.SplitOutputs(upstream, resource.PropertyMap{"tags": obj("env", "dev", "app", "web")}, config)
+	assert.Equal(t, resource.PropertyMap{
+		"tags":    obj("env", "dev", "app", "web"),
+		"tagsAll": obj("env", "dev", "team", "infra", "app", "web"),
+	}, outputs)
+	assert.Equal(t, upstream, tags.JoinState(outputs))
+
+	// Without inputs, as in an import, tags with the default value are default tags.
+	outputs = tags.SplitOutputs(upstream, nil, config)
+	assert.Equal(t, obj("app", "web"), outputs["tags"])
+	assert.Equal(t, upstream, tags.JoinState(outputs))
+
+	// Resources without tags of their own have no tags.
+	outputs = tags.SplitOutputs(resource.PropertyMap{"tags": obj("env", "dev", "team", "infra")},
+		resource.PropertyMap{}, config)
+	assert.Equal(t, resource.NewNullProperty(), outputs["tags"])
+}
+
+func TestResourceDefaultTagsDiffKey(t *testing.T) {
+	t.Parallel()
+	tags := NewResourceDefaultTags(&DefaultTagsInfo{
+		ConfigKey: "defaultTags",
+		Resources: map[string]*ResourceTagsInfo{"res": {}},
+	}, "res", shimv2.NewSchemaMap(tagsResourceSchema(false)), nil)
+
+	tagsOf := func(kv ...string) resource.PropertyMap {
+		m := resource.PropertyMap{}
+		for i := 0; i < len(kv); i += 2 {
+			m[resource.PropertyKey(kv[i])] = resource.NewStringProperty(kv[i+1])
+		}
+		return resource.PropertyMap{"tags": resource.NewObjectProperty(m)}
+	}
+
+	tests := []struct {
+		path       string
+		olds, news resource.PropertyMap
+		expected   string
+	}{
+		{path: "name", olds: tagsOf(), news: tagsOf(), expected: "name"},
+		{path: "tags.team", olds: tagsOf("env", "dev"), news: tagsOf("env", "dev"), expected: "tagsAll.team"},
+		{path: "tags.env", olds: tagsOf("env", "dev"), news: tagsOf("env", "prod"), expected: "tags.env"},
+		{path: "tags.team", olds: tagsOf("env", "dev"), news: tagsOf("env", "prod"), expected: "tagsAll.team"},
+		{path: "tags", olds: resource.PropertyMap{}, news: tagsOf(), expected: "tagsAll"},
+		{path: "tags", olds: tagsOf(), news: tagsOf("env", "dev"), expected: "tags"},
+	}
+
+	for _, tt := range tests {
+		path, err := resource.ParsePropertyPath(tt.path)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, tags.DiffKey(path, tt.olds, tt.news).String(), tt.path)
+	}
+}
+
+func TestDefaultTagsProvider(t *testing.T) {
+	var upstreamTags map[string]interface{}
+	p := &schemav2.Provider{
+		Schema: map[string]*schemav2.Schema{},
+		ResourcesMap: map[string]*schemav2.Resource{
+			"res": {
+				Schema: tagsResourceSchema(false),
+				CreateContext: func(_ context.Context, rd *schemav2.ResourceData, _ any) diag.Diagnostics {
+					upstreamTags = rd.Get("tags").(map[string]interface{})
+					rd.SetId("r1")
+					return nil
+				},
+				ReadContext: func(context.Context, *schemav2.ResourceData, any) diag.Diagnostics {
+					return nil
+				},
+				UpdateContext: func(_ context.Context, rd *schemav2.ResourceData, _ any) diag.Diagnostics {
+					upstreamTags = rd.Get("tags").(map[string]interface{})
+					return nil
+				},
+				DeleteContext: func(context.Context, *schemav2.ResourceData, any) diag.Diagnostics {
+					return nil
+				},
+			},
+		},
+	}
+	shimProv := shimv2.NewProvider(p)
+	provider := func() *Provider {
+		return &Provider{
+			tf:     shimProv,
+			config: shimv2.NewSchemaMap(p.Schema),
+			info: ProviderInfo{
+				P: shimProv,
+				ExtraConfig: map[string]*ConfigInfo{
+					"defaultTags": {
+						Schema: shimv2.NewSchema(&schemav2.Schema{
+							Type:     schemav2.TypeMap,
+							Optional: true,
+							Elem:     &schemav2.Schema{Type: schemav2.TypeString},
+						}),
+					},
+				},
+				DefaultTags: &DefaultTagsInfo{
+					ConfigKey: "defaultTags",
+					Resources: map[string]*ResourceTagsInfo{"res": {}},
+				},
+			},
+			resources: map[tokens.Type]Resource{
+				"Res": {
+					TF:     shimv2.NewResource(p.ResourcesMap["res"]),
+					TFName: "res",
+					Schema: &ResourceInfo{Tok: "Res"},
+				},
+			},
+		}
+	}
+
+	configure := func(defaults string) string {
+		return `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Configure",
+		  "request": {
+		    "args": {"defaultTags": ` + defaults + `},
+		    "variables": {}
+		  },
+		  "response": {
+		    "supportsPreview": true
+		  }
+		}`
+	}
+	defaults := configure(`{"env": "dev", "team": "infra"}`)
+
+	t.Run("Check keeps default tags out of tags", func(t *testing.T) {
+		testutils.ReplaySequence(t, provider(), `[`+defaults+`,
+		{
+		  "method": "/pulumirpc.ResourceProvider/Check",
+		  "request": {
+		    "urn": "urn:pulumi:dev::teststack::Res::exres",
+		    "olds": {},
+		    "news": {"tags": {"env": "prod"}},
+		    "randomSeed": "iYRxB6/8Mm7pwKIs+yK6IyMDmW9JSSTM6klzRUgZhRk="
+		  },
+		  "response": {
+		    "inputs": {
+		      "__defaults": [],
+		      "tags": {"env": "prod"}
+		    }
+		  }
+		}]`)
+	})
+
+	t.Run("Create applies default tags and emits tagsAll", func(t *testing.T) {
+		testutils.ReplaySequence(t, provider(), `[`+defaults+`,
+		{
+		  "method": "/pulumirpc.ResourceProvider/Create",
+		  "request": {
+		    "urn": "urn:pulumi:dev::teststack::Res::exres",
+		    "properties": {"tags": {"env": "prod"}}
+		  },
+		  "response": {
+		    "id": "r1",
+		    "properties": {
+		      "id": "r1",
+		      "tags": {"env": "prod"},
+		      "tagsAll": {"env": "prod", "team": "infra"}
+		    }
+		  }
+		}]`)
+		assert.Equal(t, map[string]interface{}{"env": "prod", "team": "infra"}, upstreamTags)
+	})
+
+	state := `{
+	  "id": "r1",
+	  "name": "",
+	  "tags": {"env": "prod"},
+	  "tagsAll": {"env": "prod", "team": "infra"}
+	}`
+
+	t.Run("Unchanged tags do not cause a diff", func(t *testing.T) {
+		testutils.ReplaySequence(t, provider(), `[`+defaults+`,
+		{
+		  "method": "/pulumirpc.ResourceProvider/Diff",
+		  "request": {
+		    "id": "r1",
+		    "urn": "urn:pulumi:dev::teststack::Res::exres",
+		    "olds": `+state+`,
+		    "news": {"tags": {"env": "prod"}}
+		  },
+		  "response": {
+		    "changes": "DIFF_NONE",
+		    "hasDetailedDiff": true
+		  }
+		}]`)
+	})
+
+	t.Run("Changed default tags are reported on tagsAll", func(t *testing.T) {
+		testutils.ReplaySequence(t, provider(), `[`+configure(`{"env": "dev", "team": "platform"}`)+`,
+		{
+		  "method": "/pulumirpc.ResourceProvider/Diff",
+		  "request": {
+		    "id": "r1",
+		    "urn": "urn:pulumi:dev::teststack::Res::exres",
+		    "olds": `+state+`,
+		    "news": {"tags": {"env": "prod"}}
+		  },
+		  "response": {
+		    "changes": "DIFF_SOME",
+		    "diffs": ["tagsAll"],
+		    "detailedDiff": {"tagsAll.team": {"kind": "UPDATE"}},
+		    "hasDetailedDiff": true
+		  }
+		}]`)
+	})
+
+	t.Run("Changed resource tags are reported on tags", func(t *testing.T) {
+		testutils.ReplaySequence(t, provider(), `[`+defaults+`,
+		{
+		  "method": "/pulumirpc.ResourceProvider/Diff",
+		  "request": {
+		    "id": "r1",
+		    "urn": "urn:pulumi:dev::teststack::Res::exres",
+		    "olds": `+state+`,
+		    "news": {"tags": {"env": "staging"}}
+		  },
+		  "response": {
+		    "changes": "DIFF_SOME",
+		    "diffs": ["tags"],
+		    "detailedDiff": {"tags.env": {"kind": "UPDATE"}},
+		    "hasDetailedDiff": true
+		  }
+		}]`)
+	})
+
+	t.Run("Update applies new default tags", func(t *testing.T) {
+		testutils.ReplaySequence(t, provider(), `[`+configure(`{"env": "dev", "team": "platform"}`)+`,
+		{
+		  "method": "/pulumirpc.ResourceProvider/Update",
+		  "request": {
+		    "id": "r1",
+		    "urn": "urn:pulumi:dev::teststack::Res::exres",
+		    "olds": `+state+`,
+		    "news": {"tags": {"env": "prod"}}
+		  },
+		  "response": {
+		    "properties": {
+		      "id": "r1",
+		      "name": "",
+		      "tags": {"env": "prod"},
+		      "tagsAll": {"env": "prod", "team": "platform"}
+		    }
+		  }
+		}]`)
+		assert.Equal(t, map[string]interface{}{"env": "prod", "team": "platform"}, upstreamTags)
+	})
+
+	t.Run("Read keeps default tags out of tags", func(t *testing.T) {
+		testutils.ReplaySequence(t, provider(), `[`+defaults+`,
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "r1",
+		    "urn": "urn:pulumi:dev::teststack::Res::exres",
+		    "properties": `+state+`,
+		    "inputs": {"tags": {"env": "prod"}}
+		  },
+		  "response": {
+		    "id": "r1",
+		    "inputs": {
+		      "tags": {"env": "prod"}
+		    },
+		    "properties": `+state+`
+		  }
+		}]`)
+	})
+}
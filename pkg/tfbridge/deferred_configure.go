@@ -0,0 +1,409 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/propertyvalue"
+)
+
+// ErrConfigurationUnknown is returned by operations that need a configured provider while the configuration of the
+// provider is deferred. See [ProviderInfo.DeferConfigureWithUnknowns].
+var ErrConfigurationUnknown = errors.New("the provider configuration contains unknown values")
+
+// DeferredConfigureError reports that op needs a configured provider while the configuration is deferred.
+func DeferredConfigureError(op string) error {
+	return fmt.Errorf("cannot %s: %w", op, ErrConfigurationUnknown)
+}
+
+// DeferredPreviewOutputs computes the outputs of a resource from its inputs and schema alone, without calling the
+// upstream provider. It is used to preview Create and Update while the configuration of the provider is deferred:
+// the outputs are the inputs, with all computed properties that are not set in the inputs, and the ID, unknown.
+func DeferredPreviewOutputs(
+	schema shim.SchemaMap, fields map[string]*SchemaInfo, inputs resource.PropertyMap,
+) resource.PropertyMap {
+	unknown := resource.MakeComputed(resource.NewStringProperty(""))
+	outputs := inputs.Copy()
+	delete(outputs, defaultsKey)
+	schema.Range(func(tfName string, s shim.Schema) bool {
+		key := resource.PropertyKey(TerraformToPulumiNameV2(tfName, schema, fields))
+		if s.Computed() {
+			if v, ok := outputs[key]; !ok || v.IsNull() || !s.Optional() {
+				outputs[key] = unknown
+			}
+		}
+		return true
+	})
+	outputs["id"] = unknown
+	return outputs
+}
+
+// DeferredDiff diffs a resource from its schema alone, without calling the upstream provider. It is used while the
+// configuration of the provider is deferred.
+//
+// Only the input properties of the resource are compared, nested blocks included: computed properties missing from
+// news are assumed unchanged, null and empty values are treated alike, properties with unknown values are assumed
+// changed, and changes to ForceNew properties, or to properties nested in them, require a replacement.
+func DeferredDiff(
+	schema shim.SchemaMap, fields map[string]*SchemaInfo, olds, news resource.PropertyMap, ignoreChanges []string,
+) (plugin.DiffResult, error) {
+	news, err := propertyvalue.ApplyIgnoreChanges(olds, news, ignoreChanges)
+	if err != nil {
+		return plugin.DiffResult{}, fmt.Errorf("failed to apply ignore changes: %w", err)
+	}
+
+	oldInputs, newInputs := deferredInputs(schema, fields, olds, news)
+	result := plugin.DiffResult{
+		Changes:      plugin.DiffNone,
+		DetailedDiff: plugin.NewDetailedDiffFromObjectDiff(oldInputs.DiffIncludeUnknowns(newInputs), true),
+	}
+	deferredUnknowns(nil, resource.NewObjectProperty(newInputs), func(path resource.PropertyPath) {
+		result.DetailedDiff[path.String()] = plugin.PropertyDiff{Kind: plugin.DiffUpdate, InputDiff: true}
+	})
+	changed, replaced := map[resource.PropertyKey]bool{}, map[resource.PropertyKey]bool{}
+	for path, d := range result.DetailedDiff {
+		p, err := resource.ParsePropertyPath(path)
+		if err != nil || len(p) == 0 {
+			continue
+		}
+		key, ok := p[0].(string)
+		if !ok {
+			continue
+		}
+		changed[resource.PropertyKey(key)] = true
+		if deferredRequiresReplace(schema, fields, p, oldInputs, newInputs) {
+			result.DetailedDiff[path] = d.ToReplace()
+			replaced[resource.PropertyKey(key)] = true
+		}
+	}
+	for k := range changed {
+		result.ChangedKeys = append(result.ChangedKeys, k)
+		if replaced[k] {
+			result.ReplaceKeys = append(result.ReplaceKeys, k)
+		}
+	}
+	sort.Slice(result.ChangedKeys, func(i, j int) bool { return result.ChangedKeys[i] < result.ChangedKeys[j] })
+	sort.Slice(result.ReplaceKeys, func(i, j int) bool { return result.ReplaceKeys[i] < result.ReplaceKeys[j] })
+	if len(changed) > 0 {
+		result.Changes = plugin.DiffSome
+	}
+	return result, nil
+}
+
+// deferredInputs returns the input properties of olds and news that DeferredDiff compares. Computed properties that
+// are not set in news are dropped from both, nested blocks are compared recursively, and null and empty values and
+// secrets are normalized away.
+func deferredInputs(
+	tfs shim.SchemaMap, ps map[string]*SchemaInfo, olds, news resource.PropertyMap,
+) (resource.PropertyMap, resource.PropertyMap) {
+	oldInputs, newInputs := resource.PropertyMap{}, resource.PropertyMap{}
+	tfs.Range(func(tfName string, s shim.Schema) bool {
+		if !s.Optional() && !s.Required() {
+			return true
+		}
+		key, _, info := getInfoFromTerraformName(tfName, tfs, ps, false)
+		newValue := deferredValue(news[key])
+		if newValue.IsNull() && s.Computed() {
+			return true
+		}
+		oldValue, newValue := deferredInput(s, info, deferredValue(olds[key]), newValue)
+		if !oldValue.IsNull() {
+			oldInputs[key] = oldValue
+		}
+		if !newValue.IsNull() {
+			newInputs[key] = newValue
+		}
+		return true
+	})
+	return oldInputs, newInputs
+}
+
+// deferredInput returns the input-shaped old and new values of the property s for DeferredDiff.
+func deferredInput(s shim.Schema, info *SchemaInfo, old, new resource.PropertyValue) (
+	resource.PropertyValue, resource.PropertyValue,
+) {
+	old, new = deferredValue(old), deferredValue(new)
+	res, isBlock := s.Elem().(shim.Resource)
+	switch {
+	case isBlock && deferredIsObject(old) && deferredIsObject(new):
+		var fields map[string]*SchemaInfo
+		if info != nil {
+			fields = info.Fields
+		}
+		o, n := deferredInputs(res.Schema(), fields, deferredObject(old), deferredObject(new))
+		return deferredValue(resource.NewObjectProperty(o)), deferredValue(resource.NewObjectProperty(n))
+	case deferredIsArray(old) && deferredIsArray(new):
+		var os, ns []resource.PropertyValue
+		for i := 0; i < len(deferredArray(old)) || i < len(deferredArray(new)); i++ {
+			o, n := deferredElem(old, i), deferredElem(new, i)
+			if isBlock {
+				// The elements of a block are objects, which deferredInput compares with the schema of the block.
+				o, n = deferredInput(s, info, o, n)
+			} else if es, einfo := elemSchemas(s, info); es != nil {
+				o, n = deferredInput(es, einfo, o, n)
+			}
+			if i < len(deferredArray(old)) {
+				os = append(os, o)
+			}
+			if i < len(deferredArray(new)) {
+				ns = append(ns, n)
+			}
+		}
+		return deferredValue(resource.NewArrayProperty(os)), deferredValue(resource.NewArrayProperty(ns))
+	case !isBlock && deferredIsObject(old) && deferredIsObject(new):
+		es, einfo := elemSchemas(s, info)
+		if es == nil {
+			return old, new
+		}
+		os, ns := resource.PropertyMap{}, resource.PropertyMap{}
+		for k, v := range deferredObject(old) {
+			os[k], ns[k] = deferredInput(es, einfo, v, deferredObject(new)[k])
+		}
+		for k, v := range deferredObject(new) {
+			if _, ok := os[k]; !ok {
+				os[k], ns[k] = deferredInput(es, einfo, resource.NewNullProperty(), v)
+			}
+		}
+		for k := range os {
+			if os[k].IsNull() {
+				delete(os, k)
+			}
+			if ns[k].IsNull() {
+				delete(ns, k)
+			}
+		}
+		return deferredValue(resource.NewObjectProperty(os)), deferredValue(resource.NewObjectProperty(ns))
+	default:
+		return old, new
+	}
+}
+
+// deferredUnknowns calls f with the path of every unknown value in v.
+func deferredUnknowns(path resource.PropertyPath, v resource.PropertyValue, f func(resource.PropertyPath)) {
+	switch {
+	case v.IsComputed() || v.IsOutput() && !v.OutputValue().Known:
+		f(path)
+	case v.IsArray():
+		for i, e := range v.ArrayValue() {
+			deferredUnknowns(append(append(resource.PropertyPath{}, path...), i), e, f)
+		}
+	case v.IsObject():
+		for k, e := range v.ObjectValue() {
+			deferredUnknowns(append(append(resource.PropertyPath{}, path...), string(k)), e, f)
+		}
+	}
+}
+
+// deferredValue removes the secret marker from v and returns null for empty arrays and objects.
+func deferredValue(v resource.PropertyValue) resource.PropertyValue {
+	for v.IsSecret() {
+		v = v.SecretValue().Element
+	}
+	switch {
+	case v.V == nil,
+		v.IsArray() && len(v.ArrayValue()) == 0,
+		v.IsObject() && len(v.ObjectValue()) == 0:
+		return resource.NewNullProperty()
+	}
+	return v
+}
+
+func deferredIsObject(v resource.PropertyValue) bool { return v.IsNull() || v.IsObject() }
+
+func deferredIsArray(v resource.PropertyValue) bool { return v.IsNull() || v.IsArray() }
+
+func deferredObject(v resource.PropertyValue) resource.PropertyMap {
+	if v.IsObject() {
+		return v.ObjectValue()
+	}
+	return resource.PropertyMap{}
+}
+
+func deferredArray(v resource.PropertyValue) []resource.PropertyValue {
+	if v.IsArray() {
+		return v.ArrayValue()
+	}
+	return nil
+}
+
+func deferredElem(v resource.PropertyValue, i int) resource.PropertyValue {
+	if arr := deferredArray(v); i < len(arr) {
+		return arr[i]
+	}
+	return resource.NewNullProperty()
+}
+
+// deferredRequiresReplace reports whether the change at path requires a replacement: when the property at path or
+// one of its parents is ForceNew, or when a ForceNew property nested in it changes.
+func deferredRequiresReplace(
+	tfs shim.SchemaMap, ps map[string]*SchemaInfo, path resource.PropertyPath, olds, news resource.PropertyMap,
+) bool {
+	// s is nil while the path is at an object of tfs, and the schema of the property at the path otherwise.
+	var s shim.Schema
+	var info *SchemaInfo
+	for i := 0; i < len(path); {
+		if s == nil {
+			key, ok := path[i].(string)
+			if !ok {
+				return false
+			}
+			if _, s, info = getInfoFromPulumiName(resource.PropertyKey(key), tfs, ps, false); s == nil {
+				return false
+			}
+			if s.ForceNew() {
+				return true
+			}
+			i++
+			continue
+		}
+		res, isBlock := s.Elem().(shim.Resource)
+		if !isBlock {
+			if s, info = elemSchemas(s, info); s == nil {
+				return false
+			}
+			if s.ForceNew() {
+				return true
+			}
+			i++
+			continue
+		}
+		tfs, ps = res.Schema(), nil
+		if info != nil {
+			ps = info.Fields
+		}
+		// The elements of a list block are indexed, those of a MaxItemsOne block are not.
+		if _, isIndex := path[i].(int); isIndex {
+			i++
+		}
+		s, info = nil, nil
+	}
+
+	oldValue, _ := path.Get(resource.NewObjectProperty(olds))
+	newValue, _ := path.Get(resource.NewObjectProperty(news))
+	if s == nil {
+		return deferredForceNewObjectChanged(tfs, ps, oldValue, newValue)
+	}
+	return deferredForceNewChanged(s, info, oldValue, newValue)
+}
+
+// deferredForceNewChanged reports whether s is ForceNew and changes, or contains a ForceNew property that changes.
+func deferredForceNewChanged(s shim.Schema, info *SchemaInfo, old, new resource.PropertyValue) bool {
+	old, new = deferredValue(old), deferredValue(new)
+	if s.ForceNew() {
+		return !old.DeepEquals(new)
+	}
+	res, isBlock := s.Elem().(shim.Resource)
+	if !isBlock {
+		return false
+	}
+	var fields map[string]*SchemaInfo
+	if info != nil {
+		fields = info.Fields
+	}
+	if new.IsComputed() {
+		return deferredHasForceNew(res.Schema())
+	}
+	if old.IsObject() || new.IsObject() {
+		return deferredForceNewObjectChanged(res.Schema(), fields, old, new)
+	}
+	for i := 0; i < len(deferredArray(old)) || i < len(deferredArray(new)); i++ {
+		if deferredForceNewObjectChanged(res.Schema(), fields, deferredElem(old, i), deferredElem(new, i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// deferredForceNewObjectChanged reports whether a ForceNew property of an object of tfs changes.
+func deferredForceNewObjectChanged(
+	tfs shim.SchemaMap, ps map[string]*SchemaInfo, old, new resource.PropertyValue,
+) bool {
+	old, new = deferredValue(old), deferredValue(new)
+	if new.IsComputed() {
+		return deferredHasForceNew(tfs)
+	}
+	changed := false
+	tfs.Range(func(tfName string, s shim.Schema) bool {
+		if !s.Optional() && !s.Required() {
+			return true
+		}
+		key, _, info := getInfoFromTerraformName(tfName, tfs, ps, false)
+		changed = deferredForceNewChanged(s, info, deferredObject(old)[key], deferredObject(new)[key])
+		return !changed
+	})
+	return changed
+}
+
+// deferredHasForceNew reports whether tfs has a ForceNew input property, at any depth.
+func deferredHasForceNew(tfs shim.SchemaMap) bool {
+	found := false
+	tfs.Range(func(_ string, s shim.Schema) bool {
+		if !s.Optional() && !s.Required() {
+			return true
+		}
+		if res, ok := s.Elem().(shim.Resource); ok {
+			found = s.ForceNew() || deferredHasForceNew(res.Schema())
+		} else {
+			found = s.ForceNew()
+		}
+		return !found
+	})
+	return found
+}
+
+// makeDiffResponse converts the result of DeferredDiff to a gRPC response.
+func makeDiffResponse(diff plugin.DiffResult) *pulumirpc.DiffResponse {
+	resp := &pulumirpc.DiffResponse{
+		Changes:         pulumirpc.DiffResponse_DIFF_NONE,
+		DetailedDiff:    map[string]*pulumirpc.PropertyDiff{},
+		HasDetailedDiff: true,
+	}
+	if diff.Changes == plugin.DiffSome {
+		resp.Changes = pulumirpc.DiffResponse_DIFF_SOME
+	}
+	for path, d := range diff.DetailedDiff {
+		var kind pulumirpc.PropertyDiff_Kind
+		switch d.Kind {
+		case plugin.DiffAdd:
+			kind = pulumirpc.PropertyDiff_ADD
+		case plugin.DiffAddReplace:
+			kind = pulumirpc.PropertyDiff_ADD_REPLACE
+		case plugin.DiffDelete:
+			kind = pulumirpc.PropertyDiff_DELETE
+		case plugin.DiffDeleteReplace:
+			kind = pulumirpc.PropertyDiff_DELETE_REPLACE
+		case plugin.DiffUpdate:
+			kind = pulumirpc.PropertyDiff_UPDATE
+		case plugin.DiffUpdateReplace:
+			kind = pulumirpc.PropertyDiff_UPDATE_REPLACE
+		}
+		resp.DetailedDiff[path] = &pulumirpc.PropertyDiff{Kind: kind, InputDiff: d.InputDiff}
+	}
+	for _, k := range diff.ChangedKeys {
+		resp.Diffs = append(resp.Diffs, string(k))
+	}
+	for _, k := range diff.ReplaceKeys {
+		resp.Replaces = append(resp.Replaces, string(k))
+	}
+	return resp
+}
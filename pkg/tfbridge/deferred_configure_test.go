@@ -0,0 +1,422 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutils "github.com/pulumi/providertest/replay"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+func deferredResourceSchema() map[string]*schemav2.Schema {
+	return map[string]*schemav2.Schema{
+		"name":     {Type: schemav2.TypeString, Required: true, ForceNew: true},
+		"size":     {Type: schemav2.TypeInt, Optional: true},
+		"region":   {Type: schemav2.TypeString, Optional: true, Computed: true},
+		"endpoint": {Type: schemav2.TypeString, Computed: true},
+	}
+}
+
+func deferredNestedResourceSchema() map[string]*schemav2.Schema {
+	return map[string]*schemav2.Schema{
+		"rule": {
+			Type:     schemav2.TypeList,
+			Optional: true,
+			Elem: &schemav2.Resource{Schema: map[string]*schemav2.Schema{
+				"name": {Type: schemav2.TypeString, Required: true, ForceNew: true},
+				"port": {Type: schemav2.TypeInt, Optional: true},
+				"mode": {Type: schemav2.TypeString, Optional: true, Computed: true},
+				"arn":  {Type: schemav2.TypeString, Computed: true},
+			}},
+		},
+		"placement": {
+			Type:     schemav2.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			ForceNew: true,
+			Elem: &schemav2.Resource{Schema: map[string]*schemav2.Schema{
+				"zone": {Type: schemav2.TypeString, Optional: true},
+			}},
+		},
+		"labels": {Type: schemav2.TypeMap, Optional: true, Elem: &schemav2.Schema{Type: schemav2.TypeString}},
+	}
+}
+
+func TestDeferredPreviewOutputs(t *testing.T) {
+	t.Parallel()
+	schema := shimv2.NewSchemaMap(deferredResourceSchema())
+	unknown := resource.MakeComputed(resource.NewStringProperty(""))
+
+	outputs := DeferredPreviewOutputs(schema, nil, resource.PropertyMap{
+		"name":       resource.NewStringProperty("n"),
+		"region":     resource.NewStringProperty("us-west-2"),
+		"__defaults": resource.NewArrayProperty(nil),
+	})
+
+	assert.Equal(t, resource.PropertyMap{
+		"id":       unknown,
+		"name":     resource.NewStringProperty("n"),
+		"region":   resource.NewStringProperty("us-west-2"),
+		"endpoint": unknown,
+	}, outputs)
+
+	outputs = DeferredPreviewOutputs(schema, nil, resource.PropertyMap{"name": resource.NewStringProperty("n")})
+	assert.Equal(t, unknown, outputs["region"])
+}
+
+func TestDeferredDiff(t *testing.T) {
+	t.Parallel()
+	schema := shimv2.NewSchemaMap(deferredResourceSchema())
+	olds := resource.PropertyMap{
+		"id":       resource.NewStringProperty("r1"),
+		"name":     resource.NewStringProperty("n"),
+		"size":     resource.NewNumberProperty(1),
+		"region":   resource.NewStringProperty("us-west-2"),
+		"endpoint": resource.NewStringProperty("https://example.com"),
+	}
+
+	tests := []struct {
+		name          string
+		news          resource.PropertyMap
+		ignoreChanges []string
+		changes       plugin.DiffChanges
+		changed       []resource.PropertyKey
+		replaced      []resource.PropertyKey
+	}{
+		{
+			name: "no changes, computed properties omitted",
+			news: resource.PropertyMap{
+				"name": resource.NewStringProperty("n"),
+				"size": resource.NewNumberProperty(1),
+			},
+			changes: plugin.DiffNone,
+		},
+		{
+			name: "update",
+			news: resource.PropertyMap{
+				"name": resource.NewStringProperty("n"),
+				"size": resource.NewNumberProperty(2),
+			},
+			changes: plugin.DiffSome,
+			changed: []resource.PropertyKey{"size"},
+		},
+		{
+			name: "removed optional property",
+			news: resource.PropertyMap{
+				"name": resource.NewStringProperty("n"),
+			},
+			changes: plugin.DiffSome,
+			changed: []resource.PropertyKey{"size"},
+		},
+		{
+			name: "unknown ForceNew property",
+			news: resource.PropertyMap{
+				"name": resource.MakeComputed(resource.NewStringProperty("")),
+				"size": resource.NewNumberProperty(1),
+			},
+			changes:  plugin.DiffSome,
+			changed:  []resource.PropertyKey{"name"},
+			replaced: []resource.PropertyKey{"name"},
+		},
+		{
+			name: "ignored change",
+			news: resource.PropertyMap{
+				"name": resource.NewStringProperty("n"),
+				"size": resource.NewNumberProperty(2),
+			},
+			ignoreChanges: []string{"size"},
+			changes:       plugin.DiffNone,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			diff, err := DeferredDiff(schema, nil, olds, tt.news, tt.ignoreChanges)
+			require.NoError(t, err)
+			assert.Equal(t, tt.changes, diff.Changes)
+			assert.Equal(t, tt.changed, diff.ChangedKeys)
+			assert.Equal(t, tt.replaced, diff.ReplaceKeys)
+		})
+	}
+}
+
+func TestDeferredDiffNested(t *testing.T) {
+	t.Parallel()
+	schema := shimv2.NewSchemaMap(deferredNestedResourceSchema())
+	str := resource.NewStringProperty
+	num := resource.NewNumberProperty
+	obj := func(m resource.PropertyMap) resource.PropertyValue { return resource.NewObjectProperty(m) }
+	arr := func(vs ...resource.PropertyValue) resource.PropertyValue { return resource.NewArrayProperty(vs) }
+	rule := func(name string, port float64) resource.PropertyValue {
+		return obj(resource.PropertyMap{"name": str(name), "port": num(port)})
+	}
+	olds := resource.PropertyMap{
+		"id": str("r1"),
+		"rules": arr(obj(resource.PropertyMap{
+			"name": str("a"),
+			"port": num(80),
+			"mode": str("tcp"),
+			"arn":  str("arn:a"),
+		})),
+		"placement": obj(resource.PropertyMap{"zone": str("z1")}),
+	}
+
+	tests := []struct {
+		name     string
+		news     resource.PropertyMap
+		changes  plugin.DiffChanges
+		changed  []resource.PropertyKey
+		replaced []resource.PropertyKey
+		detailed map[string]plugin.DiffKind
+	}{
+		{
+			name: "nested computed properties omitted",
+			news: resource.PropertyMap{
+				"rules":     arr(rule("a", 80)),
+				"placement": obj(resource.PropertyMap{"zone": str("z1")}),
+			},
+			changes: plugin.DiffNone,
+		},
+		{
+			name: "null and empty are the same",
+			news: resource.PropertyMap{
+				"rules":     arr(rule("a", 80)),
+				"placement": obj(resource.PropertyMap{"zone": str("z1")}),
+				"labels":    obj(resource.PropertyMap{}),
+			},
+			changes: plugin.DiffNone,
+		},
+		{
+			name: "nested update",
+			news: resource.PropertyMap{
+				"rules":     arr(rule("a", 443)),
+				"placement": obj(resource.PropertyMap{"zone": str("z1")}),
+			},
+			changes:  plugin.DiffSome,
+			changed:  []resource.PropertyKey{"rules"},
+			detailed: map[string]plugin.DiffKind{"rules[0].port": plugin.DiffUpdate},
+		},
+		{
+			name: "nested ForceNew property",
+			news: resource.PropertyMap{
+				"rules":     arr(rule("b", 80)),
+				"placement": obj(resource.PropertyMap{"zone": str("z1")}),
+			},
+			changes:  plugin.DiffSome,
+			changed:  []resource.PropertyKey{"rules"},
+			replaced: []resource.PropertyKey{"rules"},
+			detailed: map[string]plugin.DiffKind{"rules[0].name": plugin.DiffUpdateReplace},
+		},
+		{
+			name: "added block with a ForceNew property",
+			news: resource.PropertyMap{
+				"rules":     arr(rule("a", 80), rule("b", 80)),
+				"placement": obj(resource.PropertyMap{"zone": str("z1")}),
+			},
+			changes:  plugin.DiffSome,
+			changed:  []resource.PropertyKey{"rules"},
+			replaced: []resource.PropertyKey{"rules"},
+			detailed: map[string]plugin.DiffKind{"rules[1]": plugin.DiffAddReplace},
+		},
+		{
+			name: "unknown nested property",
+			news: resource.PropertyMap{
+				"rules": arr(obj(resource.PropertyMap{
+					"name": str("a"),
+					"port": resource.MakeComputed(str("")),
+				})),
+				"placement": obj(resource.PropertyMap{"zone": str("z1")}),
+			},
+			changes:  plugin.DiffSome,
+			changed:  []resource.PropertyKey{"rules"},
+			detailed: map[string]plugin.DiffKind{"rules[0].port": plugin.DiffUpdate},
+		},
+		{
+			name: "ForceNew MaxItemsOne block",
+			news: resource.PropertyMap{
+				"rules":     arr(rule("a", 80)),
+				"placement": obj(resource.PropertyMap{"zone": str("z2")}),
+			},
+			changes:  plugin.DiffSome,
+			changed:  []resource.PropertyKey{"placement"},
+			replaced: []resource.PropertyKey{"placement"},
+			detailed: map[string]plugin.DiffKind{"placement.zone": plugin.DiffUpdateReplace},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			diff, err := DeferredDiff(schema, nil, olds, tt.news, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.changes, diff.Changes)
+			assert.Equal(t, tt.changed, diff.ChangedKeys)
+			assert.Equal(t, tt.replaced, diff.ReplaceKeys)
+			if tt.detailed != nil {
+				detailed := map[string]plugin.DiffKind{}
+				for path, d := range diff.DetailedDiff {
+					detailed[path] = d.Kind
+				}
+				assert.Equal(t, tt.detailed, detailed)
+			}
+		})
+	}
+}
+
+func TestDeferConfigureWithUnknowns(t *testing.T) {
+	t.Parallel()
+	var configured []string
+	p := &schemav2.Provider{
+		Schema: map[string]*schemav2.Schema{
+			"endpoint": {Type: schemav2.TypeString, Optional: true},
+		},
+		ConfigureContextFunc: func(_ context.Context, rd *schemav2.ResourceData) (any, diag.Diagnostics) {
+			configured = append(configured, rd.Get("endpoint").(string))
+			return nil, nil
+		},
+		ResourcesMap: map[string]*schemav2.Resource{
+			"res": {
+				Schema: deferredResourceSchema(),
+				CreateContext: func(_ context.Context, rd *schemav2.ResourceData, _ any) diag.Diagnostics {
+					rd.SetId("r1")
+					return nil
+				},
+				ReadContext: func(context.Context, *schemav2.ResourceData, any) diag.Diagnostics {
+					return nil
+				},
+				UpdateContext: func(context.Context, *schemav2.ResourceData, any) diag.Diagnostics {
+					return nil
+				},
+				DeleteContext: func(context.Context, *schemav2.ResourceData, any) diag.Diagnostics {
+					return nil
+				},
+			},
+		},
+	}
+	shimProv := shimv2.NewProvider(p)
+	provider := &Provider{
+		tf:     shimProv,
+		config: shimv2.NewSchemaMap(p.Schema),
+		info:   ProviderInfo{P: shimProv, DeferConfigureWithUnknowns: true},
+		resources: map[tokens.Type]Resource{
+			"Res": {
+				TF:     shimv2.NewResource(p.ResourcesMap["res"]),
+				TFName: "res",
+				Schema: &ResourceInfo{Tok: "Res"},
+			},
+		},
+	}
+
+	testutils.ReplaySequence(t, provider, `[
+	{
+	  "method": "/pulumirpc.ResourceProvider/Configure",
+	  "request": {
+	    "args": {"endpoint": "04da6b54-80e4-46f7-96ec-b56ff0331ba9"},
+	    "variables": {}
+	  },
+	  "response": {
+	    "supportsPreview": true
+	  }
+	},
+	{
+	  "method": "/pulumirpc.ResourceProvider/Create",
+	  "request": {
+	    "urn": "urn:pulumi:dev::teststack::Res::exres",
+	    "properties": {"name": "n", "size": 1},
+	    "preview": true
+	  },
+	  "response": {
+	    "properties": {
+	      "id": "04da6b54-80e4-46f7-96ec-b56ff0331ba9",
+	      "name": "n",
+	      "size": 1,
+	      "region": "04da6b54-80e4-46f7-96ec-b56ff0331ba9",
+	      "endpoint": "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
+	    }
+	  }
+	},
+	{
+	  "method": "/pulumirpc.ResourceProvider/Diff",
+	  "request": {
+	    "id": "r1",
+	    "urn": "urn:pulumi:dev::teststack::Res::exres",
+	    "olds": {"id": "r1", "name": "n", "size": 1, "region": "r", "endpoint": "e"},
+	    "news": {"name": "m", "size": 1}
+	  },
+	  "response": {
+	    "changes": "DIFF_SOME",
+	    "diffs": ["name"],
+	    "replaces": ["name"],
+	    "detailedDiff": {"name": {"kind": "UPDATE_REPLACE", "inputDiff": true}},
+	    "hasDetailedDiff": true
+	  }
+	},
+	{
+	  "method": "/pulumirpc.ResourceProvider/Update",
+	  "request": {
+	    "id": "r1",
+	    "urn": "urn:pulumi:dev::teststack::Res::exres",
+	    "olds": {"id": "r1", "name": "n", "size": 1, "region": "r", "endpoint": "e"},
+	    "news": {"name": "n", "size": 2},
+	    "preview": true
+	  },
+	  "response": {
+	    "properties": {
+	      "id": "04da6b54-80e4-46f7-96ec-b56ff0331ba9",
+	      "name": "n",
+	      "size": 2,
+	      "region": "04da6b54-80e4-46f7-96ec-b56ff0331ba9",
+	      "endpoint": "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
+	    }
+	  }
+	}]`)
+	assert.Empty(t, configured)
+
+	props, err := plugin.MarshalProperties(resource.PropertyMap{"name": resource.NewStringProperty("n")},
+		plugin.MarshalOptions{})
+	require.NoError(t, err)
+	_, err = provider.Create(context.Background(), &pulumirpc.CreateRequest{
+		Urn:        "urn:pulumi:dev::teststack::Res::exres",
+		Properties: props,
+	})
+	assert.ErrorIs(t, err, ErrConfigurationUnknown)
+
+	testutils.Replay(t, provider, `
+	{
+	  "method": "/pulumirpc.ResourceProvider/Configure",
+	  "request": {
+	    "args": {"endpoint": "https://example.com"},
+	    "variables": {}
+	  },
+	  "response": {
+	    "supportsPreview": true
+	  }
+	}`)
+	assert.Equal(t, []string{"https://example.com"}, configured)
+}
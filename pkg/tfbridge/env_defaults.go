@@ -0,0 +1,223 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// stringElemSchema is the element schema assumed for collections that do not declare one.
+var stringElemSchema = (&schema.Schema{Type: shim.TypeString}).Shim()
+
+// ParseEnvDefault parses the value of one of the environment variables named by DefaultInfo.EnvVars into a
+// PropertyValue shaped like the property described by sch and info.
+//
+// Scalars are parsed from their usual string representation. Lists and sets may be given either as a JSON array
+// or as a comma-separated list of elements. Maps and nested blocks must be given as JSON; the keys of a block may
+// use either the Terraform or the Pulumi names of its properties. A block with MaxItemsOne may be given as a single
+// JSON object.
+//
+// The resulting PropertyValue uses Pulumi property names, and is suitable for passing to MakeTerraformInputs.
+func ParseEnvDefault(str string, sch shim.Schema, info *SchemaInfo) (resource.PropertyValue, error) {
+	switch sch.Type() {
+	case shim.TypeList, shim.TypeSet, shim.TypeMap:
+		trimmed := strings.TrimSpace(str)
+		if strings.HasPrefix(trimmed, "[") || strings.HasPrefix(trimmed, "{") {
+			var v interface{}
+			if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+				return resource.PropertyValue{}, fmt.Errorf("invalid JSON: %w", err)
+			}
+			return envDefaultValue(v, sch, info)
+		}
+		if _, isBlock := sch.Elem().(shim.Resource); isBlock || sch.Type() == shim.TypeMap {
+			return resource.PropertyValue{}, fmt.Errorf("expected a JSON object")
+		}
+		if trimmed == "" {
+			return resource.NewArrayProperty([]resource.PropertyValue{}), nil
+		}
+		parts := strings.Split(str, ",")
+		elems := make([]interface{}, len(parts))
+		for i, p := range parts {
+			elems[i] = strings.TrimSpace(p)
+		}
+		return envDefaultValue(elems, sch, info)
+	default:
+		return envDefaultValue(str, sch, info)
+	}
+}
+
+// envDefaultValue coerces v, which is either a string or a value decoded from JSON, into the shape of sch.
+func envDefaultValue(v interface{}, sch shim.Schema, info *SchemaInfo) (resource.PropertyValue, error) {
+	if v == nil {
+		return resource.NewNullProperty(), nil
+	}
+
+	var elemInfo *SchemaInfo
+	if info != nil {
+		elemInfo = info.Elem
+	}
+
+	switch sch.Type() {
+	case shim.TypeBool:
+		switch v := v.(type) {
+		case bool:
+			return resource.NewBoolProperty(v), nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return resource.PropertyValue{}, err
+			}
+			return resource.NewBoolProperty(b), nil
+		}
+	case shim.TypeInt:
+		switch v := v.(type) {
+		case float64:
+			if v != math.Trunc(v) {
+				return resource.PropertyValue{}, fmt.Errorf("expected an integer, got %v", v)
+			}
+			return resource.NewNumberProperty(v), nil
+		case string:
+			i, err := strconv.ParseInt(v, 0, 0)
+			if err != nil {
+				return resource.PropertyValue{}, err
+			}
+			return resource.NewNumberProperty(float64(i)), nil
+		}
+	case shim.TypeFloat:
+		switch v := v.(type) {
+		case float64:
+			return resource.NewNumberProperty(v), nil
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return resource.PropertyValue{}, err
+			}
+			return resource.NewNumberProperty(f), nil
+		}
+	case shim.TypeString:
+		switch v := v.(type) {
+		case string:
+			return resource.NewStringProperty(v), nil
+		case bool:
+			return resource.NewStringProperty(strconv.FormatBool(v)), nil
+		case float64:
+			return resource.NewStringProperty(strconv.FormatFloat(v, 'f', -1, 64)), nil
+		}
+	case shim.TypeList, shim.TypeSet:
+		if res, isBlock := sch.Elem().(shim.Resource); isBlock && IsMaxItemsOne(sch, info) {
+			if arr, ok := v.([]interface{}); ok {
+				switch len(arr) {
+				case 0:
+					return resource.NewNullProperty(), nil
+				case 1:
+					v = arr[0]
+				default:
+					return resource.PropertyValue{}, fmt.Errorf("expected at most one element, got %d", len(arr))
+				}
+			}
+			return envDefaultBlock(v, res, elemInfo)
+		}
+		arr, ok := v.([]interface{})
+		if !ok {
+			break
+		}
+		elems := make([]resource.PropertyValue, len(arr))
+		for i, e := range arr {
+			var err error
+			switch elem := sch.Elem().(type) {
+			case shim.Resource:
+				elems[i], err = envDefaultBlock(e, elem, elemInfo)
+			case shim.Schema:
+				elems[i], err = envDefaultValue(e, elem, elemInfo)
+			default:
+				elems[i], err = envDefaultValue(e, stringElemSchema, elemInfo)
+			}
+			if err != nil {
+				return resource.PropertyValue{}, fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		return resource.NewArrayProperty(elems), nil
+	case shim.TypeMap:
+		if res, isBlock := sch.Elem().(shim.Resource); isBlock {
+			// Single nested blocks keep their field overrides in info.Fields rather than info.Elem.Fields.
+			return envDefaultBlock(v, res, info)
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			break
+		}
+		var elemSchema shim.Schema = stringElemSchema
+		if elem, ok := sch.Elem().(shim.Schema); ok {
+			elemSchema = elem
+		}
+		obj := make(resource.PropertyMap, len(m))
+		for k, e := range m {
+			pv, err := envDefaultValue(e, elemSchema, elemInfo)
+			if err != nil {
+				return resource.PropertyValue{}, fmt.Errorf("%s: %w", k, err)
+			}
+			obj[resource.PropertyKey(k)] = pv
+		}
+		return resource.NewObjectProperty(obj), nil
+	default:
+		return resource.PropertyValue{}, fmt.Errorf("unknown type for default value: %v", sch.Type())
+	}
+	return resource.PropertyValue{}, fmt.Errorf("cannot use %v as a value of type %v", v, sch.Type())
+}
+
+// envDefaultBlock coerces a JSON object into the shape of a nested block. Keys may be either Terraform or Pulumi
+// property names; the result is always keyed by Pulumi names.
+func envDefaultBlock(v interface{}, res shim.Resource, info *SchemaInfo) (resource.PropertyValue, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return resource.PropertyValue{}, fmt.Errorf("expected a JSON object, got %v", v)
+	}
+
+	var fields map[string]*SchemaInfo
+	if info != nil {
+		fields = info.Fields
+	}
+	schemaMap := res.Schema()
+	pulumiNames := map[string]string{}
+	schemaMap.Range(func(tfName string, _ shim.Schema) bool {
+		pulumiNames[TerraformToPulumiNameV2(tfName, schemaMap, fields)] = tfName
+		return true
+	})
+
+	obj := make(resource.PropertyMap, len(m))
+	for k, e := range m {
+		tfName := k
+		if _, ok := schemaMap.GetOk(k); !ok {
+			if tfName, ok = pulumiNames[k]; !ok {
+				return resource.PropertyValue{}, fmt.Errorf("unknown property %q", k)
+			}
+		}
+		pv, err := envDefaultValue(e, schemaMap.Get(tfName), fields[tfName])
+		if err != nil {
+			return resource.PropertyValue{}, fmt.Errorf("%s: %w", k, err)
+		}
+		obj[resource.PropertyKey(TerraformToPulumiNameV2(tfName, schemaMap, fields))] = pv
+	}
+	return resource.NewObjectProperty(obj), nil
+}
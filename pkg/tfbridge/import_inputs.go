@@ -0,0 +1,183 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/propertyvalue"
+)
+
+// droppedImportInput records a property that was left out of the inputs inferred for an imported resource.
+type droppedImportInput struct {
+	key    resource.PropertyKey
+	reason string
+}
+
+// inferImportInputs computes the inputs of a resource being imported from its refreshed state, as enabled by
+// ProviderInfo.VerifyImportInputs.
+//
+// It starts from the schema-directed inputs of ExtractInputsFromOutputs, which already leave out computed-only
+// properties and zero values, and additionally drops:
+//
+//   - optional properties whose value equals the one DefaultInfo would provide, and
+//   - optional properties that make the inferred inputs diff against the imported state.
+//
+// The second step runs the inputs through the same translation as Check followed by a Diff against state, so that
+// the program generated by `pulumi import` is stable. Verification is best-effort: if a Diff fails, the inputs
+// inferred so far are returned.
+func (p *Provider) inferImportInputs(
+	ctx context.Context, urn resource.URN, res Resource, state shim.InstanceState, outs resource.PropertyMap,
+) (resource.PropertyMap, []droppedImportInput) {
+	tfs, fields := res.TF.Schema(), res.Schema.Fields
+	inputs := extractSchemaInputsObject(outs, tfs, fields)
+
+	var dropped []droppedImportInput
+	for _, k := range inputs.StableKeys() {
+		if k == defaultsKey {
+			continue
+		}
+		_, etfs, eps := getInfoFromPulumiName(k, tfs, fields, false)
+		if etfs == nil || etfs.Required() {
+			continue
+		}
+		if dv, ok := p.defaultInfoValue(etfs, eps); ok && equalIgnoringSecrets(dv, inputs[k]) {
+			delete(inputs, k)
+			dropped = append(dropped, droppedImportInput{k, "it is equal to its default value"})
+		}
+	}
+
+	diffs, err := p.importInputsDiff(ctx, urn, res, state, outs, inputs)
+	if err != nil {
+		glog.V(9).Infof("%s: skipping import input verification: %v", urn, err)
+		return inputs, dropped
+	}
+	for _, k := range diffs {
+		if _, has := inputs[k]; !has {
+			continue
+		}
+		if _, etfs, _ := getInfoFromPulumiName(k, tfs, fields, false); etfs == nil || etfs.Required() {
+			continue
+		}
+
+		// Only prune a property if doing so actually removes its diff; removing an optional, non-computed
+		// property usually produces a diff of its own.
+		candidate := inputs.Copy()
+		delete(candidate, k)
+		candidateDiffs, err := p.importInputsDiff(ctx, urn, res, state, outs, candidate)
+		if err != nil || slices.Contains(candidateDiffs, k) {
+			continue
+		}
+		inputs = candidate
+		dropped = append(dropped, droppedImportInput{k, "it caused a diff against the imported state"})
+	}
+	return inputs, dropped
+}
+
+// importInputsDiff returns the top-level properties that differ between state and a resource configured with inputs.
+func (p *Provider) importInputsDiff(
+	ctx context.Context, urn resource.URN, res Resource, state shim.InstanceState,
+	outs, inputs resource.PropertyMap,
+) ([]resource.PropertyKey, error) {
+	tfs, fields := res.TF.Schema(), res.Schema.Fields
+
+	tfInputs, assets, err := MakeTerraformInputs(ctx, &PulumiResource{URN: urn, Properties: inputs},
+		p.configValues, nil, inputs, tfs, fields)
+	if err != nil {
+		return nil, err
+	}
+	news := MakeTerraformOutputs(ctx, p.tf, tfInputs, tfs, fields, assets, false, p.supportsSecrets)
+
+	config, _, err := MakeTerraformConfig(ctx, p, news, tfs, fields)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := p.tf.Diff(ctx, res.TFName, state, config, shim.DiffOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[resource.PropertyKey]struct{}{}
+	for path := range makeDetailedDiffExtra(ctx, tfs, fields, outs, news, diff).diffs {
+		pp, err := resource.ParsePropertyPath(path)
+		if err != nil || len(pp) == 0 {
+			continue
+		}
+		if k, ok := pp[0].(string); ok {
+			keys[resource.PropertyKey(k)] = struct{}{}
+		}
+	}
+	result := make([]resource.PropertyKey, 0, len(keys))
+	for k := range keys {
+		result = append(result, k)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
+
+// defaultInfoValue returns the value that the DefaultInfo of a property would currently provide, if any. Defaults
+// computed by functions are not considered, since their results are not stable.
+func (p *Provider) defaultInfoValue(sch shim.Schema, info *SchemaInfo) (resource.PropertyValue, bool) {
+	if info == nil || info.Default == nil {
+		return resource.PropertyValue{}, false
+	}
+	d := info.Default
+	for _, n := range d.EnvVars {
+		if str := os.Getenv(n); str != "" {
+			v, err := ParseEnvDefault(str, sch, info)
+			return v, err == nil
+		}
+	}
+	if d.Config != "" {
+		v, ok := p.configValues[resource.PropertyKey(d.Config)]
+		return v, ok
+	}
+	if d.Value != nil {
+		return defaultValueToPropertyValue(d.Value), true
+	}
+	return resource.PropertyValue{}, false
+}
+
+// logDroppedImportInputs tells the user which properties were left out of the inputs of an imported resource.
+func (p *Provider) logDroppedImportInputs(ctx context.Context, urn resource.URN, dropped []droppedImportInput) error {
+	if len(dropped) == 0 {
+		return nil
+	}
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "The following properties were left out of the inputs inferred for %v:", urn.Name())
+	for _, d := range dropped {
+		fmt.Fprintf(&msg, "\n  - %s: %s", d.key, d.reason)
+	}
+	// TODO: This is needed for tests, since tests don't have a host defined.
+	if p.host == nil {
+		glog.Info(msg.String())
+		return nil
+	}
+	return p.host.Log(ctx, diag.Info, urn, msg.String())
+}
+
+func equalIgnoringSecrets(a, b resource.PropertyValue) bool {
+	return propertyvalue.RemoveSecrets(a).DeepEquals(propertyvalue.RemoveSecrets(b))
+}
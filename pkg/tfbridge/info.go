@@ -32,6 +32,7 @@ import (
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/logging"
+	"github.com/pulumi/pulumi-terraform-bridge/x/muxer"
 )
 
 const (
@@ -143,6 +144,14 @@ type ProviderInfo struct {
 	// compiled provider.
 	MuxWith []MuxProvider
 
+	// Controls how muxed providers resolve provider configuration keys that their CheckConfig results disagree
+	// on, keyed by the Pulumi name of the config key. Server indexes follow the dispatch order of the muxed
+	// providers: for MuxWith, index 0 is the bridged provider and index i is MuxWith[i-1].
+	//
+	// Applies to providers muxed with MuxWith or with pf/tfbridge.MuxShimWithPF. The policies are recorded in
+	// the "mux" metadata during tfgen.
+	MuxConfigPolicies map[string]muxer.ConfigPolicy
+
 	// Disables validation of provider-level configuration for Plugin Framework based providers.
 	// Hybrid providers that utilize a mixture of Plugin Framework and SDKv2 based resources may
 	// opt into this to workaround slowdown in PF validators, since their configuration is
@@ -156,11 +165,50 @@ type ProviderInfo struct {
 	// See https://github.com/pulumi/pulumi-terraform-bridge/issues/1501
 	XSkipDetailedDiffForChanges bool
 
+	// Enables diff-verified inference of inputs when resources are imported with `pulumi import`.
+	//
+	// Besides the computed-only and zero values that are always left out, inputs equal to the value a
+	// [DefaultInfo] would provide are dropped. The inferred inputs are then diffed against the imported state, and
+	// optional properties that cause a diff are pruned if that makes the diff go away. Dropped properties are
+	// reported to the user.
+	//
+	// Inference only runs when a resource is read by its ID alone, without prior state or inputs. This only applies
+	// to SDKv2 based resources: Plugin Framework based providers ignore this setting.
+	VerifyImportInputs bool
+
 	// Enables generation of a trimmed, runtime-only metadata file
-	// to help reduce resource plugin start time
+	// to help reduce resource plugin start time. The file uses a
+	// compressed binary encoding and is read by NewProviderMetadata.
+	//
+	// The file is named runtime-bridge-metadata.json for compatibility
+	// with existing go:embed directives, but it is not JSON.
 	//
 	// See also pulumi/pulumi-terraform-bridge#1524
 	GenerateRuntimeMetadata bool
+
+	// Configures provider-level default tags that are merged into the tags of resources, similarly to the
+	// default_tags block of some Terraform providers.
+	DefaultTags *DefaultTagsInfo
+
+	// Retries calls into the upstream provider that fail with transient errors. Resources may override it with
+	// [ResourceInfo.RetryPolicy].
+	RetryPolicy *RetryPolicy
+
+	// Limits the number of concurrent calls into the upstream provider that create, read, update or delete
+	// resources or read data sources, for example to stay within the rate limits of an API. 0 means no limit.
+	MaxConcurrentOperations int
+
+	// Defers configuring the upstream provider while the provider configuration contains unknown values, such as
+	// the endpoint of a cluster that has not been created yet.
+	//
+	// While the configuration is deferred, Check validates resources as usual, and Diff and the previews of
+	// Create and Update are computed from the schema alone, with all computed outputs unknown. Operations that
+	// need a configured provider fail with [ErrConfigurationUnknown]. The upstream provider is configured once
+	// Configure is called with known values.
+	//
+	// Plugin Framework resources declare replacements with plan modifiers that the schema does not expose, so
+	// their deferred diffs report changes as updates.
+	DeferConfigureWithUnknowns bool
 }
 
 // Send logs or status logs to the user.
@@ -357,9 +405,24 @@ type ResourceInfo struct {
 	CSharpName          string      // .NET-specific name
 
 	// Optional hook to run before upgrading the state. TODO[pulumi/pulumi-terraform-bridge#864] this is currently
-	// only supported for Plugin-Framework based providers.
+	// only supported for Plugin-Framework based providers. See StateMigrations for a versioned alternative
+	// supported by all providers.
 	PreStateUpgradeHook PreStateUpgradeHook
 
+	// StateMigrations rewrite the Pulumi state of the resource, for example to fix state written by older
+	// versions of the provider after a property rename, a MaxItemsOne change or a token move.
+	//
+	// Migrations are ordered: state written by a provider with N migrations has Pulumi schema version N, which is
+	// recorded in the __meta block of the state. When reading state with version V < N, the bridge applies
+	// migrations V+1..N in Diff, Read, Update and Delete before the state reaches Terraform and its
+	// UpgradeResourceState. Migrations may only be appended; removing or reordering them corrupts state.
+	//
+	// Read also applies the migrations of the state to the prior inputs of the resource, which have no version of
+	// their own. Check does not migrate its prior inputs, since it has no state to tell their version.
+	//
+	// Migrations run before TransformFromState.
+	StateMigrations []StateMigration
+
 	// An experimental way to augment the Check function in the Pulumi life cycle.
 	PreCheckCallback PreCheckCallback
 
@@ -389,6 +452,9 @@ type ResourceInfo struct {
 	// To delegate the resource ID to another string field in state, use the helper function
 	// [DelegateIDField].
 	ComputeID ComputeID
+
+	// Overrides [ProviderInfo.RetryPolicy] for this resource.
+	RetryPolicy *RetryPolicy
 }
 
 type ComputeID = func(ctx context.Context, state resource.PropertyMap) (resource.ID, error)
@@ -424,8 +490,21 @@ type DataSourceInfo struct {
 	Fields             map[string]*SchemaInfo
 	Docs               *DocInfo // overrides for finding and mapping TF docs.
 	DeprecationMessage string   // message to use in deprecation warning
+
+	// PreInvokeCallback edits the arguments of the data source before they are validated and passed to
+	// Terraform. It is the data source analog of [ResourceInfo.PreCheckCallback].
+	PreInvokeCallback PreInvokeCallback
+
+	// TransformOutputs edits the result of the data source before it is returned to the program. In particular,
+	// it can be used as a last resort hook to correct the translation of the upstream result from TF to Pulumi.
+	// Should be used sparingly.
+	TransformOutputs PropertyTransform
 }
 
+type PreInvokeCallback = func(
+	ctx context.Context, args resource.PropertyMap, config resource.PropertyMap,
+) (resource.PropertyMap, error)
+
 // GetTok returns a datasource type token
 func (info *DataSourceInfo) GetTok() tokens.Token { return tokens.Token(info.Tok) }
 
@@ -514,6 +593,17 @@ type SchemaInfo struct {
 
 	// whether or not to treat this property as secret
 	Secret *bool
+
+	// whether this property is write-only: its value is sent to the provider on Create and Update, but is never
+	// stored in state, neither in the resource's outputs nor in its inputs, and is never returned by Read.
+	//
+	// Check removes write-only values from the inputs it returns and keeps them in the memory of the provider
+	// process until Create or Update sends them to the provider, see [WriteOnlyInputs]. Create and Update fail if
+	// Check ran in another provider process. Changing only the value of a write-only property never triggers an
+	// update, as in Terraform. Write-only properties are also treated as secret.
+	//
+	// Attributes that the upstream schema declares write-only are write-only even if this is not set.
+	WriteOnly bool
 }
 
 // ConfigInfo represents a synthetic configuration variable that is Pulumi-only, and not passed to Terraform.
@@ -575,11 +665,19 @@ type DefaultInfo struct {
 	ComputeDefault func(ctx context.Context, opts ComputeDefaultOptions) (interface{}, error)
 
 	// Value injects a raw literal value as the default.
-	// Note that only simple types such as string, int and boolean are currently supported here.
-	// Structs, slices and maps are not yet supported.
+	//
+	// Besides simple types such as string, int and boolean, Value may be a slice, a map or a struct to provide a
+	// default for list-, set-, map- or block-typed properties. Composite values are given in their Pulumi shape:
+	// map keys and struct field tags (`pulumi:"name"`) use Pulumi property names, and blocks flattened by
+	// MaxItemsOne take a single object rather than a list. A [resource.PropertyValue] is also accepted as-is.
+	// Since Pulumi schema only allows primitive default values, composite values are documented in the
+	// description of the property in the generated schema rather than in its default.
 	Value interface{}
 	// EnvVars to use for defaults. If none of these variables have values at runtime, the value of `Value` (if any)
 	// will be used as the default.
+	//
+	// Lists and sets may be read from either a JSON array or a comma-separated list, while maps and blocks are read
+	// from JSON objects keyed by either Terraform or Pulumi property names. See [ParseEnvDefault].
 	EnvVars []string
 }
 
@@ -602,6 +700,9 @@ type ComputeDefaultOptions struct {
 	// example, that random values generated across "pulumi preview" and "pulumi up" in the same deployment are
 	// consistent. This currently is only available for resource changes.
 	Seed []byte
+
+	// The configuration of the provider, if known. Used to evaluate [AutonamingConfigKey].
+	ProviderConfig resource.PropertyMap
 }
 
 // PulumiResource is just a little bundle that carries URN, seed and properties around.
@@ -985,6 +1086,7 @@ type MarshallableSchemaInfo struct {
 	Deprecated  string                             `json:"deprecated,omitempty"`
 	ForceNew    *bool                              `json:"forceNew,omitempty"`
 	Secret      *bool                              `json:"secret,omitempty"`
+	WriteOnly   bool                               `json:"writeOnly,omitempty"`
 }
 
 // MarshalSchemaInfo converts a Pulumi SchemaInfo value into a MarshallableSchemaInfo value.
@@ -1010,6 +1112,7 @@ func MarshalSchemaInfo(s *SchemaInfo) *MarshallableSchemaInfo {
 		Deprecated:  s.DeprecationMessage,
 		ForceNew:    s.ForceNew,
 		Secret:      s.Secret,
+		WriteOnly:   s.WriteOnly,
 	}
 }
 
@@ -1036,6 +1139,7 @@ func (m *MarshallableSchemaInfo) Unmarshal() *SchemaInfo {
 		DeprecationMessage: m.Deprecated,
 		ForceNew:           m.ForceNew,
 		Secret:             m.Secret,
+		WriteOnly:          m.WriteOnly,
 	}
 }
 
@@ -63,19 +63,33 @@ func (info *MetadataInfo) assertValid() {
 }
 
 var declaredRuntimeMetadata = map[string]struct{}{
-	autoSettingsKey: {},
-	"mux":           {},
+	autoSettingsKey:    {},
+	"mux":              {},
+	"inferred-modules": {},
 }
 
 func declareRuntimeMetadata(label string) { declaredRuntimeMetadata[label] = struct{}{} }
 
+// Values of runtime metadata keys at least this many bytes long are compressed, see
+// [MetadataInfo.ExtractRuntimeMetadata].
+const runtimeMetadataCompressionThreshold = 4 << 10
+
 // trim the metadata to just the keys required for the runtime phase
-// in the future this method might also substitute compressed contents within some keys
+//
+// Large values, such as the mux tables and alias histories of big providers, are compressed so that they do not bloat
+// provider binaries. They are decompressed on first use by [metadata.Get]. tfgen writes the result with
+// [metadata.Data.MarshalBinary], which [NewProviderMetadata] reads back.
+//
+// The file keeps the name runtime-bridge-metadata.json so that existing go:embed directives keep working, but its
+// contents are binary, not JSON. Use [metadata.New] and [metadata.Data.MarshalIndent] to inspect it.
 func (info *MetadataInfo) ExtractRuntimeMetadata() *MetadataInfo {
 	data, _ := metadata.New(nil)
 
 	for k := range declaredRuntimeMetadata {
 		metadata.CloneKey(k, info.Data, data)
+		err := metadata.Compress(data, k, runtimeMetadataCompressionThreshold)
+		// Compressing a valid JSON value in memory cannot fail.
+		contract.AssertNoErrorf(err, "failed to compress runtime metadata key %q", k)
 	}
 
 	return &MetadataInfo{"runtime-bridge-metadata.json", ProviderMetadata(data)}
@@ -1,6 +1,7 @@
 package tfbridge
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hexops/autogold/v2"
@@ -76,3 +77,35 @@ func TestMetadataInfo(t *testing.T) {
     ]
 }`).Equal(t, string(runtimeMarshalled))
 }
+
+func TestExtractRuntimeMetadataCompressesLargeKeys(t *testing.T) {
+	data, err := md.New(nil)
+	require.NoError(t, err)
+
+	mux := map[string]int{}
+	for i := 0; i < 1000; i++ {
+		mux[fmt.Sprintf("pkg:index/resource%d:Resource%d", i, i)] = i % 2
+	}
+	require.NoError(t, md.Set(data, "mux", mux))
+	require.NoError(t, md.Set(data, autoSettingsKey, []string{"..."}))
+
+	info := &MetadataInfo{Path: "bridge-metadata.json", Data: ProviderMetadata(data)}
+	runtime := info.ExtractRuntimeMetadata()
+
+	full := (*md.Data)(info.Data).Marshal()
+	runtimeJSON := (*md.Data)(runtime.Data).Marshal()
+	runtimeMarshalled := (*md.Data)(runtime.Data).MarshalBinary()
+	assert.Less(t, len(runtimeJSON), len(full)/2)
+	assert.Less(t, len(runtimeMarshalled), len(runtimeJSON))
+
+	parsed := NewProviderMetadata(runtimeMarshalled)
+	readMux, ok, err := md.Get[map[string]int](parsed.Data, "mux")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, mux, readMux)
+
+	settings, ok, err := md.Get[[]string](parsed.Data, autoSettingsKey)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"..."}, settings)
+}
@@ -363,6 +363,34 @@ func ComputeAutoNameDefault(
 		}
 	}
 
+	// Stack configuration may override the auto-naming of the provider, see AutonamingConfigKey.
+	config, err := parseAutonamingConfig(defaultOptions.ProviderConfig)
+	if err != nil {
+		return nil, err
+	}
+	if config != nil {
+		settings := config.settingsFor(string(defaultOptions.URN.Type()))
+		var name string
+		switch {
+		case settings.Mode == autonamingModeDisabled:
+			return nil, nil
+		case settings.Mode == autonamingModeVerbatim:
+			name = defaultOptions.URN.Name()
+		case settings.Pattern != "":
+			name, err = settings.evalPattern(defaultOptions.URN, defaultOptions.Seed)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if name != "" {
+			if options.Maxlen > 0 && len(name) > options.Maxlen {
+				return nil, fmt.Errorf("name %q of '%v' computed from %s config is longer than %d characters",
+					name, defaultOptions.URN.Type(), AutonamingConfigKey, options.Maxlen)
+			}
+			return name, nil
+		}
+	}
+
 	// Take the URN name part, transform it if required, and then append some unique characters if requested.
 	vs := defaultOptions.URN.Name()
 	if options.Transform != nil {
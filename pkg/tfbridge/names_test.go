@@ -15,12 +15,14 @@
 package tfbridge
 
 import (
+	"context"
 	"sort"
 	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -325,3 +327,85 @@ func TestBijectiveNameConversion(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeAutoNameDefaultWithConfig(t *testing.T) {
+	t.Parallel()
+	options := AutoNameOptions{Separator: "-", Maxlen: 20, Randlen: 4}
+	compute := func(typ, autonaming string) (interface{}, error) {
+		return ComputeAutoNameDefault(context.Background(), options, ComputeDefaultOptions{
+			URN:  resource.NewURN("dev", "proj", "", tokens.Type(typ), "n1"),
+			Seed: []byte("seed"),
+			ProviderConfig: resource.PropertyMap{
+				AutonamingConfigKey: resource.NewStringProperty(autonaming),
+			},
+		})
+	}
+
+	name, err := compute("pkg:index:Res", `{"pattern": "${project}-${stack}-${name}-${hex(4)}"}`)
+	require.NoError(t, err)
+	assert.Regexp(t, "^proj-dev-n1-[0-9a-f]{4}$", name)
+	again, err := compute("pkg:index:Res", `{"pattern": "${project}-${stack}-${name}-${hex(4)}"}`)
+	require.NoError(t, err)
+	assert.Equal(t, name, again, "names are stable for a given seed")
+
+	name, err = compute("pkg:index:Res", `{"mode": "verbatim"}`)
+	require.NoError(t, err)
+	assert.Equal(t, "n1", name)
+
+	name, err = compute("pkg:index:Res", `{"mode": "disabled"}`)
+	require.NoError(t, err)
+	assert.Nil(t, name)
+
+	name, err = compute("pkg:index:Res", `{"mode": "default"}`)
+	require.NoError(t, err)
+	assert.Regexp(t, "^n1-[0-9a-f]{4}$", name)
+
+	perType := `{"mode": "verbatim", "resources": {"pkg:index:Other": {"pattern": "${name}-x"}}}`
+	name, err = compute("pkg:index:Other", perType)
+	require.NoError(t, err)
+	assert.Equal(t, "n1-x", name)
+	name, err = compute("pkg:index:Res", perType)
+	require.NoError(t, err)
+	assert.Equal(t, "n1", name)
+
+	// The prior value is preserved.
+	name, err = ComputeAutoNameDefault(context.Background(), options, ComputeDefaultOptions{
+		URN:        "urn:pulumi:dev::proj::pkg:index:Res::n1",
+		PriorState: resource.PropertyMap{},
+		PriorValue: resource.NewStringProperty("old"),
+		ProviderConfig: resource.PropertyMap{
+			AutonamingConfigKey: resource.NewStringProperty(`{"mode": "verbatim"}`),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "old", name)
+
+	_, err = compute("pkg:index:Res", `{"pattern": "${project}-${stack}-${name}-is-too-long"}`)
+	assert.ErrorContains(t, err, "is longer than 20 characters")
+	_, err = compute("pkg:index:Res", `{"pattern": "${name}-${uuid}"}`)
+	assert.ErrorContains(t, err, "unknown expression ${uuid}")
+	_, err = compute("pkg:index:Res", `{"mode": "verbatim", "pattern": "${name}"}`)
+	assert.ErrorContains(t, err, `a pattern cannot be used with mode "verbatim"`)
+	_, err = compute("pkg:index:Res", `{"resources": {"pkg:index:Res": {"mode": "random"}}}`)
+	assert.ErrorContains(t, err, `autonaming.resources["pkg:index:Res"]: unknown mode "random"`)
+}
+
+func TestCheckAutonamingConfig(t *testing.T) {
+	t.Parallel()
+	object := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"pattern": "${name}-${hex(6)}",
+		"resources": map[string]interface{}{
+			"pkg:index:Res": map[string]interface{}{"mode": "disabled"},
+		},
+	})
+	assert.NoError(t, CheckAutonamingConfig(resource.PropertyMap{
+		AutonamingConfigKey: resource.MakeSecret(resource.NewObjectProperty(object)),
+	}))
+	assert.NoError(t, CheckAutonamingConfig(resource.PropertyMap{
+		AutonamingConfigKey: resource.MakeComputed(resource.NewStringProperty("")),
+	}))
+	assert.NoError(t, CheckAutonamingConfig(nil))
+	assert.ErrorContains(t, CheckAutonamingConfig(resource.PropertyMap{
+		AutonamingConfigKey: resource.NewStringProperty("${name}"),
+	}), "autonaming must be an object")
+}
@@ -0,0 +1,270 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/walk"
+)
+
+// The property a [PropertyNameRule] is applied to.
+type PropertyNameContext struct {
+	// The TF token of the resource or data source the property belongs to, or "" for the
+	// provider configuration.
+	TFToken string
+	// The location of the property in the schema of its resource, data source or provider.
+	Path walk.SchemaPath
+	// The TF schema of the property.
+	Schema shim.Schema
+	// The overrides of the property, or nil if there are none.
+	Info *SchemaInfo
+}
+
+// TFName returns the TF name of the property.
+func (ctx PropertyNameContext) TFName() string {
+	return ctx.Path[len(ctx.Path)-1].(walk.GetAttrStep).Name
+}
+
+// A rule that renames Pulumi properties, see [ProviderInfo.ComputePropertyNames].
+//
+// name is the Pulumi name of the property computed so far. A rule returns the new name, or
+// name unchanged if it does not apply to the property.
+type PropertyNameRule func(ctx PropertyNameContext, name string) string
+
+// Assigns Pulumi property names according to rules, unless already specified by the user.
+//
+// Context: Pulumi property names are derived from TF attribute names by
+// [TerraformToPulumiNameV2]. Providers that want different names override them field by
+// field with [SchemaInfo.Name], which becomes a chore for providers with 1000s of fields.
+//
+// ComputePropertyNames visits every property of the provider configuration and of every
+// resource and data source in [ProviderInfo.Resources] and [ProviderInfo.DataSources],
+// nested properties included. The rules are applied in order to the default name of each
+// property, and the result is recorded in [SchemaInfo.Name] if it differs from the default.
+//
+// ComputePropertyNames always respects and does not modify pre-existing names: a property
+// whose [SchemaInfo.Name] is set is not renamed. Resources and data sources without an
+// entry are skipped, so ComputePropertyNames should be called after
+// [ProviderInfo.ComputeTokens].
+//
+// ComputePropertyNames fails if the rules give the same name to two properties of the same
+// object, or an empty name to any property.
+func (info *ProviderInfo) ComputePropertyNames(rules ...PropertyNameRule) error {
+	var errs multierror.Error
+	if err := computePropertyNames("", info.P.Schema(), &info.Config, rules); err != nil {
+		errs.Errors = append(errs.Errors, fmt.Errorf("config:\n%w", err))
+	}
+
+	ignored := ignoredTokens(info)
+	visit := func(kind string, resources shim.ResourceMap, fields func(string) *map[string]*SchemaInfo) {
+		keys := make([]string, 0, resources.Len())
+		resources.Range(func(key string, _ shim.Resource) bool {
+			keys = append(keys, key)
+			return true
+		})
+		sort.Strings(keys)
+		for _, tfToken := range keys {
+			f := fields(tfToken)
+			if ignored[tfToken] || f == nil {
+				continue
+			}
+			err := computePropertyNames(tfToken, resources.Get(tfToken).Schema(), f, rules)
+			if err != nil {
+				errs.Errors = append(errs.Errors, fmt.Errorf("%s %s:\n%w", kind, tfToken, err))
+			}
+		}
+	}
+	visit("resource", info.P.ResourcesMap(), func(tfToken string) *map[string]*SchemaInfo {
+		if r := info.Resources[tfToken]; r != nil {
+			return &r.Fields
+		}
+		return nil
+	})
+	visit("datasource", info.P.DataSourcesMap(), func(tfToken string) *map[string]*SchemaInfo {
+		if d := info.DataSources[tfToken]; d != nil {
+			return &d.Fields
+		}
+		return nil
+	})
+	return errs.ErrorOrNil()
+}
+
+// Assign property names according to rules.
+//
+// Panics if ComputePropertyNames would return an error.
+func (info *ProviderInfo) MustComputePropertyNames(rules ...PropertyNameRule) {
+	err := info.ComputePropertyNames(rules...)
+	contract.AssertNoErrorf(err, "Failed to compute property names")
+}
+
+func computePropertyNames(
+	tfToken string, schemaMap shim.SchemaMap, fields *map[string]*SchemaInfo, rules []PropertyNameRule,
+) error {
+	if schemaMap == nil {
+		return nil
+	}
+	type property struct {
+		path   walk.SchemaPath
+		schema shim.Schema
+	}
+	var properties []property
+	walk.VisitSchemaMap(schemaMap, func(path walk.SchemaPath, schema shim.Schema) {
+		if _, ok := path[len(path)-1].(walk.GetAttrStep); ok {
+			properties = append(properties, property{path, schema})
+		}
+	})
+
+	// Names must be computed before any is assigned, since the default name of a property
+	// depends on the names of its siblings.
+	names := make([]string, len(properties))
+	defaults := make([]string, len(properties))
+	explicit := make([]bool, len(properties))
+	for i, p := range properties {
+		info := LookupSchemaInfoMapPath(p.path, *fields)
+		name, err := TerraformToPulumiNameAtPath(p.path, schemaMap, *fields)
+		if err != nil {
+			return err
+		}
+		names[i], defaults[i], explicit[i] = name, name, info != nil && info.Name != ""
+		if explicit[i] {
+			continue
+		}
+		ctx := PropertyNameContext{TFToken: tfToken, Path: p.path, Schema: p.schema, Info: info}
+		for _, rule := range rules {
+			names[i] = rule(ctx, names[i])
+		}
+	}
+
+	var errs multierror.Error
+	// Siblings are keyed by the encoded path of their parent object and their Pulumi name.
+	seen := map[[2]string]walk.SchemaPath{}
+	for i, p := range properties {
+		if names[i] == "" {
+			errs.Errors = append(errs.Errors, fmt.Errorf("%s: empty property name", p.path.MustEncodeSchemaPath()))
+			continue
+		}
+		key := [2]string{p.path[:len(p.path)-1].MustEncodeSchemaPath(), names[i]}
+		if other, ok := seen[key]; ok {
+			errs.Errors = append(errs.Errors, fmt.Errorf("%s and %s are both named %q",
+				other.MustEncodeSchemaPath(), p.path.MustEncodeSchemaPath(), names[i]))
+			continue
+		}
+		seen[key] = p.path
+	}
+	if err := errs.ErrorOrNil(); err != nil {
+		return err
+	}
+
+	root := &SchemaInfo{Fields: *fields}
+	for i, p := range properties {
+		if explicit[i] || names[i] == defaults[i] {
+			continue
+		}
+		getOrCreateSchemaInfoPath(p.path, root).Name = names[i]
+	}
+	*fields = root.Fields
+	return nil
+}
+
+// Replace the matches of re in property names with replacement, as by
+// [regexp.Regexp.ReplaceAllString].
+func RenamePropertiesMatching(re *regexp.Regexp, replacement string) PropertyNameRule {
+	return func(_ PropertyNameContext, name string) string {
+		return re.ReplaceAllString(name, replacement)
+	}
+}
+
+// Strip prefix from property names that start with it, as a whole camel case word.
+//
+// For example, StripPropertyPrefix("resource") renames resourceGroupName to groupName, but
+// leaves resources and resource unchanged.
+func StripPropertyPrefix(prefix string) PropertyNameRule {
+	return func(_ PropertyNameContext, name string) string {
+		rest := strings.TrimPrefix(name, prefix)
+		if rest == name || rest == "" || !unicode.IsUpper([]rune(rest)[0]) {
+			return name
+		}
+		return strings.ToLower(rest[:1]) + rest[1:]
+	}
+}
+
+// Upper case acronyms in property names.
+//
+// Each acronym is given in upper case, and replaces the camel case words that spell it, or
+// its plural, except at the start of the name. For example, PropertyAcronyms("ID", "ARN")
+// renames vpcId to vpcID, roleArns to roleARNs and arnSuffix to arnSuffix.
+func PropertyAcronyms(acronyms ...string) PropertyNameRule {
+	words := map[string]string{}
+	for _, a := range acronyms {
+		title := a[:1] + strings.ToLower(a[1:])
+		words[title] = a
+		words[title+"s"] = a + "s"
+	}
+	return func(_ PropertyNameContext, name string) string {
+		var b strings.Builder
+		start := 0
+		flush := func(end int) {
+			word := name[start:end]
+			if a, ok := words[word]; ok && start > 0 {
+				word = a
+			}
+			b.WriteString(word)
+			start = end
+		}
+		for i, r := range name {
+			if i > 0 && unicode.IsUpper(r) {
+				flush(i)
+			}
+		}
+		flush(len(name))
+		return b.String()
+	}
+}
+
+// Override the plural names given to list and set properties.
+//
+// exceptions maps the TF name of a property to its Pulumi name. The exception only applies
+// where [TerraformToPulumiNameV2] would pluralize the name, that is for lists and sets that
+// are not flattened by MaxItemsOne.
+func PluralizationExceptions(exceptions map[string]string) PropertyNameRule {
+	return func(ctx PropertyNameContext, name string) string {
+		exception, ok := exceptions[ctx.TFName()]
+		if !ok {
+			return name
+		}
+		switch ctx.Schema.Type() {
+		case shim.TypeList, shim.TypeSet:
+		default:
+			return name
+		}
+		if ctx.Info != nil && ctx.Info.MaxItemsOne != nil {
+			if *ctx.Info.MaxItemsOne {
+				return name
+			}
+		} else if ctx.Schema.MaxItems() == 1 {
+			return name
+		}
+		return exception
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func TestPropertyNameRules(t *testing.T) {
+	t.Parallel()
+	str := (&schema.Schema{Type: shim.TypeString}).Shim()
+	list := (&schema.Schema{Type: shim.TypeList, Elem: str}).Shim()
+	single := (&schema.Schema{Type: shim.TypeList, Elem: str, MaxItems: 1}).Shim()
+	ctx := func(tfName string, s shim.Schema) tfbridge.PropertyNameContext {
+		return tfbridge.PropertyNameContext{Path: tfbridge.SchemaPath{}.GetAttr(tfName), Schema: s}
+	}
+
+	tests := []struct {
+		name     string
+		rule     tfbridge.PropertyNameRule
+		ctx      tfbridge.PropertyNameContext
+		input    string
+		expected string
+	}{
+		{
+			name:     "regex rename",
+			rule:     tfbridge.RenamePropertiesMatching(regexp.MustCompile("Cidr"), "CIDR"),
+			ctx:      ctx("vpc_cidr_block", str),
+			input:    "vpcCidrBlock",
+			expected: "vpcCIDRBlock",
+		},
+		{
+			name:     "strip prefix",
+			rule:     tfbridge.StripPropertyPrefix("resource"),
+			ctx:      ctx("resource_group_name", str),
+			input:    "resourceGroupName",
+			expected: "groupName",
+		},
+		{
+			name:     "strip prefix of a longer word",
+			rule:     tfbridge.StripPropertyPrefix("resource"),
+			ctx:      ctx("resources", list),
+			input:    "resources",
+			expected: "resources",
+		},
+		{
+			name:     "strip whole name",
+			rule:     tfbridge.StripPropertyPrefix("resource"),
+			ctx:      ctx("resource", str),
+			input:    "resource",
+			expected: "resource",
+		},
+		{
+			name:     "acronyms",
+			rule:     tfbridge.PropertyAcronyms("ID", "ARN"),
+			ctx:      ctx("role_arn_id", str),
+			input:    "roleArnId",
+			expected: "roleARNID",
+		},
+		{
+			name:     "plural acronym",
+			rule:     tfbridge.PropertyAcronyms("ID"),
+			ctx:      ctx("subnet_ids", list),
+			input:    "subnetIds",
+			expected: "subnetIDs",
+		},
+		{
+			name:     "acronym prefix of a word",
+			rule:     tfbridge.PropertyAcronyms("ID"),
+			ctx:      ctx("user_identity", str),
+			input:    "userIdentity",
+			expected: "userIdentity",
+		},
+		{
+			name:     "leading acronym",
+			rule:     tfbridge.PropertyAcronyms("ARN"),
+			ctx:      ctx("arn", str),
+			input:    "arn",
+			expected: "arn",
+		},
+		{
+			name:     "pluralization exception",
+			rule:     tfbridge.PluralizationExceptions(map[string]string{"data": "data"}),
+			ctx:      ctx("data", list),
+			input:    "datas",
+			expected: "data",
+		},
+		{
+			name:     "pluralization exception for MaxItemsOne",
+			rule:     tfbridge.PluralizationExceptions(map[string]string{"data": "dataItems"}),
+			ctx:      ctx("data", single),
+			input:    "data",
+			expected: "data",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, tt.rule(tt.ctx, tt.input))
+		})
+	}
+}
+
+func TestComputePropertyNames(t *testing.T) {
+	t.Parallel()
+	str := (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim()
+	info := &tfbridge.ProviderInfo{
+		P: (&schema.Provider{
+			Schema: schema.SchemaMap{"account_id": str},
+			ResourcesMap: schema.ResourceMap{
+				"pkg_instance": (&schema.Resource{Schema: schema.SchemaMap{
+					"vpc_id":   str,
+					"role_arn": str,
+					"name":     str,
+					"network": (&schema.Schema{
+						Type: shim.TypeList,
+						Elem: (&schema.Resource{Schema: schema.SchemaMap{"subnet_id": str}}).Shim(),
+					}).Shim(),
+				}}).Shim(),
+				"pkg_unmapped": (&schema.Resource{Schema: schema.SchemaMap{"vpc_id": str}}).Shim(),
+			},
+			DataSourcesMap: schema.ResourceMap{
+				"pkg_instance": (&schema.Resource{Schema: schema.SchemaMap{"vpc_id": str}}).Shim(),
+			},
+		}).Shim(),
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"pkg_instance": {
+				Tok:    "pkg:index:Instance",
+				Fields: map[string]*tfbridge.SchemaInfo{"role_arn": {Name: "role"}},
+			},
+		},
+		DataSources: map[string]*tfbridge.DataSourceInfo{
+			"pkg_instance": {Tok: "pkg:index:getInstance"},
+		},
+	}
+
+	err := info.ComputePropertyNames(tfbridge.PropertyAcronyms("ID", "ARN"))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]*tfbridge.SchemaInfo{"account_id": {Name: "accountID"}}, info.Config)
+	assert.Equal(t, map[string]*tfbridge.SchemaInfo{
+		"vpc_id":   {Name: "vpcID"},
+		"role_arn": {Name: "role"},
+		"network": {Elem: &tfbridge.SchemaInfo{Fields: map[string]*tfbridge.SchemaInfo{
+			"subnet_id": {Name: "subnetID"},
+		}}},
+	}, info.Resources["pkg_instance"].Fields)
+	assert.Equal(t, map[string]*tfbridge.SchemaInfo{"vpc_id": {Name: "vpcID"}},
+		info.DataSources["pkg_instance"].Fields)
+	assert.Nil(t, info.Resources["pkg_unmapped"])
+
+	err = info.ComputePropertyNames(tfbridge.RenamePropertiesMatching(regexp.MustCompile(".*"), "same"))
+	assert.ErrorContains(t, err, `are both named "same"`)
+}
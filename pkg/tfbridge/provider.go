@@ -20,6 +20,7 @@ import (
 	"log"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -66,9 +67,15 @@ type Provider struct {
 	configValues    resource.PropertyMap               // this package's config values.
 	resources       map[tokens.Type]Resource           // a map of Pulumi type tokens to resource info.
 	dataSources     map[tokens.ModuleMember]DataSource // a map of Pulumi module tokens to data sources.
+	resourceMapsMu  sync.Mutex                         // guards resources, dataSources and the fields below.
+	infoTokens      *infoTokens                        // the tokens recorded in info, indexed on first use.
+	resourceMapsAll bool                               // true once all of tf is mapped in resources and dataSources.
+	writeOnlyInputs WriteOnlyInputs                    // write-only values kept from Check to Create and Update.
 	supportsSecrets bool                               // true if the engine supports secret property values
 	pulumiSchema    []byte                             // the JSON-encoded Pulumi schema.
 	memStats        memStatCollector
+	operations      *OperationRunner // retries and rate-limits calls into tf.
+	configDeferred  bool             // true if tf is not configured yet, see DeferConfigureWithUnknowns.
 }
 
 // MuxProvider defines an interface which must be implemented by providers
@@ -208,9 +215,9 @@ func newProvider(ctx context.Context, host *provider.HostClient,
 		info:         info,
 		config:       tf.Schema(),
 		pulumiSchema: pulumiSchema,
+		operations:   NewOperationRunner(&info),
 	}
 	p.loggingContext(ctx, "")
-	p.initResourceMaps()
 	return p
 }
 
@@ -296,13 +303,99 @@ func (p *Provider) label() string {
 	return fmt.Sprintf("tf.Provider[%s]", p.module)
 }
 
+// infoTokens indexes the Terraform names of the resources and data sources of ProviderInfo by their tokens.
+type infoTokens struct {
+	resources   map[tokens.Type]string
+	dataSources map[tokens.ModuleMember]string
+}
+
+// tokenIndex indexes the tokens of p.info on first use. p.resourceMapsMu must be held.
+func (p *Provider) tokenIndex() *infoTokens {
+	if p.infoTokens != nil {
+		return p.infoTokens
+	}
+	p.infoTokens = &infoTokens{
+		resources:   make(map[tokens.Type]string, len(p.info.Resources)),
+		dataSources: make(map[tokens.ModuleMember]string, len(p.info.DataSources)),
+	}
+	for name, r := range p.info.Resources {
+		if r != nil && r.Tok != "" {
+			p.infoTokens.resources[r.Tok] = name
+		}
+	}
+	for name, ds := range p.info.DataSources {
+		if ds != nil && ds.Tok != "" {
+			p.infoTokens.dataSources[ds.Tok] = name
+		}
+	}
+	return p.infoTokens
+}
+
+// resource looks up the resource with the Pulumi type token t.
+//
+// Resources are mapped on first use so that starting the provider does not visit every resource of large
+// providers: t is resolved with the tokens recorded in ProviderInfo, which only visits the requested resource, and
+// all the resources of tf are only visited for tokens that ProviderInfo does not record.
+func (p *Provider) resource(t tokens.Type) (Resource, bool) {
+	p.resourceMapsMu.Lock()
+	defer p.resourceMapsMu.Unlock()
+	if res, ok := p.resources[t]; ok {
+		return res, true
+	}
+	if name, ok := p.tokenIndex().resources[t]; ok {
+		if tf, ok := p.tf.ResourcesMap().GetOk(name); ok {
+			res := Resource{TF: tf, TFName: name, Schema: p.info.Resources[name]}
+			if p.resources == nil {
+				p.resources = make(map[tokens.Type]Resource)
+			}
+			p.resources[t] = res
+			return res, true
+		}
+	}
+	p.mapAllResources()
+	res, has := p.resources[t]
+	return res, has
+}
+
+// dataSource looks up the data source with the Pulumi module token tok, see resource.
+func (p *Provider) dataSource(tok tokens.ModuleMember) (DataSource, bool) {
+	p.resourceMapsMu.Lock()
+	defer p.resourceMapsMu.Unlock()
+	if ds, ok := p.dataSources[tok]; ok {
+		return ds, true
+	}
+	if name, ok := p.tokenIndex().dataSources[tok]; ok {
+		if tf, ok := p.tf.DataSourcesMap().GetOk(name); ok {
+			ds := DataSource{TF: tf, TFName: name, Schema: p.info.DataSources[name]}
+			if p.dataSources == nil {
+				p.dataSources = make(map[tokens.ModuleMember]DataSource)
+			}
+			p.dataSources[tok] = ds
+			return ds, true
+		}
+	}
+	p.mapAllResources()
+	ds, has := p.dataSources[tok]
+	return ds, has
+}
+
+// mapAllResources maps all the resources and data sources of tf, once. p.resourceMapsMu must be held.
+func (p *Provider) mapAllResources() {
+	if !p.resourceMapsAll {
+		p.initResourceMaps()
+		p.resourceMapsAll = true
+	}
+}
+
 // initResourceMaps creates maps from Pulumi types and tokens to Terraform resource type.
 func (p *Provider) initResourceMaps() {
 
 	ignoredTokens := ignoredTokens(&p.info)
 
 	// Fetch a list of all resource types handled by this provider and make a map.
-	p.resources = make(map[tokens.Type]Resource)
+	if p.resources == nil {
+		p.resources = make(map[tokens.Type]Resource)
+	}
 	p.tf.ResourcesMap().Range(func(name string, res shim.Resource) bool {
 		schema, ok := p.info.Resources[name]
 
@@ -344,7 +437,9 @@ func (p *Provider) initResourceMaps() {
 	})
 
 	// Fetch a list of all data source types handled by this provider and make a similar map.
-	p.dataSources = make(map[tokens.ModuleMember]DataSource)
+	if p.dataSources == nil {
+		p.dataSources = make(map[tokens.ModuleMember]DataSource)
+	}
 	p.tf.DataSourcesMap().Range(func(name string, ds shim.Resource) bool {
 		var tok tokens.ModuleMember
 
@@ -430,7 +525,7 @@ func (p *Provider) CheckConfig(ctx context.Context, req *pulumirpc.CheckRequest)
 	checkConfigSpan, ctx := opentracing.StartSpanFromContext(ctx, "sdkv2.CheckConfig",
 		opentracing.Tag{Key: "provider", Value: p.info.Name},
 		opentracing.Tag{Key: "version", Value: p.version},
-		opentracing.Tag{Key: "inputs", Value: resource.NewObjectProperty(news).String()},
+		TracePayloadTag(ctx, "inputs", p.config, p.info.Config, news),
 		opentracing.Tag{Key: "urn", Value: string(urn)},
 	)
 	defer checkConfigSpan.Finish()
@@ -462,6 +557,12 @@ func (p *Provider) CheckConfig(ctx context.Context, req *pulumirpc.CheckRequest)
 		}
 	}
 
+	if err := CheckAutonamingConfig(news); err != nil {
+		return &pulumirpc.CheckResponse{
+			Failures: []*pulumirpc.CheckFailure{{Property: AutonamingConfigKey, Reason: err.Error()}},
+		}, nil
+	}
+
 	checkFailures := validateProviderConfig(ctx, urn, p, config)
 	if len(checkFailures) > 0 {
 		return &pulumirpc.CheckResponse{
@@ -513,7 +614,7 @@ func (p *Provider) preConfigureCallbackWithLogger(
 
 func buildTerraformConfig(ctx context.Context, p *Provider, vars resource.PropertyMap) (shim.ResourceConfig, error) {
 	tfVars := make(resource.PropertyMap)
-	ignoredKeys := map[string]bool{"version": true, "pluginDownloadURL": true}
+	ignoredKeys := map[string]bool{"version": true, "pluginDownloadURL": true, AutonamingConfigKey: true}
 	for k, v := range vars {
 		// we need to skip the version as adding that will cause the provider validation to fail
 		if ignoredKeys[string(k)] {
@@ -690,7 +791,7 @@ func (p *Provider) Configure(ctx context.Context,
 	configureSpan, ctx := opentracing.StartSpanFromContext(ctx, "sdkv2.Configure",
 		opentracing.Tag{Key: "provider", Value: p.info.Name},
 		opentracing.Tag{Key: "version", Value: p.version},
-		opentracing.Tag{Key: "inputs", Value: resource.NewObjectProperty(configMap).String()},
+		TracePayloadTag(ctx, "inputs", p.config, p.info.Config, configMap),
 	)
 	defer configureSpan.Finish()
 	p.memStats.collectMemStats(ctx, configureSpan)
@@ -699,6 +800,14 @@ func (p *Provider) Configure(ctx context.Context,
 	// them later on for purposes of (e.g.) config-based defaults.
 	p.configValues = configMap
 
+	p.configDeferred = p.info.DeferConfigureWithUnknowns && configMap.ContainsUnknowns()
+	if p.configDeferred {
+		glog.V(9).Infof("%s.Configure deferred: the configuration contains unknown values", p.label())
+		return &pulumirpc.ConfigureResponse{
+			SupportsPreview: true,
+		}, nil
+	}
+
 	config, err := buildTerraformConfig(ctx, p, configMap)
 	if err != nil {
 		return nil, errors.Wrap(err, "could not marshal config state")
@@ -727,7 +836,7 @@ func (p *Provider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (*pul
 	ctx = p.loggingContext(ctx, resource.URN(req.GetUrn()))
 	urn := resource.URN(req.GetUrn())
 	t := urn.Type()
-	res, has := p.resources[t]
+	res, has := p.resource(t)
 	if !has {
 		return nil, errors.Errorf("unrecognized resource type (Check): %s", t)
 	}
@@ -751,6 +860,8 @@ func (p *Provider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (*pul
 		if err != nil {
 			return nil, err
 		}
+		// The olds of Check are prior inputs, not state: they have no __meta block to tell which
+		// StateMigrations they need, so they are not migrated.
 		olds, err = transformFromState(ctx, res.Schema, olds)
 		if err != nil {
 			return nil, err
@@ -816,6 +927,15 @@ func (p *Provider) Check(ctx context.Context, req *pulumirpc.CheckRequest) (*pul
 	pinputsWithSecrets := MarkSchemaSecrets(ctx, res.TF.Schema(), res.Schema.Fields,
 		resource.NewObjectProperty(pinputs)).ObjectValue()
 
+	// The engine records the checked inputs in the state, so they must not carry write-only values. Failed checks
+	// are never followed by Create or Update, so there is no need to keep the values.
+	if len(failures) == 0 {
+		pinputsWithSecrets = p.writeOnlyInputs.Stash(urn, res.TF.Schema(), res.Schema.Fields, pinputsWithSecrets)
+	} else {
+		p.writeOnlyInputs.Forget(urn)
+		pinputsWithSecrets = RemoveWriteOnlyValues(res.TF.Schema(), res.Schema.Fields, pinputsWithSecrets)
+	}
+
 	minputs, err := plugin.MarshalProperties(pinputsWithSecrets, plugin.MarshalOptions{
 		Label: fmt.Sprintf("%s.inputs", label), KeepUnknowns: true, KeepSecrets: true,
 	})
@@ -853,10 +973,19 @@ func markWronglyTypedMaxItemsOneStateDiff(
 
 // Diff checks what impacts a hypothetical update will have on the resource's properties.
 func (p *Provider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	resp, err := p.diff(ctx, req)
+	if err != nil || resp.GetChanges() == pulumirpc.DiffResponse_DIFF_NONE {
+		// Neither Create nor Update follow, so the write-only values kept by Check are no longer needed.
+		p.writeOnlyInputs.Forget(resource.URN(req.GetUrn()))
+	}
+	return resp, err
+}
+
+func (p *Provider) diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
 	ctx = p.loggingContext(ctx, resource.URN(req.GetUrn()))
 	urn := resource.URN(req.GetUrn())
 	t := urn.Type()
-	res, has := p.resources[t]
+	res, has := p.resource(t)
 	if !has {
 		return nil, errors.Errorf("unrecognized resource type (Diff): %s", urn)
 	}
@@ -876,16 +1005,12 @@ func (p *Provider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulum
 	if err != nil {
 		return nil, err
 	}
-	olds, err = transformFromState(ctx, res.Schema, olds)
+	priorOutputs := olds
+	olds, err = p.fromState(ctx, res, olds)
 	if err != nil {
 		return nil, err
 	}
 
-	state, err := MakeTerraformState(ctx, res, req.GetId(), olds)
-	if err != nil {
-		return nil, errors.Wrapf(err, "unmarshaling %s's instance state", urn)
-	}
-
 	news, err := plugin.UnmarshalProperties(req.GetNews(),
 		plugin.MarshalOptions{Label: fmt.Sprintf("%s.news", label), KeepUnknowns: true})
 	if err != nil {
@@ -894,6 +1019,28 @@ func (p *Provider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulum
 
 	schema, fields := res.TF.Schema(), res.Schema.Fields
 
+	// Write-only values are absent from the checked inputs and the prior state. Restore them to the inputs and
+	// assume they are unchanged.
+	news = p.writeOnlyInputs.Peek(urn, schema, fields, news)
+	olds = CopyWriteOnlyValues(schema, fields, news, olds)
+
+	if p.configDeferred {
+		diff, err := DeferredDiff(schema, fields, olds, news, req.GetIgnoreChanges())
+		if err != nil {
+			return nil, err
+		}
+		return makeDiffResponse(diff), nil
+	}
+
+	state, err := MakeTerraformState(ctx, res, req.GetId(), olds)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshaling %s's instance state", urn)
+	}
+
+	tags := p.defaultTags(res)
+	checkedInputs := news
+	news = tags.Merge(news, p.configValues)
+
 	config, _, err := MakeTerraformConfig(ctx, p, news, schema, fields)
 	if err != nil {
 		return nil, errors.Wrapf(err, "preparing %s's new property state", urn)
@@ -926,6 +1073,23 @@ func (p *Provider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulum
 		}
 	}
 
+	// The diff is computed on the tags merged with the default tags. Report the changes that come only from the
+	// default tags on tagsAll, since the tags the user sees have not changed.
+	renamed := map[string]string{}
+	for k := range detailedDiff {
+		path, err := resource.ParsePropertyPath(k)
+		if err != nil {
+			continue
+		}
+		if reported := tags.DiffKey(path, priorOutputs, checkedInputs); reported[0] != path[0] {
+			renamed[k] = reported.String()
+		}
+	}
+	for k, reported := range renamed {
+		detailedDiff[reported] = detailedDiff[k]
+		delete(detailedDiff, k)
+	}
+
 	// If there were changes in this diff, check to see if we have a replacement.
 	var replaces []string
 	var replaced map[string]bool
@@ -1010,8 +1174,10 @@ func (p *Provider) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulum
 func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
 	ctx = p.loggingContext(ctx, resource.URN(req.GetUrn()))
 	urn := resource.URN(req.GetUrn())
+	// The write-only values kept by Check are sent at most once, see [WriteOnlyInputs].
+	defer p.writeOnlyInputs.Forget(urn)
 	t := urn.Type()
-	res, has := p.resources[t]
+	res, has := p.resource(t)
 	if !has {
 		return nil, errors.Errorf("unrecognized resource type (Create): %s", t)
 	}
@@ -1030,10 +1196,27 @@ func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*p
 	if err != nil {
 		return nil, errors.Wrapf(err, "unmarshaling %s's new property state", urn)
 	}
+	props, err = p.writeOnlyInputs.Restore(urn, res.TF.Schema(), res.Schema.Fields, props)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.configDeferred {
+		if !req.GetPreview() {
+			return nil, DeferredConfigureError(fmt.Sprintf("create %s", urn))
+		}
+		outs, err := p.marshalDeferredPreview(res, props, label)
+		if err != nil {
+			return nil, err
+		}
+		return &pulumirpc.CreateResponse{Properties: outs}, nil
+	}
+	tags := p.defaultTags(res)
+	inputs := props
 	// To get Terraform to create a new resource, the ID must be blank and existing state must be empty (since the
 	// resource does not exist yet), and the diff object should have no old state and all of the new state.
 	config, assets, err := makeTerraformConfigWithOpts(
-		ctx, p, props, res.TF.Schema(), res.Schema.Fields,
+		ctx, p, tags.Merge(props, p.configValues), res.TF.Schema(), res.Schema.Fields,
 		makeTerraformConfigOpts{},
 	)
 	if err != nil {
@@ -1045,12 +1228,11 @@ func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*p
 		return nil, errors.Errorf("error decoding timeout: %s", err)
 	}
 
-	diff, err := p.tf.Diff(ctx, res.TFName, nil, config, shim.DiffOptions{
-		TimeoutOptions: shim.TimeoutOptions{
-			ResourceTimeout:  timeouts,
-			TimeoutOverrides: newTimeoutOverrides(shim.TimeoutCreate, req.Timeout),
-		},
-	})
+	timeoutOpts := shim.TimeoutOptions{
+		ResourceTimeout:  timeouts,
+		TimeoutOverrides: newTimeoutOverrides(shim.TimeoutCreate, req.Timeout),
+	}
+	diff, err := p.tf.Diff(ctx, res.TFName, nil, config, shim.DiffOptions{TimeoutOptions: timeoutOpts})
 	if err != nil {
 		return nil, errors.Wrapf(err, "diffing %s", urn)
 	}
@@ -1058,7 +1240,16 @@ func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*p
 	var newstate shim.InstanceState
 	var reasons []string
 	if !req.GetPreview() {
-		newstate, err = p.tf.Apply(ctx, res.TFName, nil, diff)
+		timeout := operationTimeout(timeoutOpts, shim.TimeoutCreate)
+		err = p.operations.Run(ctx, res.Schema, timeout, func(ctx context.Context) error {
+			var err error
+			newstate, err = p.tf.Apply(ctx, res.TFName, nil, diff)
+			if err != nil && newstate != nil && newstate.ID() != "" {
+				// The resource exists: retrying would leak it.
+				return Permanent(err)
+			}
+			return err
+		})
 		if newstate == nil {
 			if err == nil {
 				return nil, fmt.Errorf("expected non-nil error with nil state during Create of %s", urn)
@@ -1085,6 +1276,11 @@ func (p *Provider) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*p
 		reasons = append(reasons, errors.Wrapf(err, "converting result for %s", urn).Error())
 	}
 
+	if props, err = SetStateVersion(res.Schema, props); err != nil {
+		return nil, err
+	}
+	props = tags.SplitOutputs(props, inputs, p.configValues)
+
 	if res.Schema.TransformOutputs != nil {
 		var err error
 		props, err = res.Schema.TransformOutputs(ctx, props)
@@ -1115,7 +1311,7 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 	ctx = p.loggingContext(ctx, resource.URN(req.GetUrn()))
 	urn := resource.URN(req.GetUrn())
 	t := urn.Type()
-	res, has := p.resources[t]
+	res, has := p.resource(t)
 	if !has {
 		return nil, errors.Errorf("unrecognized resource type (Read): %s", t)
 	}
@@ -1130,6 +1326,10 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 	label := fmt.Sprintf("%s.Read(%s, %s/%s)", p.label(), id, urn, res.TFName)
 	glog.V(9).Infof("%s executing", label)
 
+	if p.configDeferred {
+		return nil, DeferredConfigureError(fmt.Sprintf("read %s", urn))
+	}
+
 	// Manufacture Terraform attributes and state with the provided properties, in preparation for reading.
 	oldInputs, err := plugin.UnmarshalProperties(req.GetInputs(), plugin.MarshalOptions{
 		Label: fmt.Sprintf("%s.inputs", label), KeepUnknowns: true,
@@ -1137,7 +1337,22 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 	if err != nil {
 		return nil, err
 	}
-	state, err := UnmarshalTerraformState(ctx, res, id, req.GetProperties(), fmt.Sprintf("%s.state", label))
+	props, err := plugin.UnmarshalProperties(req.GetProperties(), plugin.MarshalOptions{
+		Label:     fmt.Sprintf("%s.state", label),
+		SkipNulls: true,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshaling %s's instance state", urn)
+	}
+	oldInputs, err = MigrateInputs(ctx, res.Schema, props, oldInputs)
+	if err != nil {
+		return nil, err
+	}
+	props, err = p.fromState(ctx, res, props)
+	if err != nil {
+		return nil, err
+	}
+	state, err := makeTerraformStateWithOpts(ctx, res, id, props, makeTerraformStateOpts{})
 	if err != nil {
 		return nil, errors.Wrapf(err, "unmarshaling %s's instance state", urn)
 	}
@@ -1159,12 +1374,20 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 		}
 	}
 
-	config, assets, err := MakeTerraformConfig(ctx, p, oldInputs, res.TF.Schema(), res.Schema.Fields)
+	tags := p.defaultTags(res)
+	config, assets, err := MakeTerraformConfig(ctx, p, tags.Merge(oldInputs, p.configValues),
+		res.TF.Schema(), res.Schema.Fields)
 	if err != nil {
 		return nil, errors.Wrapf(err, "preparing %s's new property state", urn)
 	}
 
-	newstate, err := p.tf.Refresh(ctx, res.TFName, state, config)
+	var newstate shim.InstanceState
+	timeout := operationTimeout(shim.TimeoutOptions{ResourceTimeout: res.TF.Timeouts()}, shim.TimeoutRead)
+	err = p.operations.Run(ctx, res.Schema, timeout, func(ctx context.Context) error {
+		var err error
+		newstate, err = p.tf.Refresh(ctx, res.TFName, state, config)
+		return err
+	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "refreshing %s", urn)
 	}
@@ -1177,6 +1400,15 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 			return nil, err
 		}
 
+		if props, err = SetStateVersion(res.Schema, props); err != nil {
+			return nil, err
+		}
+		var knownInputs resource.PropertyMap
+		if isRefresh {
+			knownInputs = oldInputs
+		}
+		props = tags.SplitOutputs(props, knownInputs, p.configValues)
+
 		if res.Schema.TransformOutputs != nil {
 			var err error
 			props, err = res.Schema.TransformOutputs(ctx, props)
@@ -1193,10 +1425,24 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 			return nil, err
 		}
 
-		inputs, err := ExtractInputsFromOutputs(oldInputs, props, res.TF.Schema(), res.Schema.Fields, isRefresh)
-		if err != nil {
-			return nil, err
+		// Only an import reads a resource by its ID alone, without prior state or inputs. Lookups with `.get()`
+		// that pass no state look the same to the provider, and get the same inputs.
+		isImport := !isRefresh && id != "" && len(req.GetInputs().GetFields()) == 0
+
+		var inputs resource.PropertyMap
+		if isImport && p.info.VerifyImportInputs {
+			var dropped []droppedImportInput
+			inputs, dropped = p.inferImportInputs(ctx, urn, res, newstate, props)
+			if err := p.logDroppedImportInputs(ctx, urn, dropped); err != nil {
+				return nil, err
+			}
+		} else {
+			inputs, err = ExtractInputsFromOutputs(oldInputs, props, res.TF.Schema(), res.Schema.Fields, isRefresh)
+			if err != nil {
+				return nil, err
+			}
 		}
+		inputs = RemoveWriteOnlyValues(res.TF.Schema(), res.Schema.Fields, inputs)
 		minputs, err := plugin.MarshalProperties(inputs, plugin.MarshalOptions{
 			Label:       label + ".inputs",
 			KeepSecrets: p.supportsSecrets,
@@ -1217,8 +1463,10 @@ func (p *Provider) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulum
 func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*pulumirpc.UpdateResponse, error) {
 	ctx = p.loggingContext(ctx, resource.URN(req.GetUrn()))
 	urn := resource.URN(req.GetUrn())
+	// The write-only values kept by Check are sent at most once, see [WriteOnlyInputs].
+	defer p.writeOnlyInputs.Forget(urn)
 	t := urn.Type()
-	res, has := p.resources[t]
+	res, has := p.resource(t)
 	if !has {
 		return nil, errors.Errorf("unrecognized resource type (Update): %s", t)
 	}
@@ -1238,16 +1486,11 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 	if err != nil {
 		return nil, err
 	}
-	olds, err = transformFromState(ctx, res.Schema, olds)
+	olds, err = p.fromState(ctx, res, olds)
 	if err != nil {
 		return nil, err
 	}
 
-	state, err := MakeTerraformState(ctx, res, req.GetId(), olds)
-	if err != nil {
-		return nil, errors.Wrapf(err, "unmarshaling %s's instance state", urn)
-	}
-
 	news, err := plugin.UnmarshalProperties(req.GetNews(),
 		plugin.MarshalOptions{Label: fmt.Sprintf("%s.news", label), KeepUnknowns: true})
 	if err != nil {
@@ -1256,6 +1499,34 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 
 	schema, fields := res.TF.Schema(), res.Schema.Fields
 
+	if p.configDeferred {
+		if !req.GetPreview() {
+			return nil, DeferredConfigureError(fmt.Sprintf("update %s", urn))
+		}
+		outs, err := p.marshalDeferredPreview(res, news, label)
+		if err != nil {
+			return nil, err
+		}
+		return &pulumirpc.UpdateResponse{Properties: outs}, nil
+	}
+
+	// Write-only values are absent from the checked inputs and the prior state. Restore them to the inputs and
+	// assume they are unchanged.
+	news, err = p.writeOnlyInputs.Restore(urn, schema, fields, news)
+	if err != nil {
+		return nil, err
+	}
+	olds = CopyWriteOnlyValues(schema, fields, news, olds)
+
+	state, err := MakeTerraformState(ctx, res, req.GetId(), olds)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unmarshaling %s's instance state", urn)
+	}
+
+	tags := p.defaultTags(res)
+	checkedInputs := news
+	news = tags.Merge(news, p.configValues)
+
 	config, assets, err := MakeTerraformConfig(ctx, p, news, schema, fields)
 	if err != nil {
 		return nil, errors.Wrapf(err, "preparing %s's new property state", urn)
@@ -1268,12 +1539,13 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 		return nil, errors.Errorf("error decoding timeout: %s", err)
 	}
 
+	timeoutOpts := shim.TimeoutOptions{
+		TimeoutOverrides: newTimeoutOverrides(shim.TimeoutUpdate, req.Timeout),
+		ResourceTimeout:  timeouts,
+	}
 	diff, err := p.tf.Diff(ctx, res.TFName, state, config, shim.DiffOptions{
-		IgnoreChanges: ic,
-		TimeoutOptions: shim.TimeoutOptions{
-			TimeoutOverrides: newTimeoutOverrides(shim.TimeoutUpdate, req.Timeout),
-			ResourceTimeout:  timeouts,
-		},
+		IgnoreChanges:  ic,
+		TimeoutOptions: timeoutOpts,
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "diffing %s", urn)
@@ -1295,7 +1567,16 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 	var newstate shim.InstanceState
 	var reasons []string
 	if !req.GetPreview() {
-		newstate, err = p.tf.Apply(ctx, res.TFName, state, diff)
+		timeout := operationTimeout(timeoutOpts, shim.TimeoutUpdate)
+		err = p.operations.Run(ctx, res.Schema, timeout, func(ctx context.Context) error {
+			var err error
+			newstate, err = p.tf.Apply(ctx, res.TFName, state, diff)
+			if err != nil && newstate != nil && newstate.ID() != "" {
+				// The resource was partially updated: retrying would apply the diff to stale state.
+				return Permanent(err)
+			}
+			return err
+		})
 		if newstate == nil {
 			if err != nil {
 				return nil, err
@@ -1323,6 +1604,11 @@ func (p *Provider) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*p
 		reasons = append(reasons, errors.Wrapf(err, "converting result for %s", urn).Error())
 	}
 
+	if props, err = SetStateVersion(res.Schema, props); err != nil {
+		return nil, err
+	}
+	props = tags.SplitOutputs(props, checkedInputs, p.configValues)
+
 	if res.Schema.TransformOutputs != nil {
 		var err error
 		props, err = res.Schema.TransformOutputs(ctx, props)
@@ -1351,7 +1637,7 @@ func (p *Provider) Delete(ctx context.Context, req *pulumirpc.DeleteRequest) (*p
 	ctx = p.loggingContext(ctx, resource.URN(req.GetUrn()))
 	urn := resource.URN(req.GetUrn())
 	t := urn.Type()
-	res, has := p.resources[t]
+	res, has := p.resource(t)
 	if !has {
 		return nil, errors.Errorf("unrecognized resource type (Delete): %s", t)
 	}
@@ -1365,23 +1651,44 @@ func (p *Provider) Delete(ctx context.Context, req *pulumirpc.DeleteRequest) (*p
 	label := fmt.Sprintf("%s.Delete(%s/%s)", p.label(), urn, res.TFName)
 	glog.V(9).Infof("%s executing", label)
 
+	if p.configDeferred {
+		return nil, DeferredConfigureError(fmt.Sprintf("delete %s", urn))
+	}
+
 	// Fetch the resource attributes since many providers need more than just the ID to perform the delete.
-	state, err := UnmarshalTerraformState(ctx, res, req.GetId(), req.GetProperties(), label)
+	state, err := p.unmarshalTerraformState(ctx, res, req.GetId(), req.GetProperties(), label)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create a new destroy diff.
-	diff := p.tf.NewDestroyDiff(ctx, res.TFName, shim.TimeoutOptions{
+	timeoutOpts := shim.TimeoutOptions{
 		TimeoutOverrides: newTimeoutOverrides(shim.TimeoutDelete, req.Timeout),
 		ResourceTimeout:  res.TF.Timeouts(),
-	})
-	if _, err := p.tf.Apply(ctx, res.TFName, state, diff); err != nil {
+	}
+	diff := p.tf.NewDestroyDiff(ctx, res.TFName, timeoutOpts)
+	timeout := operationTimeout(timeoutOpts, shim.TimeoutDelete)
+	if err := p.operations.Run(ctx, res.Schema, timeout, func(ctx context.Context) error {
+		_, err := p.tf.Apply(ctx, res.TFName, state, diff)
+		return err
+	}); err != nil {
 		return nil, errors.Wrapf(err, "deleting %s", urn)
 	}
 	return &pbempty.Empty{}, nil
 }
 
+// marshalDeferredPreview marshals the outputs of a preview of res computed with DeferredPreviewOutputs.
+func (p *Provider) marshalDeferredPreview(
+	res Resource, inputs resource.PropertyMap, label string,
+) (*pbstruct.Struct, error) {
+	outs := DeferredPreviewOutputs(res.TF.Schema(), res.Schema.Fields, inputs)
+	return plugin.MarshalProperties(outs, plugin.MarshalOptions{
+		Label:        fmt.Sprintf("%s.outs", label),
+		KeepUnknowns: true,
+		KeepSecrets:  p.supportsSecrets,
+	})
+}
+
 // Construct creates a new instance of the provided component resource and returns its state.
 func (p *Provider) Construct(context.Context, *pulumirpc.ConstructRequest) (*pulumirpc.ConstructResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "Construct is not yet implemented")
@@ -1396,7 +1703,7 @@ func (p *Provider) Call(ctx context.Context, req *pulumirpc.CallRequest) (*pulum
 func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*pulumirpc.InvokeResponse, error) {
 	ctx = p.loggingContext(ctx, "")
 	tok := tokens.ModuleMember(req.GetTok())
-	ds, has := p.dataSources[tok]
+	ds, has := p.dataSource(tok)
 	if !has {
 		return nil, errors.Errorf("unrecognized data function (Invoke): %s", tok)
 	}
@@ -1410,6 +1717,10 @@ func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*p
 	label := fmt.Sprintf("%s.Invoke(%s)", p.label(), tok)
 	glog.V(9).Infof("%s executing", label)
 
+	if p.configDeferred {
+		return nil, DeferredConfigureError(fmt.Sprintf("invoke %s", tok))
+	}
+
 	// Unmarshal the arguments.
 	args, err := plugin.UnmarshalProperties(req.GetArgs(), plugin.MarshalOptions{
 		Label: fmt.Sprintf("%s.args", label), KeepUnknowns: true, SkipNulls: true,
@@ -1418,6 +1729,13 @@ func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*p
 		return nil, err
 	}
 
+	if preInvoke := ds.Schema.PreInvokeCallback; preInvoke != nil {
+		args, err = preInvoke(ctx, args, p.configValues.Copy())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// First, create the inputs.
 	tfname := ds.TFName
 	inputs, _, err := MakeTerraformInputs(
@@ -1456,7 +1774,12 @@ func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*p
 			return nil, errors.Wrapf(err, "reading data source diff for %s", tok)
 		}
 
-		invoke, err := p.tf.ReadDataApply(ctx, tfname, diff)
+		var invoke shim.InstanceState
+		err = p.operations.Run(ctx, nil, 0, func(ctx context.Context) error {
+			var err error
+			invoke, err = p.tf.ReadDataApply(ctx, tfname, diff)
+			return err
+		})
 		if err != nil {
 			return nil, errors.Wrapf(err, "invoking %s", tok)
 		}
@@ -1470,6 +1793,13 @@ func (p *Provider) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*p
 			props["id"] = resource.NewStringProperty(invoke.ID())
 		}
 
+		if transform := ds.Schema.TransformOutputs; transform != nil {
+			props, err = transform(ctx, props)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		ret, err = plugin.MarshalProperties(
 			props,
 			plugin.MarshalOptions{Label: fmt.Sprintf("%s.returns", label)})
@@ -1688,6 +2018,40 @@ func True() *bool { return ref(true) }
 // False is used for interactions in the providers that require a pointer to false
 func False() *bool { return ref(false) }
 
+func (p *Provider) defaultTags(res Resource) *ResourceDefaultTags {
+	return NewResourceDefaultTags(p.info.DefaultTags, res.TFName, res.TF.Schema(), res.Schema.GetFields())
+}
+
+// fromState prepares a resource state recorded by Pulumi for use with the upstream resource.
+func (p *Provider) fromState(
+	ctx context.Context, res Resource, state resource.PropertyMap,
+) (resource.PropertyMap, error) {
+	state, err := MigrateState(ctx, res.Schema, state)
+	if err != nil {
+		return nil, err
+	}
+	state = p.defaultTags(res).JoinState(state)
+	return transformFromState(ctx, res.Schema, state)
+}
+
+// unmarshalTerraformState is UnmarshalTerraformState with the state prepared by fromState.
+func (p *Provider) unmarshalTerraformState(
+	ctx context.Context, res Resource, id string, m *pbstruct.Struct, l string,
+) (shim.InstanceState, error) {
+	props, err := plugin.UnmarshalProperties(m, plugin.MarshalOptions{
+		Label:     fmt.Sprintf("%s.state", l),
+		SkipNulls: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	props, err = p.fromState(ctx, res, props)
+	if err != nil {
+		return nil, err
+	}
+	return makeTerraformStateWithOpts(ctx, res, id, props, makeTerraformStateOpts{})
+}
+
 func transformFromState(
 	ctx context.Context, res *ResourceInfo, inputs resource.PropertyMap,
 ) (resource.PropertyMap, error) {
@@ -1174,6 +1174,47 @@ func TestCheckConfig(t *testing.T) {
 		}`)
 	})
 
+	t.Run("autonaming", func(t *testing.T) {
+		provider := &Provider{
+			tf:     shimv2.NewProvider(testTFProviderV2),
+			config: shimv2.NewSchemaMap(testTFProviderV2.Schema),
+		}
+		// The autonaming key is not passed to the TF provider, but invalid values are reported.
+		testutils.Replay(t, provider, `
+		{
+		  "method": "/pulumirpc.ResourceProvider/CheckConfig",
+		  "request": {
+		    "urn": "urn:pulumi:dev::teststack::pulumi:providers:testprovider::test",
+		    "olds": {},
+		    "news": {
+		      "autonaming": "{\"pattern\": \"${name}-${hex(6)}\"}"
+		    }
+		  },
+		  "response": {
+		    "inputs": {
+		      "autonaming": "{\"pattern\": \"${name}-${hex(6)}\"}"
+		    }
+		  }
+		}`)
+		testutils.Replay(t, provider, `
+		{
+		  "method": "/pulumirpc.ResourceProvider/CheckConfig",
+		  "request": {
+		    "urn": "urn:pulumi:dev::teststack::pulumi:providers:testprovider::test",
+		    "olds": {},
+		    "news": {
+		      "autonaming": "{\"mode\": \"random\"}"
+		    }
+		  },
+		  "response": {
+		    "failures": [{
+		      "property": "autonaming",
+		      "reason": "autonaming: unknown mode \"random\", expected \"default\", \"verbatim\" or \"disabled\""
+		    }]
+		  }
+		}`)
+	})
+
 	t.Run("unknown_config_value", func(t *testing.T) {
 		// Currently if a top-level config property is a Computed value, or it's a composite value with any
 		// Computed values inside, the engine sends a sentinel string. Ensure that CheckConfig propagates the
@@ -1968,6 +2009,67 @@ func TestInvoke(t *testing.T) {
 		  }
 		}`)
 	})
+
+	t.Run("hooks", func(t *testing.T) {
+		ds := testprovider.ProviderV2().DataSourcesMap["example_resource"]
+		provider := &Provider{
+			tf:           shimv2.NewProvider(testTFProviderV2),
+			config:       shimv2.NewSchemaMap(testTFProviderV2.Schema),
+			configValues: resource.PropertyMap{"prefix": resource.NewStringProperty("cfg-")},
+			dataSources: map[tokens.ModuleMember]DataSource{
+				"tprov:index/ExampleFn:ExampleFn": {
+					TF:     shimv2.NewResource(ds),
+					TFName: "example_resource",
+					Schema: &DataSourceInfo{
+						Tok: "tprov:index/ExampleFn:ExampleFn",
+						PreInvokeCallback: func(
+							_ context.Context, args, config resource.PropertyMap,
+						) (resource.PropertyMap, error) {
+							args = args.Copy()
+							args["stringPropertyValue"] = resource.NewStringProperty(
+								config["prefix"].StringValue() + args["stringPropertyValue"].StringValue())
+							return args, nil
+						},
+						TransformOutputs: func(
+							_ context.Context, pm resource.PropertyMap,
+						) (resource.PropertyMap, error) {
+							pm = pm.Copy()
+							pm["stringPropertyValue"] = resource.NewStringProperty(
+								pm["stringPropertyValue"].StringValue() + "-TRANSFORMED")
+							return pm, nil
+						},
+					},
+				},
+			},
+		}
+
+		testutils.Replay(t, provider, `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Invoke",
+		  "request": {
+		    "tok": "tprov:index/ExampleFn:ExampleFn",
+		    "args": {
+		      "stringPropertyValue": "foo",
+		      "arrayPropertyValues": []
+		    }
+		  },
+		  "response": {
+		    "return": {
+		      "stringPropertyValue": "cfg-foo-TRANSFORMED",
+		      "__meta": "*",
+		      "arrayPropertyValues": "*",
+		      "boolPropertyValue": "*",
+		      "floatPropertyValue": "*",
+		      "id": "*",
+		      "nestedResources": "*",
+		      "numberPropertyValue": "*",
+		      "objectPropertyValue": "*",
+		      "setPropertyValues": "*",
+		      "stringWithBadInterpolation": "*"
+		    }
+		  }
+		}`)
+	})
 }
 
 func TestTransformOutputs(t *testing.T) {
@@ -3009,6 +3111,123 @@ func TestRefresh(t *testing.T) {
 	})
 }
 
+func TestImportVerifyInputs(t *testing.T) {
+	p := testprovider.ProviderV2()
+	er := p.ResourcesMap["example_resource"]
+	er.Read = nil //nolint
+	er.ReadContext = func(ctx context.Context, rd *schema.ResourceData, i interface{}) diag.Diagnostics {
+		require.NoError(t, rd.Set("string_property_value", "imported"))
+		require.NoError(t, rd.Set("region", "us-east-1"))
+		require.NoError(t, rd.Set("normalized", "abc"))
+		return diag.Diagnostics{}
+	}
+	er.Importer = &schema.ResourceImporter{
+		StateContext: schema.ImportStatePassthroughContext,
+	}
+	er.Schema = map[string]*schema.Schema{
+		"string_property_value": {Type: schema.TypeString, Optional: true},
+		"region":                {Type: schema.TypeString, Optional: true},
+		"normalized":            {Type: schema.TypeString, Optional: true, Computed: true},
+	}
+	// Emulate a provider that normalizes a configured value, so that the value read back from the
+	// cloud always diffs against the configuration.
+	er.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, i interface{}) error {
+		if v := d.GetRawConfig().GetAttr("normalized"); v.IsKnown() && !v.IsNull() {
+			return d.SetNew("normalized", strings.ToUpper(v.AsString()))
+		}
+		return nil
+	}
+
+	shimProv := shimv2.NewProvider(p)
+	provider := &Provider{
+		tf:     shimProv,
+		config: shimv2.NewSchemaMap(p.Schema),
+		info: ProviderInfo{
+			P:              shimProv,
+			ResourcePrefix: "example",
+			Resources: map[string]*ResourceInfo{
+				"example_resource": {
+					Tok: "ExampleResource",
+					Fields: map[string]*SchemaInfo{
+						"region": {Default: &DefaultInfo{Value: "us-east-1"}},
+					},
+				},
+				"second_resource":        {Tok: "SecondResource"},
+				"nested_secret_resource": {Tok: "NestedSecretResource"},
+			},
+			VerifyImportInputs: true,
+		},
+	}
+	provider.initResourceMaps()
+
+	testutils.Replay(t, provider, `
+	{
+	  "method": "/pulumirpc.ResourceProvider/Read",
+	  "request": {
+	    "id": "res1",
+	    "urn": "urn:pulumi:dev::mystack::ExampleResource::res1name",
+	    "properties": {}
+	  },
+	  "response": {
+	    "inputs": {
+	      "__defaults": [],
+	      "stringPropertyValue": "imported"
+	    },
+	    "properties": "*",
+	    "id": "res1"
+	  }
+	}`)
+
+	t.Run("with inputs", func(t *testing.T) {
+		// Reads that carry inputs are not imports, so their inputs are not inferred.
+		testutils.Replay(t, provider, `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "res1",
+		    "urn": "urn:pulumi:dev::mystack::ExampleResource::res1name",
+		    "properties": {},
+		    "inputs": {
+		      "stringPropertyValue": "imported"
+		    }
+		  },
+		  "response": {
+		    "inputs": {
+		      "__defaults": [],
+		      "stringPropertyValue": "imported",
+		      "region": "us-east-1",
+		      "normalized": "abc"
+		    },
+		    "properties": "*",
+		    "id": "res1"
+		  }
+		}`)
+	})
+
+	t.Run("without verification", func(t *testing.T) {
+		provider.info.VerifyImportInputs = false
+		testutils.Replay(t, provider, `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "res1",
+		    "urn": "urn:pulumi:dev::mystack::ExampleResource::res1name",
+		    "properties": {}
+		  },
+		  "response": {
+		    "inputs": {
+		      "__defaults": [],
+		      "stringPropertyValue": "imported",
+		      "region": "us-east-1",
+		      "normalized": "abc"
+		    },
+		    "properties": "*",
+		    "id": "res1"
+		  }
+		}`)
+	})
+}
+
 func testRefresh(t *testing.T, newProvider func(*schema.Provider) shim.Provider) {
 	init := func(rcf schema.ReadContextFunc) *Provider {
 		p := testprovider.ProviderV2()
@@ -3925,3 +4144,238 @@ func TestIgnoreMappings(t *testing.T) {
 		}
 	})
 }
+
+func TestWriteOnlyValuesAreNeverStored(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	const password = "hunter2-write-only"
+
+	var created, updated string
+	upstream := &schema.Provider{
+		ResourcesMap: map[string]*schema.Resource{
+			"test_res": {
+				Schema: map[string]*schema.Schema{
+					"name":     {Type: schema.TypeString, Optional: true},
+					"password": {Type: schema.TypeString, Optional: true},
+				},
+				CreateContext: func(_ context.Context, rd *schema.ResourceData, _ interface{}) diag.Diagnostics {
+					created = rd.Get("password").(string)
+					rd.SetId("r1")
+					return nil
+				},
+				UpdateContext: func(_ context.Context, rd *schema.ResourceData, _ interface{}) diag.Diagnostics {
+					updated = rd.Get("password").(string)
+					return nil
+				},
+				ReadContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+					return nil
+				},
+				DeleteContext: func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+					return nil
+				},
+			},
+		},
+	}
+	p := &Provider{
+		tf: shimv2.NewProvider(upstream),
+		info: ProviderInfo{
+			Name: "test",
+			Resources: map[string]*ResourceInfo{
+				"test_res": {
+					Tok:    "test:index:Res",
+					Fields: map[string]*SchemaInfo{"password": {WriteOnly: true}},
+				},
+			},
+		},
+		supportsSecrets: true,
+	}
+	urn := "urn:pulumi:dev::teststack::test:index:Res::res"
+
+	marshal := func(pm resource.PropertyMap) *structpb.Struct {
+		m, err := plugin.MarshalProperties(pm, plugin.MarshalOptions{KeepSecrets: true})
+		require.NoError(t, err)
+		return m
+	}
+	assertNotStored := func(t *testing.T, resp interface{ String() string }) {
+		assert.NotContains(t, resp.String(), "hunter2")
+	}
+
+	check := func(name string) *structpb.Struct {
+		resp, err := p.Check(ctx, &pulumirpc.CheckRequest{
+			Urn: urn,
+			News: marshal(resource.PropertyMap{
+				"name":     resource.NewStringProperty(name),
+				"password": resource.NewStringProperty(password),
+			}),
+		})
+		require.NoError(t, err)
+		assertNotStored(t, resp)
+		return resp.GetInputs()
+	}
+
+	inputs := check("a")
+	createResp, err := p.Create(ctx, &pulumirpc.CreateRequest{Urn: urn, Properties: inputs})
+	require.NoError(t, err)
+	assertNotStored(t, createResp)
+	assert.Equal(t, password, created, "Create should receive the write-only value")
+
+	newInputs := check("b")
+	diffResp, err := p.Diff(ctx, &pulumirpc.DiffRequest{
+		Id: "r1", Urn: urn, Olds: createResp.GetProperties(), News: newInputs,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name"}, diffResp.GetDiffs())
+
+	updateResp, err := p.Update(ctx, &pulumirpc.UpdateRequest{
+		Id: "r1", Urn: urn, Olds: createResp.GetProperties(), News: newInputs,
+	})
+	require.NoError(t, err)
+	assertNotStored(t, updateResp)
+	assert.Equal(t, password, updated, "Update should receive the write-only value")
+
+	readResp, err := p.Read(ctx, &pulumirpc.ReadRequest{
+		Id: "r1", Urn: urn, Properties: updateResp.GetProperties(), Inputs: newInputs,
+	})
+	require.NoError(t, err)
+	assertNotStored(t, readResp)
+
+	// Changing only the write-only value does not trigger an update.
+	p.writeOnlyInputs.Stash(resource.URN(urn), p.tf.ResourcesMap().Get("test_res").Schema(),
+		p.info.Resources["test_res"].Fields, resource.PropertyMap{
+			"name":     resource.NewStringProperty("b"),
+			"password": resource.NewStringProperty("changed"),
+		})
+	diffResp, err = p.Diff(ctx, &pulumirpc.DiffRequest{
+		Id: "r1", Urn: urn, Olds: updateResp.GetProperties(), News: newInputs,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_NONE, diffResp.GetChanges())
+	assert.Empty(t, p.writeOnlyInputs.values, "a diff without changes should drop the write-only values")
+
+	// Without a Check in this process, Update fails instead of sending no value to the provider.
+	_, err = p.Update(ctx, &pulumirpc.UpdateRequest{
+		Id: "r1", Urn: urn, Olds: updateResp.GetProperties(), News: newInputs,
+	})
+	assert.ErrorContains(t, err, "write-only values of "+urn+" are missing")
+}
+
+func TestResourceMapsAreInitializedLazily(t *testing.T) {
+	t.Parallel()
+	tf := shimv2.NewProvider(&schemav2.Provider{
+		ResourcesMap: map[string]*schemav2.Resource{
+			"test_r1": {DeprecationMessage: "r1"},
+			"test_r2": {DeprecationMessage: "r2"},
+			"test_r3": {DeprecationMessage: "r3"},
+		},
+		DataSourcesMap: map[string]*schemav2.Resource{
+			"test_d1": {DeprecationMessage: "d1"},
+		},
+	})
+	p := newProvider(context.Background(), nil, "test", "1.0.0", tf, ProviderInfo{
+		Name:           "test",
+		ResourcePrefix: "test",
+		Resources: map[string]*ResourceInfo{
+			"test_r1": {Tok: "test:index:R1"},
+			"test_r2": {Tok: "test:index:R2"},
+		},
+		DataSources: map[string]*DataSourceInfo{"test_d1": {Tok: "test:index:getD1"}},
+	}, nil)
+	assert.Nil(t, p.resources, "resources should not be indexed on startup")
+	assert.Nil(t, p.dataSources, "data sources should not be indexed on startup")
+
+	r, ok := p.resource("test:index:R1")
+	if assert.True(t, ok) {
+		assert.Equal(t, "r1", r.TF.DeprecationMessage())
+	}
+	assert.Len(t, p.resources, 1, "tokens recorded in ProviderInfo should only map the requested resource")
+	d, ok := p.dataSource("test:index:getD1")
+	if assert.True(t, ok) {
+		assert.Equal(t, "d1", d.TF.DeprecationMessage())
+	}
+	assert.Len(t, p.dataSources, 1)
+
+	// Tokens that ProviderInfo does not record are resolved by mapping all the resources.
+	r, ok = p.resource("test:r3:R3")
+	if assert.True(t, ok) {
+		assert.Equal(t, "r3", r.TF.DeprecationMessage())
+	}
+	assert.Len(t, p.resources, 3)
+	_, ok = p.resource("test:index:Missing")
+	assert.False(t, ok)
+}
+
+func TestWriteOnly(t *testing.T) {
+	provider := &Provider{
+		tf:     shimv2.NewProvider(testTFProviderV2),
+		config: shimv2.NewSchemaMap(testTFProviderV2.Schema),
+		resources: map[tokens.Type]Resource{
+			"ExampleResource": {
+				TF:     shimv2.NewResource(testTFProviderV2.ResourcesMap["example_resource"]),
+				TFName: "example_resource",
+				Schema: &ResourceInfo{
+					Tok: "ExampleResource",
+					Fields: map[string]*SchemaInfo{
+						"string_property_value": {WriteOnly: true},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		// Create only receives write-only values that Check kept in this provider process.
+		res := provider.resources["ExampleResource"]
+		provider.writeOnlyInputs.Stash("urn:pulumi:dev::teststack::ExampleResource::exres", res.TF.Schema(),
+			res.Schema.Fields, resource.PropertyMap{"stringPropertyValue": resource.NewStringProperty("password")})
+		testutils.Replay(t, provider, `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Create",
+		  "request": {
+		    "urn": "urn:pulumi:dev::teststack::ExampleResource::exres",
+		    "properties": {
+		      "__defaults": [],
+		      "stringPropertyValue": "password"
+		    }
+		  },
+		  "response": {
+		    "id": "*",
+		    "properties": {
+		      "id": "*",
+		      "boolPropertyValue": "*",
+		      "__meta": "*",
+		      "objectPropertyValue": "*",
+		      "floatPropertyValue": "*",
+		      "arrayPropertyValues": "*",
+		      "nestedResources": "*",
+		      "numberPropertyValue": "*",
+		      "setPropertyValues": "*",
+		      "stringWithBadInterpolation": "*"
+		    }
+		  }
+		}`)
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		testutils.Replay(t, provider, `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Diff",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::ExampleResource::exres",
+		    "olds": {
+		      "id": "0",
+		      "boolPropertyValue": true
+		    },
+		    "news": {
+		      "boolPropertyValue": true,
+		      "stringPropertyValue": "password"
+		    }
+		  },
+		  "response": {
+		    "changes": "DIFF_NONE",
+		    "stables": "*",
+		    "hasDetailedDiff": true
+		  }
+		}`)
+	})
+}
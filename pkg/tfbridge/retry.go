@@ -0,0 +1,230 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"math/rand"
+	"regexp"
+	"time"
+
+	"github.com/golang/glog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = time.Second
+	defaultRetryMaxBackoff     = 30 * time.Second
+)
+
+// RetryPolicy retries calls into the upstream provider that failed with a transient error, such as the eventual
+// consistency errors of some cloud APIs.
+//
+// The policy applies to the calls that create, read, update and delete resources and read data sources. A failed
+// Create or Update that returned the state of the resource is never retried: the bridge reports it as a partial
+// failure so that the resource is recorded in the Pulumi state.
+type RetryPolicy struct {
+	// Retry errors whose message matches any of these regular expressions.
+	ErrorMessages []*regexp.Regexp
+
+	// Retry errors carrying any of these gRPC status codes.
+	StatusCodes []codes.Code
+
+	// The maximum number of attempts, including the first one. Defaults to 3.
+	MaxAttempts int
+
+	// The delay before the first retry. The delay doubles after each attempt, up to MaxBackoff, and is randomly
+	// reduced by up to half to spread out retries. Defaults to 1s.
+	InitialBackoff time.Duration
+
+	// The maximum delay between attempts. Defaults to 30s.
+	MaxBackoff time.Duration
+
+	// The time after which no new attempt is started. Defaults to the timeout of the operation, as set by the
+	// customTimeouts resource option or the timeouts of the upstream resource. When neither is set, only
+	// MaxAttempts bounds the retries.
+	Deadline time.Duration
+}
+
+// Permanent wraps err so that [OperationRunner.Run] returns it without retrying the operation, for example because the
+// failed attempt made progress that a retry would lose. Run returns err itself.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+type permanentError struct{ error }
+
+func (e permanentError) Unwrap() error { return e.error }
+
+// unwrapPermanent returns err without the wrapper added by Permanent, and whether err may be retried.
+func unwrapPermanent(err error) (error, bool) {
+	if p, ok := err.(permanentError); ok {
+		return p.error, false
+	}
+	return err, true
+}
+
+func (r *RetryPolicy) retryable(err error) bool {
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		for _, c := range r.StatusCodes {
+			if s.Code() == c {
+				return true
+			}
+		}
+	}
+	msg := err.Error()
+	for _, re := range r.ErrorMessages {
+		if re.MatchString(msg) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *RetryPolicy) maxAttempts() int {
+	if r.MaxAttempts > 0 {
+		return r.MaxAttempts
+	}
+	return defaultRetryMaxAttempts
+}
+
+// backoff returns the delay before retrying after the given number of failed attempts.
+func (r *RetryPolicy) backoff(attempts int) time.Duration {
+	d, maxDelay := r.InitialBackoff, r.MaxBackoff
+	if d <= 0 {
+		d = defaultRetryInitialBackoff
+	}
+	if maxDelay <= 0 {
+		maxDelay = defaultRetryMaxBackoff
+	}
+	for i := 1; i < attempts && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	//nolint:gosec // Jitter does not need a cryptographically secure source.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// OperationRunner runs calls into the upstream provider according to [ProviderInfo.RetryPolicy],
+// [ResourceInfo.RetryPolicy] and [ProviderInfo.MaxConcurrentOperations].
+//
+// A nil *OperationRunner runs each call once.
+type OperationRunner struct {
+	policy *RetryPolicy
+	// sem limits the number of concurrent attempts, or is nil if there is no limit.
+	sem chan struct{}
+}
+
+// NewOperationRunner returns the runner for calls into the upstream provider of info.
+func NewOperationRunner(info *ProviderInfo) *OperationRunner {
+	r := &OperationRunner{policy: info.RetryPolicy}
+	if info.MaxConcurrentOperations > 0 {
+		r.sem = make(chan struct{}, info.MaxConcurrentOperations)
+	}
+	return r
+}
+
+// Run calls op, retrying it according to the retry policy of res, or of the provider if res is nil or has none.
+//
+// timeout is the timeout of the operation, or 0 if there is none. It bounds the retries unless the policy sets a
+// Deadline.
+func (r *OperationRunner) Run(
+	ctx context.Context, res *ResourceInfo, timeout time.Duration, op func(context.Context) error,
+) error {
+	if r == nil {
+		err, _ := unwrapPermanent(op(ctx))
+		return err
+	}
+	policy := r.policy
+	if res != nil && res.RetryPolicy != nil {
+		policy = res.RetryPolicy
+	}
+	if policy == nil {
+		err, _ := unwrapPermanent(r.attempt(ctx, op))
+		return err
+	}
+
+	deadline := policy.Deadline
+	if deadline <= 0 {
+		deadline = timeout
+	}
+	start := time.Now()
+	for attempts := 1; ; attempts++ {
+		err, retry := unwrapPermanent(r.attempt(ctx, op))
+		if err == nil || !retry || attempts >= policy.maxAttempts() || !policy.retryable(err) {
+			return err
+		}
+		delay := policy.backoff(attempts)
+		if deadline > 0 && time.Since(start)+delay >= deadline {
+			return err
+		}
+		glog.V(5).Infof("retrying in %v after attempt %d failed: %v", delay, attempts, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (r *OperationRunner) attempt(ctx context.Context, op func(context.Context) error) error {
+	if r.sem != nil {
+		select {
+		case r.sem <- struct{}{}:
+			defer func() { <-r.sem }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return op(ctx)
+}
+
+// operationTimeout returns the timeout of the operation key under opts, or 0 if there is none.
+func operationTimeout(opts shim.TimeoutOptions, key shim.TimeoutKey) time.Duration {
+	if d, ok := opts.TimeoutOverrides[key]; ok {
+		return d
+	}
+	t := opts.ResourceTimeout
+	if t == nil {
+		return 0
+	}
+	var d *time.Duration
+	switch key {
+	case shim.TimeoutCreate:
+		d = t.Create
+	case shim.TimeoutRead:
+		d = t.Read
+	case shim.TimeoutUpdate:
+		d = t.Update
+	case shim.TimeoutDelete:
+		d = t.Delete
+	}
+	if d == nil {
+		d = t.Default
+	}
+	if d == nil {
+		return 0
+	}
+	return *d
+}
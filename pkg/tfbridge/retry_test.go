@@ -0,0 +1,284 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	testutils "github.com/pulumi/providertest/replay"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+// failing returns an operation that fails with err until it has been called n times.
+func failing(n int, err error) (op func(context.Context) error, calls *int) {
+	calls = new(int)
+	return func(context.Context) error {
+		*calls++
+		if *calls <= n {
+			return err
+		}
+		return nil
+	}, calls
+}
+
+func TestOperationRunnerRetries(t *testing.T) {
+	t.Parallel()
+	policy := &RetryPolicy{
+		ErrorMessages:  []*regexp.Regexp{regexp.MustCompile("not yet consistent")},
+		StatusCodes:    []codes.Code{codes.Unavailable},
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	}
+	consistencyErr := fmt.Errorf("role is not yet consistent")
+
+	tests := []struct {
+		name          string
+		failures      int
+		err           error
+		policy        *RetryPolicy
+		timeout       time.Duration
+		expectCalls   int
+		expectFailure bool
+	}{
+		{name: "success", expectCalls: 1},
+		{name: "matching message", failures: 2, err: consistencyErr, expectCalls: 3},
+		{name: "matching status", failures: 1, err: status.Error(codes.Unavailable, "down"), expectCalls: 2},
+		{
+			name: "other status", failures: 1, err: status.Error(codes.NotFound, "gone"),
+			expectCalls: 1, expectFailure: true,
+		},
+		{name: "other message", failures: 1, err: fmt.Errorf("boom"), expectCalls: 1, expectFailure: true},
+		{name: "max attempts", failures: 3, err: consistencyErr, expectCalls: 3, expectFailure: true},
+		{
+			name: "timeout", failures: 2, err: consistencyErr, timeout: time.Nanosecond,
+			expectCalls: 1, expectFailure: true,
+		},
+		{name: "no policy", failures: 1, err: consistencyErr, policy: &RetryPolicy{}, expectCalls: 1, expectFailure: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := NewOperationRunner(&ProviderInfo{RetryPolicy: policy})
+			op, calls := failing(tt.failures, tt.err)
+			err := r.Run(context.Background(), &ResourceInfo{RetryPolicy: tt.policy}, tt.timeout, op)
+			assert.Equal(t, tt.expectCalls, *calls)
+			if tt.expectFailure {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOperationRunnerPermanent(t *testing.T) {
+	t.Parallel()
+	r := NewOperationRunner(&ProviderInfo{RetryPolicy: &RetryPolicy{
+		ErrorMessages:  []*regexp.Regexp{regexp.MustCompile("not yet consistent")},
+		InitialBackoff: time.Millisecond,
+	}})
+	consistencyErr := fmt.Errorf("role is not yet consistent")
+	op, calls := failing(1, Permanent(consistencyErr))
+	err := r.Run(context.Background(), nil, 0, op)
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, consistencyErr, err)
+}
+
+func TestOperationRunnerLimitsConcurrency(t *testing.T) {
+	t.Parallel()
+	r := NewOperationRunner(&ProviderInfo{MaxConcurrentOperations: 2})
+
+	var running, maxRunning int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := r.Run(context.Background(), nil, 0, func(context.Context) error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), maxRunning)
+}
+
+func TestOperationTimeout(t *testing.T) {
+	t.Parallel()
+	d := func(d time.Duration) *time.Duration { return &d }
+	timeouts := &shim.ResourceTimeout{Create: d(time.Minute), Default: d(time.Hour)}
+
+	assert.Equal(t, time.Duration(0), operationTimeout(shim.TimeoutOptions{}, shim.TimeoutCreate))
+	assert.Equal(t, time.Minute, operationTimeout(shim.TimeoutOptions{ResourceTimeout: timeouts}, shim.TimeoutCreate))
+	assert.Equal(t, time.Hour, operationTimeout(shim.TimeoutOptions{ResourceTimeout: timeouts}, shim.TimeoutDelete))
+	assert.Equal(t, time.Second, operationTimeout(shim.TimeoutOptions{
+		ResourceTimeout:  timeouts,
+		TimeoutOverrides: map[shim.TimeoutKey]time.Duration{shim.TimeoutCreate: time.Second},
+	}, shim.TimeoutCreate))
+}
+
+func TestRetryCreate(t *testing.T) {
+	t.Parallel()
+	var calls int
+	p := &schemav2.Provider{
+		ResourcesMap: map[string]*schemav2.Resource{
+			"res": {
+				Schema: map[string]*schemav2.Schema{
+					"name": {Type: schemav2.TypeString, Optional: true},
+				},
+				CreateContext: func(_ context.Context, rd *schemav2.ResourceData, _ any) diag.Diagnostics {
+					calls++
+					if calls == 1 {
+						return diag.Errorf("role is not yet consistent")
+					}
+					rd.SetId("r1")
+					return nil
+				},
+				ReadContext: func(context.Context, *schemav2.ResourceData, any) diag.Diagnostics {
+					return nil
+				},
+				DeleteContext: func(context.Context, *schemav2.ResourceData, any) diag.Diagnostics {
+					return nil
+				},
+			},
+		},
+	}
+	shimProv := shimv2.NewProvider(p)
+	info := ProviderInfo{
+		P: shimProv,
+		RetryPolicy: &RetryPolicy{
+			ErrorMessages:  []*regexp.Regexp{regexp.MustCompile("not yet consistent")},
+			InitialBackoff: time.Millisecond,
+		},
+	}
+	provider := &Provider{
+		tf:         shimProv,
+		config:     shimv2.NewSchemaMap(p.Schema),
+		info:       info,
+		operations: NewOperationRunner(&info),
+		resources: map[tokens.Type]Resource{
+			"Res": {
+				TF:     shimv2.NewResource(p.ResourcesMap["res"]),
+				TFName: "res",
+				Schema: &ResourceInfo{Tok: "Res"},
+			},
+		},
+	}
+
+	testutils.Replay(t, provider, `
+	{
+	  "method": "/pulumirpc.ResourceProvider/Create",
+	  "request": {
+	    "urn": "urn:pulumi:dev::teststack::Res::exres",
+	    "properties": {"name": "n"}
+	  },
+	  "response": {
+	    "id": "r1",
+	    "properties": "*"
+	  }
+	}`)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetryCreateWithPartialState(t *testing.T) {
+	t.Parallel()
+	var calls int
+	p := &schemav2.Provider{
+		ResourcesMap: map[string]*schemav2.Resource{
+			"res": {
+				Schema: map[string]*schemav2.Schema{
+					"name": {Type: schemav2.TypeString, Optional: true},
+				},
+				CreateContext: func(_ context.Context, rd *schemav2.ResourceData, _ any) diag.Diagnostics {
+					calls++
+					rd.SetId(fmt.Sprintf("r%d", calls))
+					return diag.Errorf("role is not yet consistent")
+				},
+				ReadContext: func(context.Context, *schemav2.ResourceData, any) diag.Diagnostics {
+					return nil
+				},
+				DeleteContext: func(context.Context, *schemav2.ResourceData, any) diag.Diagnostics {
+					return nil
+				},
+			},
+		},
+	}
+	shimProv := shimv2.NewProvider(p)
+	info := ProviderInfo{
+		P: shimProv,
+		RetryPolicy: &RetryPolicy{
+			ErrorMessages:  []*regexp.Regexp{regexp.MustCompile("not yet consistent")},
+			InitialBackoff: time.Millisecond,
+		},
+	}
+	provider := &Provider{
+		tf:         shimProv,
+		config:     shimv2.NewSchemaMap(p.Schema),
+		info:       info,
+		operations: NewOperationRunner(&info),
+		resources: map[tokens.Type]Resource{
+			"Res": {
+				TF:     shimv2.NewResource(p.ResourcesMap["res"]),
+				TFName: "res",
+				Schema: &ResourceInfo{Tok: "Res"},
+			},
+		},
+	}
+
+	_, err := provider.Create(context.Background(), &pulumirpc.CreateRequest{
+		Urn: "urn:pulumi:dev::teststack::Res::exres",
+		Properties: &structpb.Struct{Fields: map[string]*structpb.Value{
+			"name": structpb.NewStringValue("n"),
+		}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, calls, "a Create that returned a resource must not be retried")
+
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+	initErr, ok := details[0].(*pulumirpc.ErrorResourceInitFailed)
+	require.True(t, ok)
+	assert.Equal(t, "r1", initErr.GetId())
+	assert.Contains(t, initErr.GetReasons()[0], "not yet consistent")
+}
@@ -259,6 +259,28 @@ func multiEnvDefault(names []string, dv interface{}) interface{} {
 	return dv
 }
 
+// defaultValueToPropertyValue converts a DefaultInfo.Value into a PropertyValue. Composite values are converted
+// recursively; maps and structs are expected to be keyed by Pulumi property names.
+func defaultValueToPropertyValue(v interface{}) resource.PropertyValue {
+	if pv, ok := v.(resource.PropertyValue); ok {
+		return pv
+	}
+	return resource.NewPropertyValue(v)
+}
+
+// isScalarDefaultValue returns true if v is a bool, number or string that can be used as a default verbatim.
+func isScalarDefaultValue(v interface{}) bool {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
 func getSchema(m shim.SchemaMap, key string) shim.Schema {
 	if m == nil {
 		return nil
@@ -311,10 +333,11 @@ func makeTerraformInputsWithOptions(
 	cdOptions := ComputeDefaultOptions{}
 	if instance != nil {
 		cdOptions = ComputeDefaultOptions{
-			PriorState: olds,
-			Properties: instance.Properties,
-			Seed:       instance.Seed,
-			URN:        instance.URN,
+			PriorState:     olds,
+			Properties:     instance.Properties,
+			Seed:           instance.Seed,
+			URN:            instance.URN,
+			ProviderConfig: config,
 		}
 	}
 
@@ -822,9 +845,30 @@ func (ctx *conversionContext) applyDefaults(
 						}
 					case shim.TypeString:
 						// nothing to do
+					case shim.TypeList, shim.TypeSet, shim.TypeMap:
+						v = nil
+						if str != "" {
+							pv, err := ParseEnvDefault(str, sch, psi)
+							if err != nil {
+								return errors.Wrapf(err, "parsing environment variable default for %q", name)
+							}
+							if v, err = ctx.makeTerraformInput(name, resource.PropertyValue{},
+								pv, tfi, psi, rawNames); err != nil {
+								return err
+							}
+						}
 					default:
 						return errors.Errorf("unknown type for default value: %v", sch.Type())
 					}
+				} else if v != nil && !isScalarDefaultValue(v) {
+					// No variable was set and the fallback Value is a composite, which needs translating into
+					// its Terraform representation.
+					tv, err := ctx.makeTerraformInput(name, resource.PropertyValue{},
+						defaultValueToPropertyValue(v), tfi, psi, rawNames)
+					if err != nil {
+						return err
+					}
+					v = tv
 				}
 				defaultValue, source = v, "env vars"
 			} else if configKey := info.Default.Config; configKey != "" {
@@ -836,7 +880,7 @@ func (ctx *conversionContext) applyDefaults(
 					defaultValue, source = tv, "config"
 				}
 			} else if info.Default.Value != nil {
-				v := resource.NewPropertyValue(info.Default.Value)
+				v := defaultValueToPropertyValue(info.Default.Value)
 				tv, err := ctx.makeTerraformInput(name, resource.PropertyValue{}, v, tfi, psi, rawNames)
 				if err != nil {
 					return err
@@ -1049,6 +1093,7 @@ func MakeTerraformResult(
 	}
 
 	outMap := MakeTerraformOutputs(ctx, p, outs, tfs, ps, assets, false, supportsSecrets)
+	outMap = RemoveWriteOnlyValues(tfs, ps, outMap)
 
 	// If there is any Terraform metadata associated with this state, record it.
 	if state != nil && len(state.Meta()) != 0 {
@@ -1313,7 +1358,10 @@ func makeTerraformStateWithOpts(
 		if err := json.Unmarshal([]byte(metaProperty.StringValue()), &meta); err != nil {
 			return nil, err
 		}
-	} else if res.TF.SchemaVersion() > 0 {
+		// The Pulumi schema version is bridge bookkeeping that Terraform does not need to see.
+		delete(meta, pulumiSchemaVersionKey)
+	}
+	if len(meta) == 0 && res.TF.SchemaVersion() > 0 {
 		// If there was no metadata in the inputs and this resource has a non-zero
 		// schema version, return a meta bag with the current schema version. This
 		// helps avoid migration issues.
@@ -1354,6 +1402,10 @@ func UnmarshalTerraformState(
 		return nil, err
 	}
 
+	props, err = MigrateState(ctx, r.Schema, props)
+	if err != nil {
+		return nil, err
+	}
 	props, err = transformFromState(ctx, r.Schema, props)
 	if err != nil {
 		return nil, err
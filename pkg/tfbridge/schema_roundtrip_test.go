@@ -0,0 +1,174 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+
+	schemav2 "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/require"
+	"pgregory.net/rapid"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/internal/rapidgen"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/propertyvalue"
+)
+
+var roundTripValueOptions = rapidgen.ValueOptions{
+	PulumiName: func(name string, sm shim.SchemaMap) string {
+		return TerraformToPulumiNameV2(name, sm, nil)
+	},
+}
+
+// roundTrip converts inputs to Terraform and back, as Create does with the planned state. Like the provider, it
+// removes secrets before converting. Defaults are not applied, so that the inputs are converted as is.
+func roundTrip(t *rapid.T, sm shim.SchemaMap, inputs resource.PropertyMap) resource.PropertyMap {
+	ctx := context.Background()
+	tfInputs, assets, err := makeTerraformInputsWithOptions(ctx, nil, nil, nil, removeSecrets(inputs), sm, nil,
+		makeTerraformInputsOptions{DisableDefaults: true, DisableTFDefaults: true})
+	require.NoError(t, err)
+	t.Logf("terraform inputs: %#v", tfInputs)
+	return MakeTerraformOutputs(ctx, shimv2.NewProvider(&schemav2.Provider{}), tfInputs, sm, nil, assets,
+		false, true)
+}
+
+func removeSecrets(m resource.PropertyMap) resource.PropertyMap {
+	return propertyvalue.RemoveSecrets(resource.NewObjectProperty(m)).ObjectValue()
+}
+
+func TestRoundTripPulumiToTerraform(t *testing.T) {
+	t.Parallel()
+	rapid.Check(t, func(t *rapid.T) {
+		sm := rapidgen.SchemaMapGen(3).Draw(t, "schema")
+		inputs := rapidgen.PropertyMapGen(sm, roundTripValueOptions).Draw(t, "inputs")
+
+		outputs := roundTrip(t, sm, inputs.PropertyMap)
+
+		require.Equal(t, inputs.PropertyMap, removeSecrets(outputs))
+	})
+}
+
+func TestRoundTripMarksSensitiveSecrets(t *testing.T) {
+	t.Parallel()
+	opts := roundTripValueOptions
+	opts.Secrets = true
+	rapid.Check(t, func(t *rapid.T) {
+		sm := rapidgen.SchemaMapGen(3).Draw(t, "schema")
+		inputs := rapidgen.PropertyMapGen(sm, opts).Draw(t, "inputs")
+
+		outputs := roundTrip(t, sm, inputs.PropertyMap)
+
+		// Secrets do not survive the trip through Terraform, but the values do.
+		require.Equal(t, removeSecrets(inputs.PropertyMap), removeSecrets(outputs))
+		requireSensitiveSecret(t, sm, outputs)
+	})
+}
+
+// requireSensitiveSecret checks that all values of Sensitive properties are secret.
+func requireSensitiveSecret(t *rapid.T, sm shim.SchemaMap, m resource.PropertyMap) {
+	sm.Range(func(k string, s shim.Schema) bool {
+		key := resource.PropertyKey(TerraformToPulumiNameV2(k, sm, nil))
+		v, ok := m[key]
+		if !ok || v.IsNull() {
+			return true
+		}
+		if s.Sensitive() {
+			require.Truef(t, v.IsSecret(), "sensitive property %q is not secret: %v", key, v)
+			return true
+		}
+		block, ok := s.Elem().(shim.Resource)
+		if !ok {
+			return true
+		}
+		switch {
+		case v.IsObject():
+			requireSensitiveSecret(t, block.Schema(), v.ObjectValue())
+		case v.IsArray():
+			for _, e := range v.ArrayValue() {
+				requireSensitiveSecret(t, block.Schema(), e.ObjectValue())
+			}
+		}
+		return true
+	})
+}
+
+func TestRoundTripPropagatesUnknowns(t *testing.T) {
+	t.Parallel()
+	opts := roundTripValueOptions
+	opts.Unknowns = true
+	rapid.Check(t, func(t *rapid.T) {
+		sm := rapidgen.SchemaMapGen(3).Draw(t, "schema")
+		inputs := rapidgen.PropertyMapGen(sm, opts).Draw(t, "inputs")
+
+		outputs := roundTrip(t, sm, inputs.PropertyMap)
+
+		requireUnknownsPropagated(t, sm, inputs.PropertyMap, removeSecrets(outputs))
+	})
+}
+
+func requireUnknownsPropagated(t *rapid.T, sm shim.SchemaMap, ins, outs resource.PropertyMap) {
+	sm.Range(func(k string, s shim.Schema) bool {
+		key := resource.PropertyKey(TerraformToPulumiNameV2(k, sm, nil))
+		if in, ok := ins[key]; ok {
+			requireUnknownPropagated(t, string(key), s, in, outs[key])
+		}
+		return true
+	})
+}
+
+func requireUnknownPropagated(t *rapid.T, path string, s shim.Schema, in, out resource.PropertyValue) {
+	isCollection := s.Type() == shim.TypeList || s.Type() == shim.TypeSet
+	if in.IsComputed() {
+		// Terraform cannot represent unknown lists and sets, so makeTerraformUnknown substitutes a list of
+		// unknown elements. The unknown is then only preserved if those elements have required properties.
+		if !isCollection {
+			require.Truef(t, out.ContainsUnknowns(), "%s: unknown was lost: %v", path, out)
+		}
+		return
+	}
+
+	if isCollection && s.MaxItems() != 1 {
+		require.Lenf(t, out.ArrayValue(), len(in.ArrayValue()), "%s: length changed", path)
+		for i, e := range in.ArrayValue() {
+			requireElemUnknownPropagated(t, path+"[]", s, e, out.ArrayValue()[i])
+		}
+		return
+	}
+	if isCollection || s.Type() == shim.TypeMap {
+		if s.Type() == shim.TypeMap {
+			for k, e := range in.ObjectValue() {
+				requireElemUnknownPropagated(t, path+"."+string(k), s, e, out.ObjectValue()[k])
+			}
+			return
+		}
+		requireElemUnknownPropagated(t, path, s, in, out)
+	}
+}
+
+func requireElemUnknownPropagated(t *rapid.T, path string, s shim.Schema, in, out resource.PropertyValue) {
+	switch elem := s.Elem().(type) {
+	case shim.Schema:
+		requireUnknownPropagated(t, path, elem, in, out)
+	case shim.Resource:
+		if in.IsComputed() {
+			require.Truef(t, out.ContainsUnknowns(), "%s: unknown was lost: %v", path, out)
+			return
+		}
+		requireUnknownsPropagated(t, elem.Schema(), in.ObjectValue(), out.ObjectValue())
+	}
+}
@@ -1192,6 +1192,243 @@ func TestDefaults(t *testing.T) {
 	}
 }
 
+func TestCompositeDefaults(t *testing.T) {
+	t.Parallel()
+
+	type ruleDefault struct {
+		Action   string `pulumi:"action"`
+		Priority int    `pulumi:"priority"`
+	}
+
+	block := (&schema.Resource{
+		Schema: schema.SchemaMap{
+			"action":   (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+			"priority": (&schema.Schema{Type: shim.TypeInt, Optional: true}).Shim(),
+			"note":     (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		},
+	}).Shim()
+
+	tfs := schema.SchemaMap{
+		"zones": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim(),
+		"labels": (&schema.Schema{
+			Type:     shim.TypeMap,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim(),
+		"rules": (&schema.Schema{Type: shim.TypeList, Optional: true, Elem: block}).Shim(),
+		"main_rule": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem:     block,
+		}).Shim(),
+		"env_zones": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim(),
+	}
+	ps := map[string]*SchemaInfo{
+		"zones":  {Default: &DefaultInfo{Value: []string{"a", "b"}}},
+		"labels": {Default: &DefaultInfo{Value: map[string]string{"team": "infra"}}},
+		"rules": {
+			Default: &DefaultInfo{Value: []ruleDefault{{Action: "allow", Priority: 1}}},
+			Elem: &SchemaInfo{Fields: map[string]*SchemaInfo{
+				"note": {Default: &DefaultInfo{Value: "nested"}},
+			}},
+		},
+		"main_rule": {Default: &DefaultInfo{Value: map[string]interface{}{"action": "deny"}}},
+		"env_zones": {Default: &DefaultInfo{
+			Value:   resource.NewArrayProperty([]resource.PropertyValue{resource.NewStringProperty("c")}),
+			EnvVars: []string{"PULUMI_TEST_UNSET_ENV_ZONES"},
+		}},
+	}
+
+	inputs, _, err := makeTerraformInputsForConfig(nil, resource.PropertyMap{}, tfs, ps)
+	require.NoError(t, err)
+
+	assert.Equal(t, []interface{}{"a", "b"}, inputs["zones"])
+	assert.Equal(t, map[string]interface{}{"team": "infra"}, inputs["labels"])
+	// Nested defaults are applied within composite defaults too.
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			defaultsKey: []interface{}{resource.PropertyKey("note")},
+			"action":    "allow",
+			"priority":  1,
+			"note":      "nested",
+		},
+	}, inputs["rules"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{defaultsKey: []interface{}{}, "action": "deny"},
+	}, inputs["main_rule"])
+	assert.Equal(t, []interface{}{"c"}, inputs["env_zones"])
+}
+
+func TestCompositeEnvDefaults(t *testing.T) {
+	block := (&schema.Resource{
+		Schema: schema.SchemaMap{
+			"role_arn":     (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+			"duration":     (&schema.Schema{Type: shim.TypeInt, Optional: true}).Shim(),
+			"session_name": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		},
+	}).Shim()
+
+	tfs := schema.SchemaMap{
+		"zones": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim(),
+		"ports": (&schema.Schema{
+			Type:     shim.TypeSet,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeInt}).Shim(),
+		}).Shim(),
+		"labels": (&schema.Schema{
+			Type:     shim.TypeMap,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim(),
+		"assume_role": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem:     block,
+		}).Shim(),
+		"unset": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim(),
+	}
+	ps := map[string]*SchemaInfo{
+		"zones":       {Default: &DefaultInfo{EnvVars: []string{"PULUMI_TEST_ZONES"}}},
+		"ports":       {Default: &DefaultInfo{EnvVars: []string{"PULUMI_TEST_PORTS"}}},
+		"labels":      {Default: &DefaultInfo{EnvVars: []string{"PULUMI_TEST_LABELS"}}},
+		"assume_role": {Default: &DefaultInfo{EnvVars: []string{"PULUMI_TEST_ASSUME_ROLE"}}},
+		"unset":       {Default: &DefaultInfo{EnvVars: []string{"PULUMI_TEST_UNSET"}}},
+	}
+
+	t.Setenv("PULUMI_TEST_ZONES", "a, b,c")
+	t.Setenv("PULUMI_TEST_PORTS", "[80, 443]")
+	t.Setenv("PULUMI_TEST_LABELS", `{"team": "infra"}`)
+	t.Setenv("PULUMI_TEST_ASSUME_ROLE", `{"role_arn": "arn:role", "duration": 900, "sessionName": "ci"}`)
+
+	inputs, _, err := makeTerraformInputsForConfig(nil, resource.PropertyMap{}, tfs, ps)
+	require.NoError(t, err)
+
+	assert.Equal(t, []interface{}{"a", "b", "c"}, inputs["zones"])
+	assert.Equal(t, []interface{}{80, 443}, inputs["ports"])
+	assert.Equal(t, map[string]interface{}{"team": "infra"}, inputs["labels"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			defaultsKey:    []interface{}{},
+			"role_arn":     "arn:role",
+			"duration":     900,
+			"session_name": "ci",
+		},
+	}, inputs["assume_role"])
+	assert.NotContains(t, inputs, "unset")
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Setenv("PULUMI_TEST_LABELS", "team=infra")
+		_, _, err := makeTerraformInputsForConfig(nil, resource.PropertyMap{}, tfs, ps)
+		assert.ErrorContains(t, err, `parsing environment variable default for "labels": expected a JSON object`)
+	})
+}
+
+func TestParseEnvDefault(t *testing.T) {
+	t.Parallel()
+
+	block := (&schema.Resource{
+		Schema: schema.SchemaMap{
+			"name":    (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+			"enabled": (&schema.Schema{Type: shim.TypeBool, Optional: true}).Shim(),
+		},
+	}).Shim()
+	rules := (&schema.Schema{Type: shim.TypeList, Optional: true, Elem: block}).Shim()
+
+	tests := []struct {
+		name     string
+		str      string
+		sch      shim.Schema
+		info     *SchemaInfo
+		expected resource.PropertyValue
+		err      string
+	}{
+		{
+			name:     "int",
+			str:      "0x10",
+			sch:      (&schema.Schema{Type: shim.TypeInt}).Shim(),
+			expected: resource.NewNumberProperty(16),
+		},
+		{
+			name: "list of blocks",
+			str:  `[{"name": "a", "enabled": true}, {"name": "b"}]`,
+			sch:  rules,
+			expected: resource.NewArrayProperty([]resource.PropertyValue{
+				resource.NewObjectProperty(resource.PropertyMap{
+					"name":    resource.NewStringProperty("a"),
+					"enabled": resource.NewBoolProperty(true),
+				}),
+				resource.NewObjectProperty(resource.PropertyMap{
+					"name": resource.NewStringProperty("b"),
+				}),
+			}),
+		},
+		{
+			name: "renamed block field",
+			str:  `[{"ruleName": "a"}]`,
+			sch:  rules,
+			info: &SchemaInfo{Elem: &SchemaInfo{Fields: map[string]*SchemaInfo{"name": {Name: "ruleName"}}}},
+			expected: resource.NewArrayProperty([]resource.PropertyValue{
+				resource.NewObjectProperty(resource.PropertyMap{
+					"ruleName": resource.NewStringProperty("a"),
+				}),
+			}),
+		},
+		{
+			name: "comma-separated blocks",
+			str:  "a,b",
+			sch:  rules,
+			err:  "expected a JSON object",
+		},
+		{
+			name: "unknown block field",
+			str:  `[{"other": "a"}]`,
+			sch:  rules,
+			err:  `[0]: unknown property "other"`,
+		},
+		{
+			name: "fractional int",
+			str:  "[1.5]",
+			sch: (&schema.Schema{
+				Type: shim.TypeList,
+				Elem: (&schema.Schema{Type: shim.TypeInt}).Shim(),
+			}).Shim(),
+			err: "[0]: expected an integer, got 1.5",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			actual, err := ParseEnvDefault(tt.str, tt.sch, tt.info)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
 func TestDefaultsConflictsWith(t *testing.T) {
 	ctx := context.Background()
 	for _, f := range factories {
@@ -3007,3 +3244,24 @@ func TestRegress940(t *testing.T) {
 		assert.Equal(t, "foo_bar_value", result["build"].([]any)[0].(map[string]any)["build_arg"].(map[string]any)["fooBar"])
 	})
 }
+
+func TestMakeTerraformInputsAutonamingConfig(t *testing.T) {
+	t.Parallel()
+	tfs := schema.SchemaMap{
+		"name": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+	}
+	ps := map[string]*SchemaInfo{"name": AutoName("name", 64, "-")}
+	instance := &PulumiResource{URN: "urn:pulumi:dev::proj::pkg:index:Res::n1", Seed: []byte("seed")}
+
+	inputs, _, err := MakeTerraformInputs(context.Background(), instance, resource.PropertyMap{
+		AutonamingConfigKey: resource.NewStringProperty(`{"pattern": "${stack}-${name}"}`),
+	}, nil, resource.PropertyMap{}, tfs, ps)
+	require.NoError(t, err)
+	assert.Equal(t, "dev-n1", inputs["name"])
+
+	inputs, _, err = MakeTerraformInputs(context.Background(), instance, resource.PropertyMap{
+		AutonamingConfigKey: resource.NewStringProperty(`{"mode": "disabled"}`),
+	}, nil, resource.PropertyMap{}, tfs, ps)
+	require.NoError(t, err)
+	assert.NotContains(t, inputs, "name")
+}
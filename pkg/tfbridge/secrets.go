@@ -16,6 +16,7 @@ package tfbridge
 
 import (
 	"context"
+	"sort"
 
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -24,6 +25,7 @@ import (
 // Ensures resource.MakeSecret is used to wrap any nested values that correspond to secret properties in the schema. A
 // property is considered secret if it is declared Sensitive in the SchemaMap in the upstream provider. Users may also
 // override a matching SchemaInfo.Secret setting to force a property to be considered secret or non-secret.
+// Write-only properties, see [IsWriteOnly], are secret unless SchemaInfo.Secret says otherwise.
 func MarkSchemaSecrets(ctx context.Context, schemaMap shim.SchemaMap, configInfos map[string]*SchemaInfo,
 	pv resource.PropertyValue) resource.PropertyValue {
 	ss := &schemaSecrets{schemaMap, configInfos}
@@ -44,6 +46,8 @@ func (ss *schemaSecrets) shouldBeSecret(path resource.PropertyPath) bool {
 	secret := false
 	if info != nil && info.Secret != nil {
 		secret = *info.Secret
+	} else if IsWriteOnly(s, info) {
+		secret = true
 	} else if s != nil {
 		secret = s.Sensitive()
 	}
@@ -97,3 +101,34 @@ func (ss *schemaSecrets) markSchemaSecretsTransform(
 
 	return value
 }
+
+// SecretConfigKeys returns the Pulumi names of the provider configuration keys whose values are secret, following the
+// rules of MarkSchemaSecrets. This includes the keys of ExtraConfig that are Sensitive or marked SchemaInfo.Secret.
+func SecretConfigKeys(info *ProviderInfo) []string {
+	var keys []string
+	if info.P != nil {
+		schemaMap := info.P.Schema()
+		ss := &schemaSecrets{schemaMap, info.Config}
+		schemaMap.Range(func(tfName string, _ shim.Schema) bool {
+			key := TerraformToPulumiNameV2(tfName, schemaMap, info.Config)
+			if ss.shouldBeSecret(resource.PropertyPath{key}) {
+				keys = append(keys, key)
+			}
+			return true
+		})
+	}
+	for key, extra := range info.ExtraConfig {
+		if extra == nil {
+			continue
+		}
+		secret := extra.Schema != nil && extra.Schema.Sensitive()
+		if extra.Info != nil && extra.Info.Secret != nil {
+			secret = *extra.Info.Secret
+		}
+		if secret {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
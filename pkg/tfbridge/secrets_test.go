@@ -124,3 +124,27 @@ func TestMarkSchemaSecrets(t *testing.T) {
 		})
 	}
 }
+
+func TestSecretConfigKeys(t *testing.T) {
+	t.Parallel()
+	p := (&schema.Provider{Schema: schema.SchemaMap{
+		"access_key": (&schema.Schema{Type: shim.TypeString, Optional: true, Sensitive: true}).Shim(),
+		"region":     (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"token":      (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"endpoint":   (&schema.Schema{Type: shim.TypeString, Optional: true, Sensitive: true}).Shim(),
+	}}).Shim()
+
+	info := &ProviderInfo{
+		P: p,
+		Config: map[string]*SchemaInfo{
+			"token":    {Secret: True()},
+			"endpoint": {Secret: False()},
+		},
+		ExtraConfig: map[string]*ConfigInfo{
+			"assumeRoleArn": {Info: &SchemaInfo{Secret: True()}},
+			"profile":       {Schema: (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim()},
+		},
+	}
+
+	assert.Equal(t, []string{"accessKey", "assumeRoleArn", "token"}, SecretConfigKeys(info))
+}
@@ -0,0 +1,152 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// pulumiSchemaVersionKey is the key in the __meta block of a resource state that records how many of the
+// [ResourceInfo.StateMigrations] the state has been through. It is private to the bridge and never reaches
+// Terraform.
+const pulumiSchemaVersionKey = "pulumi_schema_version"
+
+// StateMigration is a step in the evolution of the Pulumi state of a resource. See [ResourceInfo.StateMigrations].
+type StateMigration struct {
+	// A short description of the migration, used in error messages.
+	Description string
+
+	// Migrate rewrites the state of a resource written by a provider version that predates this migration. The
+	// state is in Pulumi form, with Pulumi property names, secrets and the __meta block.
+	Migrate PropertyTransform
+}
+
+// MigrateState brings the Pulumi state of a resource up to date with res.StateMigrations.
+//
+// The migrations the state needs are those past the Pulumi schema version recorded in its __meta block; states
+// without a recorded version need all of them. The returned state records the current Pulumi schema version.
+//
+// Providers call MigrateState before passing the state to Terraform, and so before Terraform state upgrades run.
+func MigrateState(
+	ctx context.Context, res *ResourceInfo, state resource.PropertyMap,
+) (resource.PropertyMap, error) {
+	if res == nil || len(res.StateMigrations) == 0 || state == nil {
+		return state, nil
+	}
+	version, err := stateVersion(res, state)
+	if err != nil {
+		return nil, err
+	}
+	state, err = migrate(ctx, res, version, state)
+	if err != nil {
+		return nil, err
+	}
+	return SetStateVersion(res, state)
+}
+
+// MigrateInputs applies to the prior inputs of a resource the migrations that MigrateState applies to its state.
+// state must not have been migrated yet.
+//
+// Inputs have no __meta block, so the Pulumi schema version of the state decides which migrations run. Prior inputs
+// that come without a state, as in Check, cannot be migrated.
+func MigrateInputs(
+	ctx context.Context, res *ResourceInfo, state, inputs resource.PropertyMap,
+) (resource.PropertyMap, error) {
+	if res == nil || len(res.StateMigrations) == 0 || state == nil || len(inputs) == 0 {
+		return inputs, nil
+	}
+	version, err := stateVersion(res, state)
+	if err != nil {
+		return nil, err
+	}
+	return migrate(ctx, res, version, inputs)
+}
+
+// stateVersion returns the Pulumi schema version recorded in state.
+func stateVersion(res *ResourceInfo, state resource.PropertyMap) (int, error) {
+	meta, err := parseMetaBlock(state)
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	if v, ok := meta[pulumiSchemaVersionKey].(string); ok {
+		version, err = strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("expected %s.%s to be an integer, got %q: %w",
+				metaKey, pulumiSchemaVersionKey, v, err)
+		}
+	}
+	if version > len(res.StateMigrations) {
+		return 0, fmt.Errorf("resource state has Pulumi schema version %d, but the provider only supports "+
+			"versions up to %d: the state was written by a newer version of the provider", version,
+			len(res.StateMigrations))
+	}
+	return version, nil
+}
+
+// migrate applies the migrations past version to m.
+func migrate(
+	ctx context.Context, res *ResourceInfo, version int, m resource.PropertyMap,
+) (resource.PropertyMap, error) {
+	for i, step := range res.StateMigrations[version:] {
+		var err error
+		m, err = step.Migrate(ctx, m.Copy())
+		if err != nil {
+			return nil, fmt.Errorf("migrating state to Pulumi schema version %d (%s): %w",
+				version+i+1, step.Description, err)
+		}
+	}
+	return m, nil
+}
+
+// SetStateVersion records in state that it is up to date with res.StateMigrations, so that later reads of the state
+// do not migrate it again. Providers call SetStateVersion on the outputs of Create, Read and Update.
+func SetStateVersion(res *ResourceInfo, state resource.PropertyMap) (resource.PropertyMap, error) {
+	if res == nil || len(res.StateMigrations) == 0 || state == nil {
+		return state, nil
+	}
+	meta, err := parseMetaBlock(state)
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		meta = map[string]interface{}{}
+	}
+	meta[pulumiSchemaVersionKey] = strconv.Itoa(len(res.StateMigrations))
+	metaJSON, err := json.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	state = state.Copy()
+	state[metaKey] = resource.NewStringProperty(string(metaJSON))
+	return state, nil
+}
+
+func parseMetaBlock(state resource.PropertyMap) (map[string]interface{}, error) {
+	var meta map[string]interface{}
+	if metaProperty, hasMeta := state[metaKey]; hasMeta && metaProperty.IsString() {
+		if err := json.Unmarshal([]byte(metaProperty.StringValue()), &meta); err != nil {
+			return nil, fmt.Errorf("expected %q special property to be a JSON-marshalled string: %w",
+				metaKey, err)
+		}
+	}
+	return meta, nil
+}
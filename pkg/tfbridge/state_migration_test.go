@@ -0,0 +1,310 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	testutils "github.com/pulumi/providertest/replay"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/internal/testprovider"
+	shimv2 "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2"
+)
+
+// appendMigration returns a migration that appends s to the "log" property.
+func appendMigration(s string) StateMigration {
+	return StateMigration{
+		Description: "append " + s,
+		Migrate: func(_ context.Context, pm resource.PropertyMap) (resource.PropertyMap, error) {
+			log := ""
+			if v, ok := pm["log"]; ok {
+				log = v.StringValue()
+			}
+			pm["log"] = resource.NewStringProperty(log + s)
+			return pm, nil
+		},
+	}
+}
+
+func TestMigrateState(t *testing.T) {
+	t.Parallel()
+	res := &ResourceInfo{StateMigrations: []StateMigration{
+		appendMigration("1"), appendMigration("2"), appendMigration("3"),
+	}}
+
+	tests := []struct {
+		name      string
+		state     resource.PropertyMap
+		expected  resource.PropertyMap
+		expectErr string
+	}{
+		{
+			name:  "no version",
+			state: resource.PropertyMap{},
+			expected: resource.PropertyMap{
+				"log":   resource.NewStringProperty("123"),
+				metaKey: resource.NewStringProperty(`{"pulumi_schema_version":"3"}`),
+			},
+		},
+		{
+			name: "partially migrated",
+			state: resource.PropertyMap{
+				metaKey: resource.NewStringProperty(`{"pulumi_schema_version":"1","schema_version":"2"}`),
+			},
+			expected: resource.PropertyMap{
+				"log":   resource.NewStringProperty("23"),
+				metaKey: resource.NewStringProperty(`{"pulumi_schema_version":"3","schema_version":"2"}`),
+			},
+		},
+		{
+			name: "up to date",
+			state: resource.PropertyMap{
+				metaKey: resource.NewStringProperty(`{"pulumi_schema_version":"3"}`),
+			},
+			expected: resource.PropertyMap{
+				metaKey: resource.NewStringProperty(`{"pulumi_schema_version":"3"}`),
+			},
+		},
+		{
+			name: "newer version",
+			state: resource.PropertyMap{
+				metaKey: resource.NewStringProperty(`{"pulumi_schema_version":"4"}`),
+			},
+			expectErr: "written by a newer version of the provider",
+		},
+		{
+			name: "malformed version",
+			state: resource.PropertyMap{
+				metaKey: resource.NewStringProperty(`{"pulumi_schema_version":"x"}`),
+			},
+			expectErr: "to be an integer",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			actual, err := MigrateState(context.Background(), res, tt.state)
+			if tt.expectErr != "" {
+				assert.ErrorContains(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestMigrateStateReportsFailingStep(t *testing.T) {
+	t.Parallel()
+	res := &ResourceInfo{StateMigrations: []StateMigration{
+		appendMigration("1"),
+		{
+			Description: "rename foo",
+			Migrate: func(context.Context, resource.PropertyMap) (resource.PropertyMap, error) {
+				return nil, fmt.Errorf("boom")
+			},
+		},
+	}}
+
+	_, err := MigrateState(context.Background(), res, resource.PropertyMap{})
+	assert.EqualError(t, err, "migrating state to Pulumi schema version 2 (rename foo): boom")
+}
+
+func TestMigrateInputs(t *testing.T) {
+	t.Parallel()
+	res := &ResourceInfo{StateMigrations: []StateMigration{
+		appendMigration("1"), appendMigration("2"), appendMigration("3"),
+	}}
+	state := resource.PropertyMap{
+		"__meta": resource.NewStringProperty(`{"pulumi_schema_version":"1"}`),
+	}
+
+	actual, err := MigrateInputs(context.Background(), res, state, resource.PropertyMap{
+		"log": resource.NewStringProperty("0"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, resource.PropertyMap{"log": resource.NewStringProperty("023")}, actual,
+		"inputs go through the migrations of the state and get no __meta block")
+
+	actual, err = MigrateInputs(context.Background(), res, state, resource.PropertyMap{})
+	require.NoError(t, err)
+	assert.Equal(t, resource.PropertyMap{}, actual, "empty inputs are not migrated")
+}
+
+func TestStateMigrationsProvider(t *testing.T) {
+	provider := func(t *testing.T) *Provider {
+		p := testprovider.AssertProvider(func(data *schema.ResourceData) {
+			// GetRawState is not available during deletes.
+			if raw := data.GetRawState(); !raw.IsNull() {
+				assert.Equal(t, "MIGRATED", raw.AsValueMap()["string_property_value"].AsString())
+			}
+			testprovider.MustSet(data, "string_property_value", "SET")
+		})
+		return &Provider{
+			tf:     shimv2.NewProvider(p),
+			config: shimv2.NewSchemaMap(p.Schema),
+			resources: map[tokens.Type]Resource{
+				"Echo": {
+					TF:     shimv2.NewResource(p.ResourcesMap["echo"]),
+					TFName: "echo",
+					Schema: &ResourceInfo{
+						Tok: "Echo",
+						StateMigrations: []StateMigration{{
+							Description: "rename oldStringPropertyValue",
+							Migrate: func(
+								_ context.Context, pm resource.PropertyMap,
+							) (resource.PropertyMap, error) {
+								if _, ok := pm["oldStringPropertyValue"]; !ok {
+									return nil, fmt.Errorf("already migrated")
+								}
+								pm["stringPropertyValue"] = pm["oldStringPropertyValue"]
+								delete(pm, "oldStringPropertyValue")
+								return pm, nil
+							},
+						}},
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("Read (Refresh)", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "properties": {
+		      "oldStringPropertyValue": "MIGRATED"
+		    }
+		  },
+		  "response": {
+		    "id": "0",
+		    "inputs": "*",
+		    "properties": {
+		      "id": "*",
+		      "stringPropertyValue": "SET",
+		      "__meta": "{\"pulumi_schema_version\":\"1\",\"schema_version\":\"1\"}"
+		    }
+		  }
+		}`)
+	})
+
+	t.Run("Read (Refresh) migrates the prior inputs", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "properties": {
+		      "oldStringPropertyValue": "MIGRATED"
+		    },
+		    "inputs": {
+		      "oldStringPropertyValue": "MIGRATED"
+		    }
+		  },
+		  "response": {
+		    "id": "0",
+		    "inputs": {
+		      "stringPropertyValue": "SET"
+		    },
+		    "properties": "*"
+		  }
+		}`)
+	})
+
+	t.Run("Check does not migrate the prior inputs", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Check",
+		  "request": {
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "olds": {
+		      "__defaults": [],
+		      "stringPropertyValue": "OLD"
+		    },
+		    "news": {
+		      "stringPropertyValue": "NEW"
+		    },
+		    "randomSeed": "wqZZaHWVfsS1ozo3bdauTfZmjslvWcZpUjn7BzpS79c="
+		  },
+		  "response": {
+		    "inputs": {
+		      "__defaults": [],
+		      "stringPropertyValue": "NEW"
+		    }
+		  }
+		}`)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Update",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "olds": {
+		      "oldStringPropertyValue": "MIGRATED"
+		    },
+		    "news": {
+		      "stringPropertyValue": "NEW"
+		    }
+		  },
+		  "response": {
+		    "properties": {
+		      "id": "*",
+		      "stringPropertyValue": "SET",
+		      "__meta": "{\"e2bfb730-ecaa-11e6-8f88-34363bc7c4c0\":{\"create\":120000000000},\"pulumi_schema_version\":\"1\",\"schema_version\":\"1\"}"
+		    }
+		  }
+		}`)
+	})
+
+	t.Run("Migrated state is not migrated again", func(t *testing.T) {
+		testutils.Replay(t, provider(t), `
+		{
+		  "method": "/pulumirpc.ResourceProvider/Read",
+		  "request": {
+		    "id": "0",
+		    "urn": "urn:pulumi:dev::teststack::Echo::exres",
+		    "properties": {
+		      "stringPropertyValue": "MIGRATED",
+		      "__meta": "{\"pulumi_schema_version\":\"1\"}"
+		    }
+		  },
+		  "response": {
+		    "id": "0",
+		    "inputs": "*",
+		    "properties": {
+		      "id": "*",
+		      "stringPropertyValue": "SET",
+		      "__meta": "{\"pulumi_schema_version\":\"1\",\"schema_version\":\"1\"}"
+		    }
+		  }
+		}`)
+	})
+}
@@ -23,8 +23,13 @@ import (
 
 	b "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	md "github.com/pulumi/pulumi-terraform-bridge/v3/unstable/metadata"
 )
 
+// The key under which InferredModules persists the module of each item in the provider
+// metadata.
+const inferredModulesMetadataKey = "inferred-modules"
+
 type InferredModulesOpts struct {
 	// The TF prefix of the package.
 	TfPkgPrefix string
@@ -53,6 +58,16 @@ type InferredModulesOpts struct {
 	// = 0 -> apply the default value.
 	// > 0 -> set the value.
 	MimimumSubmoduleSize int
+	// Recompute the module of every item, discarding the modules assigned by previous runs.
+	//
+	// When info.MetadataInfo is set, the module assigned to each item is persisted in the
+	// provider metadata (bridge-metadata.json) and kept on later runs, so that adding items
+	// upstream cannot move existing items to a different module. Only new items are placed
+	// by the global analysis.
+	//
+	// Items that move when Rebin is set are aliased to their previous token by
+	// [b.ProviderInfo.ApplyAutoAliases], which should be called after the tokens are computed.
+	Rebin bool
 }
 
 // A strategy to infer module placement from global analysis of all items (Resources & DataSources).
@@ -78,6 +93,11 @@ func InferredModules(
 	}
 
 	tokenMap := opts.computeTokens(info)
+	if info.MetadataInfo != nil {
+		if err := pinModules(info.GetMetadata(), tokenMap, opts.Rebin); err != nil {
+			return b.Strategy{}, err
+		}
+	}
 
 	rIsEmpty := func(r *b.ResourceInfo) bool { return r.Tok == "" }
 	dIsEmpty := func(r *b.DataSourceInfo) bool { return r.Tok == "" }
@@ -351,6 +371,35 @@ func sharedPrefix(s1, s2 string) string {
 
 type tokenInfo struct{ mod, name string }
 
+// The persisted form of a tokenInfo.
+type pinnedToken struct {
+	Module string `json:"module"`
+	Name   string `json:"name"`
+}
+
+// Replace the computed placement of each item in tokenMap with the placement persisted in
+// metadata, unless rebin is set, then persist the resulting placements.
+//
+// Items no longer present in tokenMap are dropped from metadata.
+func pinModules(metadata b.ProviderMetadata, tokenMap map[string]tokenInfo, rebin bool) error {
+	pinned, _, err := md.Get[map[string]pinnedToken](metadata, inferredModulesMetadataKey)
+	if err != nil {
+		return fmt.Errorf("reading inferred modules: %w", err)
+	}
+	updated := make(map[string]pinnedToken, len(tokenMap))
+	for tfToken, info := range tokenMap {
+		if p, ok := pinned[tfToken]; ok && !rebin {
+			info = tokenInfo{mod: p.Module, name: p.Name}
+			tokenMap[tfToken] = info
+		}
+		updated[tfToken] = pinnedToken{Module: info.mod, Name: info.name}
+	}
+	// Set fails only when the value is not serializable, which is impossible for updated.
+	err = md.Set(metadata, inferredModulesMetadataKey, updated)
+	contract.AssertNoErrorf(err, "Inferred modules failed to serialize")
+	return nil
+}
+
 func tokenFromMap[T b.ResourceInfo | b.DataSourceInfo](
 	tokenMap map[string]tokenInfo, isEmpty func(*T) bool,
 	finalize Make, apply func(tk string, elem *T),
@@ -467,6 +467,73 @@ func TestTokensInferredModules(t *testing.T) {
 	}
 }
 
+func TestTokensInferredModulesPinned(t *testing.T) {
+	metadata, autoAliasing := makeAutoAliasing(t)
+	opts := &tokens.InferredModulesOpts{TfPkgPrefix: "pkg_", MinimumModuleSize: 3}
+
+	provider := func(names ...string) *tfbridge.ProviderInfo {
+		resources := schema.ResourceMap{}
+		for _, name := range names {
+			resources[name] = nil
+		}
+		info := &tfbridge.ProviderInfo{
+			P:            (&schema.Provider{ResourcesMap: resources}).Shim(),
+			Version:      "1.0.0",
+			MetadataInfo: &tfbridge.MetadataInfo{Data: metadata, Path: "must be non-empty"},
+		}
+		strategy, err := tokens.InferredModules(info, tokens.MakeStandard("pkg"), opts)
+		require.NoError(t, err)
+		require.NoError(t, info.ComputeTokens(strategy))
+		autoAliasing(info, metadata)
+		return info
+	}
+	toks := func(info *tfbridge.ProviderInfo) map[string]string {
+		m := map[string]string{}
+		for k, v := range info.Resources {
+			m[k] = v.Tok.String()
+		}
+		return m
+	}
+
+	v1 := provider("pkg_hello_world", "pkg_hello_pulumi", "pkg_hi")
+	assert.Equal(t, map[string]string{
+		"pkg_hello_world":  "pkg:index/helloWorld:HelloWorld",
+		"pkg_hello_pulumi": "pkg:index/helloPulumi:HelloPulumi",
+		"pkg_hi":           "pkg:index/hi:Hi",
+	}, toks(v1))
+
+	// pkg_hello would make hello a module, but existing items keep their module.
+	v2 := provider("pkg_hello_world", "pkg_hello_pulumi", "pkg_hi", "pkg_hello")
+	assert.Equal(t, map[string]string{
+		"pkg_hello_world":  "pkg:index/helloWorld:HelloWorld",
+		"pkg_hello_pulumi": "pkg:index/helloPulumi:HelloPulumi",
+		"pkg_hi":           "pkg:index/hi:Hi",
+		"pkg_hello":        "pkg:hello/hello:Hello",
+	}, toks(v2))
+	for _, r := range v2.Resources {
+		assert.Empty(t, r.Aliases)
+	}
+
+	// The runtime metadata must carry the assignments for the provider to compute the same tokens.
+	runtime := v2.MetadataInfo.ExtractRuntimeMetadata()
+	_, ok, err := md.Get[map[string]any](runtime.Data, "inferred-modules")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// A forced rebin moves existing items, aliasing them to their previous tokens.
+	opts.Rebin = true
+	v3 := provider("pkg_hello_world", "pkg_hello_pulumi", "pkg_hi", "pkg_hello")
+	assert.Equal(t, "pkg:hello/world:World", v3.Resources["pkg_hello_world"].Tok.String())
+	ref := func(s string) *string { return &s }
+	assert.Equal(t, []tfbridge.AliasInfo{{Type: ref("pkg:index/helloWorld:HelloWorld")}},
+		v3.Resources["pkg_hello_world"].Aliases)
+
+	// The new assignments are pinned from then on.
+	opts.Rebin = false
+	v4 := provider("pkg_hello_world", "pkg_hello_pulumi", "pkg_hi", "pkg_hello")
+	assert.Equal(t, "pkg:hello/world:World", v4.Resources["pkg_hello_world"].Tok.String())
+}
+
 func makeAutoAliasing(t *testing.T) (
 	*md.Data, func(*tfbridge.ProviderInfo, tfbridge.ProviderMetadata),
 ) {
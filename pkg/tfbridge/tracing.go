@@ -0,0 +1,68 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"os"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/propertyvalue"
+)
+
+// TracePayloadsEnvVar opts in to attaching payloads such as provider configuration to tracing spans when set to a
+// truthy value. Payloads are not traced by default, and secret values are redacted from traced payloads.
+const TracePayloadsEnvVar = "PULUMI_TF_BRIDGE_TRACE_PAYLOADS"
+
+// The value substituted for secrets in traced payloads.
+const redactedTraceValue = "[secret]"
+
+// TracePayloads reports whether payloads may be attached to tracing spans, see [TracePayloadsEnvVar].
+func TracePayloads() bool {
+	return cmdutil.IsTruthy(os.Getenv(TracePayloadsEnvVar))
+}
+
+// SanitizeTracePayload renders pm for a tracing span with secret values redacted.
+//
+// Values are considered secret if they are marked as secrets, or if the schema marks them Sensitive or
+// [SchemaInfo.Secret] is set, as decided by [MarkSchemaSecrets].
+func SanitizeTracePayload(
+	ctx context.Context, schemaMap shim.SchemaMap, schemaInfos map[string]*SchemaInfo, pm resource.PropertyMap,
+) string {
+	v := MarkSchemaSecrets(ctx, schemaMap, schemaInfos, resource.NewObjectProperty(pm))
+	v = propertyvalue.Transform(func(v resource.PropertyValue) resource.PropertyValue {
+		if v.IsSecret() || (v.IsOutput() && v.OutputValue().Secret) {
+			return resource.NewStringProperty(redactedTraceValue)
+		}
+		return v
+	}, v)
+	return v.String()
+}
+
+// TracePayloadTag is a span option tagging the span with the payload pm under key, sanitized by
+// [SanitizeTracePayload]. It does nothing unless [TracePayloads] opts in.
+func TracePayloadTag(
+	ctx context.Context, key string,
+	schemaMap shim.SchemaMap, schemaInfos map[string]*SchemaInfo, pm resource.PropertyMap,
+) opentracing.StartSpanOption {
+	if !TracePayloads() {
+		return opentracing.Tags{}
+	}
+	return opentracing.Tag{Key: key, Value: SanitizeTracePayload(ctx, schemaMap, schemaInfos, pm)}
+}
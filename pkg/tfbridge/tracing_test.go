@@ -0,0 +1,81 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func TestSanitizeTracePayload(t *testing.T) {
+	t.Parallel()
+	schemaMap := schema.SchemaMap{
+		"region":     (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"access_key": (&schema.Schema{Type: shim.TypeString, Optional: true, Sensitive: true}).Shim(),
+		"token":      (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"endpoints": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim(),
+	}
+	infos := map[string]*SchemaInfo{"token": {Secret: True()}}
+
+	payload := SanitizeTracePayload(context.Background(), schemaMap, infos, resource.PropertyMap{
+		"region":    resource.NewStringProperty("us-west-2"),
+		"accessKey": resource.NewStringProperty("AKIA-sensitive"),
+		"token":     resource.NewStringProperty("info-secret"),
+		"endpoints": resource.NewArrayProperty([]resource.PropertyValue{
+			resource.MakeSecret(resource.NewStringProperty("marked-secret")),
+			resource.NewOutputProperty(resource.Output{
+				Element: resource.NewStringProperty("output-secret"),
+				Known:   true,
+				Secret:  true,
+			}),
+		}),
+	})
+
+	assert.Contains(t, payload, "us-west-2")
+	assert.Contains(t, payload, redactedTraceValue)
+	for _, secret := range []string{"AKIA-sensitive", "info-secret", "marked-secret", "output-secret"} {
+		assert.NotContains(t, payload, secret)
+	}
+}
+
+func TestTracePayloadTag(t *testing.T) {
+	schemaMap := schema.SchemaMap{
+		"password": (&schema.Schema{Type: shim.TypeString, Optional: true, Sensitive: true}).Shim(),
+	}
+	config := resource.PropertyMap{"password": resource.NewStringProperty("hunter2")}
+	ctx := context.Background()
+
+	tracer := mocktracer.New()
+	tracer.StartSpan("untraced", TracePayloadTag(ctx, "inputs", schemaMap, nil, config)).Finish()
+
+	t.Setenv(TracePayloadsEnvVar, "true")
+	tracer.StartSpan("traced", TracePayloadTag(ctx, "inputs", schemaMap, nil, config)).Finish()
+
+	spans := tracer.FinishedSpans()
+	assert.Len(t, spans, 2)
+	assert.NotContains(t, spans[0].Tags(), "inputs")
+	assert.Equal(t, `{map[password:{[secret]}]}`, spans[1].Tag("inputs"))
+}
@@ -0,0 +1,232 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/propertyvalue"
+)
+
+// IsWriteOnly reports whether a property is write-only: either SchemaInfo.WriteOnly is set, or the schema declares
+// the attribute write-only, see [shim.SchemaWithWriteOnly].
+func IsWriteOnly(s shim.Schema, info *SchemaInfo) bool {
+	if info != nil && info.WriteOnly {
+		return true
+	}
+	w, ok := s.(shim.SchemaWithWriteOnly)
+	return ok && w.WriteOnly()
+}
+
+// Removes the values of write-only properties from a resource's inputs or outputs so that they are never persisted
+// to Pulumi state, see [IsWriteOnly].
+func RemoveWriteOnlyValues(schemaMap shim.SchemaMap, schemaInfos map[string]*SchemaInfo,
+	props resource.PropertyMap) resource.PropertyMap {
+	if !hasWriteOnlyFields(schemaMap, schemaInfos) {
+		return props
+	}
+	wo := &writeOnlyFields{schemaMap, schemaInfos}
+	result, err := propertyvalue.TransformPropertyValue(make(resource.PropertyPath, 0),
+		func(path resource.PropertyPath, v resource.PropertyValue) (resource.PropertyValue, error) {
+			if !v.IsObject() {
+				return v, nil
+			}
+			obj := v.ObjectValue()
+			for k := range obj {
+				if wo.isWriteOnly(append(append(resource.PropertyPath{}, path...), string(k))) {
+					delete(obj, k)
+				}
+			}
+			return v, nil
+		}, resource.NewObjectProperty(props))
+	contract.AssertNoErrorf(err, "TransformPropertyValue should not fail")
+	return result.ObjectValue()
+}
+
+// Copies the values of write-only properties from news into olds, returning the updated copy of olds.
+//
+// Write-only values are never stored in state, so without this step every diff would see them as newly added. The
+// prior value is instead assumed to equal the new one, which matches Terraform: changing only the value of a
+// write-only property never triggers an update. Unknown values are not copied.
+func CopyWriteOnlyValues(schemaMap shim.SchemaMap, schemaInfos map[string]*SchemaInfo,
+	news, olds resource.PropertyMap) resource.PropertyMap {
+	if !hasWriteOnlyFields(schemaMap, schemaInfos) {
+		return olds
+	}
+	wo := &writeOnlyFields{schemaMap, schemaInfos}
+
+	var paths []resource.PropertyPath
+	_, err := propertyvalue.TransformPropertyValue(make(resource.PropertyPath, 0),
+		func(path resource.PropertyPath, v resource.PropertyValue) (resource.PropertyValue, error) {
+			if len(path) > 0 && !v.ContainsUnknowns() && wo.isWriteOnly(path) {
+				paths = append(paths, path)
+			}
+			return v, nil
+		}, resource.NewObjectProperty(news))
+	contract.AssertNoErrorf(err, "TransformPropertyValue should not fail")
+
+	// Transform rebuilds every object and array, so result can be updated in place without affecting olds.
+	result := propertyvalue.Transform(func(v resource.PropertyValue) resource.PropertyValue { return v },
+		resource.NewObjectProperty(olds))
+	for _, path := range paths {
+		v, _ := path.Get(resource.NewObjectProperty(news))
+		// Set fails if the parent of path is absent from olds, in which case there is nothing to compare against.
+		path.Set(result, v)
+	}
+	return result.ObjectValue()
+}
+
+// WriteOnlyInputs holds the write-only values of resources from Check until they are sent to the provider.
+//
+// The engine records the inputs returned by Check in the state, and passes exactly those inputs to Create and
+// Update. Check therefore removes write-only values from the inputs it returns and keeps them here, in the memory of
+// the provider process, keyed by the URN of the resource. Create and Update restore them before calling the provider.
+//
+// Every path after Check drops the values: Create and Update always do, and Diff does when it fails or reports no
+// changes, since neither Create nor Update follow then. Create and Update fail if the values are missing, which
+// happens when Check ran in a different provider process, instead of silently sending no value to the provider.
+//
+// The zero value is ready to use.
+type WriteOnlyInputs struct {
+	mu     sync.Mutex
+	values map[resource.URN]resource.PropertyMap
+}
+
+// Stash keeps the write-only values of the checked inputs of urn, and returns the inputs without them.
+func (w *WriteOnlyInputs) Stash(urn resource.URN, schemaMap shim.SchemaMap, schemaInfos map[string]*SchemaInfo,
+	inputs resource.PropertyMap) resource.PropertyMap {
+	if !hasWriteOnlyFields(schemaMap, schemaInfos) {
+		return inputs
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.values == nil {
+		w.values = map[resource.URN]resource.PropertyMap{}
+	}
+	// Secrets are unwrapped as the engine does for the inputs it passes to Create and Update.
+	w.values[urn] = propertyvalue.RemoveSecrets(resource.NewObjectProperty(inputs)).ObjectValue()
+	return RemoveWriteOnlyValues(schemaMap, schemaInfos, inputs)
+}
+
+// Peek copies the stashed write-only values of urn into inputs and keeps them for the Create or Update that follows
+// a Diff. Inputs are returned unchanged if nothing is stashed.
+func (w *WriteOnlyInputs) Peek(urn resource.URN, schemaMap shim.SchemaMap, schemaInfos map[string]*SchemaInfo,
+	inputs resource.PropertyMap) resource.PropertyMap {
+	w.mu.Lock()
+	stashed, ok := w.values[urn]
+	w.mu.Unlock()
+	if !ok {
+		return inputs
+	}
+	return CopyWriteOnlyValues(schemaMap, schemaInfos, stashed, inputs)
+}
+
+// Restore copies the stashed write-only values of urn into inputs and drops them, since they are no longer needed
+// once they have been sent to the provider.
+//
+// Restore fails if the resource has write-only properties but nothing is stashed for urn.
+func (w *WriteOnlyInputs) Restore(urn resource.URN, schemaMap shim.SchemaMap, schemaInfos map[string]*SchemaInfo,
+	inputs resource.PropertyMap) (resource.PropertyMap, error) {
+	if !hasWriteOnlyFields(schemaMap, schemaInfos) {
+		return inputs, nil
+	}
+	w.mu.Lock()
+	stashed, ok := w.values[urn]
+	delete(w.values, urn)
+	w.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("the write-only values of %s are missing: "+
+			"they are only kept by the provider process that checked the resource", urn)
+	}
+	return CopyWriteOnlyValues(schemaMap, schemaInfos, stashed, inputs), nil
+}
+
+// Forget drops the stashed write-only values of urn.
+func (w *WriteOnlyInputs) Forget(urn resource.URN) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.values, urn)
+}
+
+type writeOnlyFields struct {
+	schemaMap   shim.SchemaMap
+	schemaInfos map[string]*SchemaInfo
+}
+
+func (wo *writeOnlyFields) isWriteOnly(path resource.PropertyPath) bool {
+	schemaPath := PropertyPathToSchemaPath(path, wo.schemaMap, wo.schemaInfos)
+	s, info, err := LookupSchemas(schemaPath, wo.schemaMap, wo.schemaInfos)
+	if err != nil {
+		return false
+	}
+	return IsWriteOnly(s, info)
+}
+
+func hasWriteOnlyFields(schemaMap shim.SchemaMap, schemaInfos map[string]*SchemaInfo) bool {
+	var checkInfo func(info *SchemaInfo) bool
+	checkInfo = func(info *SchemaInfo) bool {
+		if info == nil {
+			return false
+		}
+		if info.WriteOnly || checkInfo(info.Elem) {
+			return true
+		}
+		for _, f := range info.Fields {
+			if checkInfo(f) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, info := range schemaInfos {
+		if checkInfo(info) {
+			return true
+		}
+	}
+	return schemaMapHasWriteOnly(schemaMap)
+}
+
+func schemaMapHasWriteOnly(schemaMap shim.SchemaMap) bool {
+	if schemaMap == nil {
+		return false
+	}
+	found := false
+	schemaMap.Range(func(_ string, s shim.Schema) bool {
+		found = schemaHasWriteOnly(s)
+		return !found
+	})
+	return found
+}
+
+func schemaHasWriteOnly(s shim.Schema) bool {
+	if s == nil {
+		return false
+	}
+	if IsWriteOnly(s, nil) {
+		return true
+	}
+	switch elem := s.Elem().(type) {
+	case shim.Schema:
+		return schemaHasWriteOnly(elem)
+	case shim.Resource:
+		return schemaMapHasWriteOnly(elem.Schema())
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfbridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func TestWriteOnlyValues(t *testing.T) {
+	t.Parallel()
+
+	schemaMap := schema.SchemaMap{
+		"name":     (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"password": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"user": (&schema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			Elem: (&schema.Resource{
+				Schema: schema.SchemaMap{
+					"login":    (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+					"password": (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+				},
+			}).Shim(),
+		}).Shim(),
+	}
+	schemaInfos := map[string]*SchemaInfo{
+		"password": {WriteOnly: true},
+		"user": {Elem: &SchemaInfo{Fields: map[string]*SchemaInfo{
+			"password": {WriteOnly: true},
+		}}},
+	}
+
+	user := func(login, password string) resource.PropertyValue {
+		m := resource.PropertyMap{"login": resource.NewStringProperty(login)}
+		if password != "" {
+			m["password"] = resource.NewStringProperty(password)
+		}
+		return resource.NewObjectProperty(m)
+	}
+
+	t.Run("remove", func(t *testing.T) {
+		t.Parallel()
+		outputs := resource.PropertyMap{
+			"name":     resource.NewStringProperty("db"),
+			"password": resource.NewStringProperty("secret"),
+			"users":    resource.NewArrayProperty([]resource.PropertyValue{user("a", "pa"), user("b", "pb")}),
+		}
+		actual := RemoveWriteOnlyValues(schemaMap, schemaInfos, outputs)
+		assert.Equal(t, resource.PropertyMap{
+			"name":  resource.NewStringProperty("db"),
+			"users": resource.NewArrayProperty([]resource.PropertyValue{user("a", ""), user("b", "")}),
+		}, actual)
+		// The input is not modified.
+		assert.Contains(t, outputs, resource.PropertyKey("password"))
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		t.Parallel()
+		news := resource.PropertyMap{
+			"name":     resource.NewStringProperty("db2"),
+			"password": resource.NewStringProperty("secret"),
+			"users":    resource.NewArrayProperty([]resource.PropertyValue{user("a", "pa"), user("b", "pb")}),
+		}
+		olds := resource.PropertyMap{
+			"name":  resource.NewStringProperty("db"),
+			"users": resource.NewArrayProperty([]resource.PropertyValue{user("a", "")}),
+		}
+		actual := CopyWriteOnlyValues(schemaMap, schemaInfos, news, olds)
+		assert.Equal(t, resource.PropertyMap{
+			"name":     resource.NewStringProperty("db"),
+			"password": resource.NewStringProperty("secret"),
+			"users":    resource.NewArrayProperty([]resource.PropertyValue{user("a", "pa")}),
+		}, actual)
+		// The input is not modified.
+		assert.NotContains(t, olds, resource.PropertyKey("password"))
+	})
+
+	t.Run("copy skips unknowns", func(t *testing.T) {
+		t.Parallel()
+		news := resource.PropertyMap{
+			"password": resource.MakeComputed(resource.NewStringProperty("")),
+		}
+		actual := CopyWriteOnlyValues(schemaMap, schemaInfos, news, resource.PropertyMap{})
+		assert.Equal(t, resource.PropertyMap{}, actual)
+	})
+
+	t.Run("write-only values are secret", func(t *testing.T) {
+		t.Parallel()
+		actual := MarkSchemaSecrets(context.Background(), schemaMap, schemaInfos,
+			resource.NewObjectProperty(resource.PropertyMap{
+				"name":     resource.NewStringProperty("db"),
+				"password": resource.NewStringProperty("secret"),
+			}))
+		assert.Equal(t, resource.NewObjectProperty(resource.PropertyMap{
+			"name":     resource.NewStringProperty("db"),
+			"password": resource.MakeSecret(resource.NewStringProperty("secret")),
+		}), actual)
+	})
+}
+
+func TestWriteOnlyInputs(t *testing.T) {
+	t.Parallel()
+
+	// The schema itself declares password write-only, without any SchemaInfo.
+	schemaMap := schema.SchemaMap{
+		"name":     (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"password": (&schema.Schema{Type: shim.TypeString, Optional: true, WriteOnly: true}).Shim(),
+	}
+	urn := resource.URN("urn:pulumi:dev::teststack::pkg:index:Res::res")
+
+	var w WriteOnlyInputs
+	checked := w.Stash(urn, schemaMap, nil, resource.PropertyMap{
+		"name":     resource.NewStringProperty("db"),
+		"password": resource.MakeSecret(resource.NewStringProperty("secret")),
+	})
+	assert.Equal(t, resource.PropertyMap{"name": resource.NewStringProperty("db")}, checked)
+
+	peeked := w.Peek(urn, schemaMap, nil, checked)
+	assert.Equal(t, resource.PropertyMap{
+		"name":     resource.NewStringProperty("db"),
+		"password": resource.NewStringProperty("secret"),
+	}, peeked)
+
+	restored, err := w.Restore(urn, schemaMap, nil, checked)
+	require.NoError(t, err)
+	assert.Equal(t, peeked, restored)
+
+	// Restore drops the values, so a second Restore fails rather than silently sending no value.
+	assert.Equal(t, checked, w.Peek(urn, schemaMap, nil, checked))
+	_, err = w.Restore(urn, schemaMap, nil, checked)
+	assert.ErrorContains(t, err, "write-only values of "+string(urn)+" are missing")
+
+	w.Stash(urn, schemaMap, nil, resource.PropertyMap{"password": resource.NewStringProperty("secret")})
+	w.Forget(urn)
+	assert.Empty(t, w.values)
+
+	// Resources without write-only properties have nothing to restore.
+	plain := schema.SchemaMap{"name": schemaMap["name"]}
+	restored, err = w.Restore(urn, plain, nil, checked)
+	require.NoError(t, err)
+	assert.Equal(t, checked, restored)
+}
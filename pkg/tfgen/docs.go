@@ -22,6 +22,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime/debug"
 	"sort"
@@ -201,7 +202,14 @@ func fixupImports() tfbridge.DocsEdit {
 type editRules []tfbridge.DocsEdit
 
 func (rr editRules) apply(fileName string, contents []byte) ([]byte, error) {
-	for _, rule := range rr {
+	return rr.applyObserved(fileName, contents, nil)
+}
+
+// applyObserved is like apply, but reports each rule that matches fileName to observe, along with whether the rule
+// changed the contents of the file.
+func (rr editRules) applyObserved(fileName string, contents []byte,
+	observe func(index int, changed bool)) ([]byte, error) {
+	for i, rule := range rr {
 		match, err := filepath.Match(rule.Path, fileName)
 		if err != nil {
 			return nil, fmt.Errorf("invalid glob: %q: %w", rule.Path, err)
@@ -209,10 +217,14 @@ func (rr editRules) apply(fileName string, contents []byte) ([]byte, error) {
 		if !match {
 			continue
 		}
-		contents, err = rule.Edit(fileName, contents)
+		edited, err := rule.Edit(fileName, contents)
 		if err != nil {
 			return nil, fmt.Errorf("replace failed: %w", err)
 		}
+		if observe != nil {
+			observe(i, !bytes.Equal(edited, contents))
+		}
+		contents = edited
 	}
 	return contents, nil
 }
@@ -222,7 +234,16 @@ func (rr editRules) apply(fileName string, contents []byte) ([]byte, error) {
 // getEditRules is only called once during `tfgen`, so we move the cost of compiling
 // regexes into getEditRules, avoiding a marginal startup time penalty.
 func getEditRules(info *tfbridge.DocRuleInfo) editRules {
-	defaults := []tfbridge.DocsEdit{
+	defaults := defaultEditRules()
+	if info == nil || info.EditRules == nil {
+		return defaults
+	}
+	return info.EditRules(defaults)
+}
+
+// The edit rules that the bridge suggests to every provider, see [tfbridge.DocRuleInfo.EditRules].
+func defaultEditRules() []tfbridge.DocsEdit {
+	return []tfbridge.DocsEdit{
 		// Replace content such as "`terraform plan`" with "`pulumi preview`"
 		boundedReplace("[tT]erraform [pP]lan", "pulumi preview"),
 		// Replace content such as " Terraform Apply." with " pulumi up."
@@ -233,10 +254,22 @@ func getEditRules(info *tfbridge.DocRuleInfo) editRules {
 		// Replace content such as "jdoe@hashicorp.com" with "jdoe@example.com"
 		reReplace("@hashicorp.com", "@example.com"),
 	}
-	if info == nil || info.EditRules == nil {
-		return defaults
+}
+
+// isDefaultEditRule reports whether rule is one of defaults, as passed through by DocRuleInfo.EditRules.
+//
+// The default rules are closures created by this package, so they are recognized by the code of their Edit function.
+func isDefaultEditRule(rule tfbridge.DocsEdit, defaults []tfbridge.DocsEdit) bool {
+	if rule.Edit == nil {
+		return false
 	}
-	return info.EditRules(defaults)
+	edit := reflect.ValueOf(rule.Edit).Pointer()
+	for _, d := range defaults {
+		if reflect.ValueOf(d.Edit).Pointer() == edit {
+			return true
+		}
+	}
+	return false
 }
 
 func (k DocKind) String() string {
@@ -290,7 +323,8 @@ func getDocsForResource(g *Generator, source DocsSource, kind DocKind,
 	}
 
 	if docFile == nil {
-		entitiesMissingDocs++
+		countMetric(&entitiesMissingDocs)
+		g.docsQuality.missingDocs(kind, rawname)
 		msg := fmt.Sprintf("could not find docs for %v %v. Override the Docs property in the %v mapping. See "+
 			"type tfbridge.DocInfo for details.", kind, formatEntityName(rawname), kind)
 
@@ -489,6 +523,7 @@ func parseTFMarkdown(g *Generator, info tfbridge.ResourceOrDataSourceInfo, kind
 			info:     g.info,
 		},
 		editRules: g.editRules,
+		quality:   g.docsQuality,
 	}
 	return p.parse(markdown)
 }
@@ -508,6 +543,7 @@ type tfMarkdownParser struct {
 
 	infoCtx   infoContext
 	editRules editRules
+	quality   *DocsQualityReport
 
 	ret entityDocs
 }
@@ -542,7 +578,10 @@ func (p *tfMarkdownParser) parse(tfMarkdown []byte) (entityDocs, error) {
 		Attributes: make(map[string]string),
 	}
 	var err error
-	tfMarkdown, err = p.editRules.apply(p.markdownFileName, tfMarkdown)
+	tfMarkdown, err = p.editRules.applyObserved(p.markdownFileName, tfMarkdown,
+		func(index int, changed bool) {
+			p.quality.editRuleApplied(index, changed)
+		})
 	if err != nil {
 		return entityDocs{}, fmt.Errorf("file %s: %w", p.markdownFileName, err)
 	}
@@ -717,7 +756,8 @@ func (p *tfMarkdownParser) parseSection(h2Section []string) error {
 	switch header {
 	case "Timeout", "Timeouts", "User Project Override", "User Project Overrides":
 		p.sink.debug("Ignoring doc section [%v] for [%v]", header, p.rawname)
-		ignoredDocHeaders[header]++
+		countIgnoredDocHeader(header)
+		p.quality.droppedSection(p.kind, p.rawname, header)
 		return nil
 	case "Example Usage":
 		sectionKind = sectionExampleUsage
@@ -757,7 +797,8 @@ func (p *tfMarkdownParser) parseSection(h2Section []string) error {
 		// Now process the content based on the H2 topic. These are mostly standard across TF's docs.
 		switch sectionKind {
 		case sectionArgsReference:
-			parseArgReferenceSection(reformattedH3Section, &p.ret)
+			unparsed := parseArgReferenceSection(reformattedH3Section, &p.ret)
+			p.quality.unparsedArgumentLines(p.kind, p.rawname, unparsed)
 		case sectionAttributesReference:
 			parseAttributesReferenceSection(reformattedH3Section, &p.ret)
 		case sectionFrontMatter:
@@ -768,7 +809,8 @@ func (p *tfMarkdownParser) parseSection(h2Section []string) error {
 			// Determine if this is a nested argument section.
 			_, isArgument := p.ret.Arguments[docsPath(header)]
 			if isArgument || strings.HasSuffix(header, "Configuration Block") {
-				parseArgReferenceSection(reformattedH3Section, &p.ret)
+				unparsed := parseArgReferenceSection(reformattedH3Section, &p.ret)
+				p.quality.unparsedArgumentLines(p.kind, p.rawname, unparsed)
 				continue
 			}
 
@@ -879,7 +921,10 @@ func getNestedBlockName(line string) string {
 	return nested
 }
 
-func parseArgReferenceSection(subsection []string, ret *entityDocs) {
+// parseArgReferenceSection parses the arguments described in subsection into ret. Non-blank lines that could not
+// be attributed to any argument or nested block are returned.
+func parseArgReferenceSection(subsection []string, ret *entityDocs) []string {
+	var unparsed []string
 	var lastMatch string
 	var nested docsPath
 
@@ -888,7 +933,7 @@ func parseArgReferenceSection(subsection []string, ret *entityDocs) {
 		if nested != "" {
 			// We found this line within a nested field. We should record it as such.
 			if ret.Arguments[nested] == nil {
-				totalArgumentsFromDocs++
+				countMetric(&totalArgumentsFromDocs)
 			}
 			ret.Arguments[nested.join(name)] = &argumentDocs{desc}
 		} else {
@@ -896,7 +941,7 @@ func parseArgReferenceSection(subsection []string, ret *entityDocs) {
 				return
 			}
 			ret.Arguments[docsPath(name)] = &argumentDocs{description: desc}
-			totalArgumentsFromDocs++
+			countMetric(&totalArgumentsFromDocs)
 		}
 	}
 
@@ -935,6 +980,10 @@ func parseArgReferenceSection(subsection []string, ret *entityDocs) {
 			lastMatch = ""
 		} else if lastMatch != "" {
 			extendExistingHeading(line)
+		} else if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasSuffix(trimmed, ":") {
+			// Lines ending in ":" introduce the list that follows them, such as "The following
+			// arguments are supported:", so they are not counted as unparsed.
+			unparsed = append(unparsed, trimmed)
 		}
 		hadSpace = isBlank(line)
 	}
@@ -942,6 +991,7 @@ func parseArgReferenceSection(subsection []string, ret *entityDocs) {
 	for _, v := range ret.Arguments {
 		v.description = strings.TrimRightFunc(v.description, unicode.IsSpace)
 	}
+	return unparsed
 }
 
 func parseAttributesReferenceSection(subsection []string, ret *entityDocs) {
@@ -1854,11 +1904,11 @@ func cleanupDoc(
 		cleanedText, elided := reformatText(infoCtx, v.description, footerLinks)
 		if elided {
 			if k.nested() {
-				elidedNestedArguments++
+				countMetric(&elidedNestedArguments)
 				g.warn("Found <elided> in docs for nested argument [%v] in [%v]. The argument's description will be "+
 					"dropped in the Pulumi provider.", k, name)
 			} else {
-				elidedArguments++
+				countMetric(&elidedArguments)
 				g.warn("Found <elided> in docs for argument [%v] in [%v]. The argument's description will be dropped in "+
 					"the Pulumi provider.", k, name)
 			}
@@ -0,0 +1,374 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+/*
+This file implements a per-entity report on the quality of the documentation that
+tfgen derives from upstream Terraform Markdown.
+
+Where printDocStats reports aggregate numbers, the DocsQualityReport records
+*which* resources and data sources are affected, so that the report can be
+checked in or diffed in CI to catch documentation regressions across upstream
+upgrades. It records:
+
+  - properties that end up with no description at all,
+  - lines in argument reference sections that could not be parsed,
+  - nested blocks that could not be matched to a path in the TF schema,
+  - H2 sections that were dropped,
+  - entities whose docs could not be found, and
+  - edit rules (DocRuleInfo.EditRules) that never changed any file.
+*/
+
+package tfgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/walk"
+)
+
+// DocsQualityReport collects documentation quality findings for each resource and data source
+// seen by the generator. All methods are safe to call on a nil *DocsQualityReport, in which case
+// they do nothing.
+type DocsQualityReport struct {
+	ProviderName    string                        // Name of the provider
+	ProviderVersion string                        // Version of the provider
+	Entities        map[string]*EntityDocsQuality // Findings keyed by "<kind>:<TF name>"
+	EditRules       []*EditRuleUsage              // Usage of each edit rule configured by the provider
+
+	editRuleIndex map[int]*EditRuleUsage // EditRules by the index of the rule in the list of edit rules
+	mu            sync.Mutex
+}
+
+// EntityDocsQuality holds the documentation findings for a single resource or data source.
+type EntityDocsQuality struct {
+	Name string // The TF name of the entity, such as "aws_s3_bucket"
+	Kind string // Either "resource" or "data source"
+
+	MissingDocs                   bool     `json:",omitempty"` // No upstream docs could be found
+	PropertiesMissingDescriptions []string `json:",omitempty"` // TF property paths without a description
+	UnparsedArgumentLines         []string `json:",omitempty"` // Argument reference lines that were dropped
+	UnmatchedNestedBlocks         []string `json:",omitempty"` // Documented nested blocks absent from the schema
+	DroppedSections               []string `json:",omitempty"` // H2 sections that were ignored
+}
+
+// EditRuleUsage tracks how often a single edit rule matched a docs file, and how often it changed it.
+type EditRuleUsage struct {
+	Index   int    // The position of the rule in the list of edit rules
+	Path    string // The file glob of the rule
+	Matched int    // How many files the rule's glob matched
+	Changed int    // How many files the rule actually changed
+}
+
+func newDocsQualityReport(providerName, providerVersion string) *DocsQualityReport {
+	return &DocsQualityReport{
+		ProviderName:    providerName,
+		ProviderVersion: providerVersion,
+		Entities:        make(map[string]*EntityDocsQuality),
+	}
+}
+
+// entity must be called with r.mu held.
+func (r *DocsQualityReport) entity(kind DocKind, rawname string) *EntityDocsQuality {
+	key := kind.String() + ":" + rawname
+	e, ok := r.Entities[key]
+	if !ok {
+		e = &EntityDocsQuality{Name: rawname, Kind: kind.String()}
+		r.Entities[key] = e
+	}
+	return e
+}
+
+func (r *DocsQualityReport) missingDocs(kind DocKind, rawname string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entity(kind, rawname).MissingDocs = true
+}
+
+func (r *DocsQualityReport) droppedSection(kind DocKind, rawname, header string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.entity(kind, rawname)
+	e.DroppedSections = append(e.DroppedSections, header)
+}
+
+func (r *DocsQualityReport) unparsedArgumentLines(kind DocKind, rawname string, lines []string) {
+	if r == nil || len(lines) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.entity(kind, rawname)
+	e.UnparsedArgumentLines = append(e.UnparsedArgumentLines, lines...)
+}
+
+// registerEditRules selects the rules that the report tracks: every rule configured by the provider shows up in the
+// report, even if it never matched a file. The bridge's default rules are not tracked, since providers do not own
+// them.
+func (r *DocsQualityReport) registerEditRules(rules editRules) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	defaults := defaultEditRules()
+	r.EditRules, r.editRuleIndex = nil, map[int]*EditRuleUsage{}
+	for i, rule := range rules {
+		if isDefaultEditRule(rule, defaults) {
+			continue
+		}
+		u := &EditRuleUsage{Index: i, Path: rule.Path}
+		r.EditRules = append(r.EditRules, u)
+		r.editRuleIndex[i] = u
+	}
+}
+
+// editRuleApplied records that the edit rule at index matched a file, and whether it changed the file's contents.
+// Rules that are not tracked, see registerEditRules, are ignored.
+func (r *DocsQualityReport) editRuleApplied(index int, changed bool) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.editRuleIndex[index]
+	if !ok {
+		return
+	}
+	u.Matched++
+	if changed {
+		u.Changed++
+	}
+}
+
+// checkEntity records the properties of a gathered entity that are missing descriptions, as well as the
+// nested blocks in the entity's docs that do not correspond to any path in the TF schema.
+func (r *DocsQualityReport) checkEntity(kind DocKind, rawname string, sch shim.SchemaMap,
+	docs entityDocs, vars ...[]*variable) {
+	if r == nil {
+		return
+	}
+
+	missing := map[string]struct{}{}
+	var visit func(prefix string, v *variable)
+	var visitType func(prefix string, t *propertyType)
+	visit = func(prefix string, v *variable) {
+		path := v.propertyName.Key
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		if v.doc == "" && v.rawdoc == "" {
+			missing[path] = struct{}{}
+		}
+		visitType(path, v.typ)
+	}
+	visitType = func(prefix string, t *propertyType) {
+		if t == nil {
+			return
+		}
+		visitType(prefix, t.element)
+		for _, p := range t.properties {
+			visit(prefix, p)
+		}
+	}
+	for _, vs := range vars {
+		for _, v := range vs {
+			visit("", v)
+		}
+	}
+
+	// Collect every path in the schema so that documented nested blocks can be checked against it. Paths
+	// are joined by "." and do not contain element steps, matching the layout of docsPath.
+	var schemaPaths []string
+	walk.VisitSchemaMap(sch, func(p walk.SchemaPath, _ shim.Schema) {
+		var parts []string
+		for _, step := range p {
+			if step, ok := step.(walk.GetAttrStep); ok {
+				parts = append(parts, step.Name)
+			}
+		}
+		schemaPaths = append(schemaPaths, strings.Join(parts, "."))
+	})
+	inSchema := func(block string) bool {
+		for _, p := range schemaPaths {
+			if p == block || strings.HasSuffix(p, "."+block) {
+				return true
+			}
+		}
+		return false
+	}
+
+	unmatched := map[string]struct{}{}
+	for arg := range docs.Arguments {
+		if !arg.nested() {
+			continue
+		}
+		parts := arg.parts()
+		block := strings.Join(parts[:len(parts)-1], ".")
+		if !inSchema(block) {
+			unmatched[block] = struct{}{}
+		}
+	}
+
+	if len(missing) == 0 && len(unmatched) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.entity(kind, rawname)
+	e.PropertiesMissingDescriptions = sortedKeys(missing)
+	e.UnmatchedNestedBlocks = sortedKeys(unmatched)
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedEntities returns the entities with findings, sorted by kind and then name.
+func (r *DocsQualityReport) sortedEntities() []*EntityDocsQuality {
+	entities := make([]*EntityDocsQuality, 0, len(r.Entities))
+	for _, e := range r.Entities {
+		entities = append(entities, e)
+	}
+	sort.Slice(entities, func(i, j int) bool {
+		if entities[i].Kind != entities[j].Kind {
+			return entities[i].Kind > entities[j].Kind // resources before data sources
+		}
+		return entities[i].Name < entities[j].Name
+	})
+	return entities
+}
+
+// unusedEditRules returns the edit rules that never changed a file.
+func (r *DocsQualityReport) unusedEditRules() []*EditRuleUsage {
+	var unused []*EditRuleUsage
+	for _, u := range r.EditRules {
+		if u != nil && u.Changed == 0 {
+			unused = append(unused, u)
+		}
+	}
+	return unused
+}
+
+// exportResults writes the report to outputDirectory as docsQuality.json and docsQuality.md.
+func (r *DocsQualityReport) exportResults(outputDirectory string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type jsonReport struct {
+		ProviderName     string
+		ProviderVersion  string
+		Entities         []*EntityDocsQuality
+		EditRules        []*EditRuleUsage
+		UnusedEditRules  []int
+		EntitiesWithDocs int
+	}
+	report := jsonReport{
+		ProviderName:    r.ProviderName,
+		ProviderVersion: r.ProviderVersion,
+		Entities:        r.sortedEntities(),
+		EditRules:       r.EditRules,
+	}
+	for _, u := range r.unusedEditRules() {
+		report.UnusedEditRules = append(report.UnusedEditRules, u.Index)
+	}
+	for _, e := range report.Entities {
+		if !e.MissingDocs {
+			report.EntitiesWithDocs++
+		}
+	}
+
+	jsonBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	jsonOutputLocation, err := createEmptyFile(outputDirectory, "docsQuality.json")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(jsonOutputLocation, append(jsonBytes, '\n'), 0600); err != nil {
+		return err
+	}
+
+	mdOutputLocation, err := createEmptyFile(outputDirectory, "docsQuality.md")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(mdOutputLocation, []byte(r.markdown()), 0600)
+}
+
+// markdown renders the report as a Markdown document. r.mu must be held.
+func (r *DocsQualityReport) markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Documentation quality for %s", r.ProviderName)
+	if r.ProviderVersion != "" {
+		fmt.Fprintf(&b, " %s", r.ProviderVersion)
+	}
+	b.WriteString("\n\n")
+
+	list := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "%s:\n\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "- `%s`\n", strings.ReplaceAll(item, "`", "'"))
+		}
+		b.WriteString("\n")
+	}
+
+	entities := r.sortedEntities()
+	if len(entities) == 0 {
+		b.WriteString("No documentation issues were found.\n\n")
+	}
+	for _, e := range entities {
+		fmt.Fprintf(&b, "## %s `%s`\n\n", e.Kind, e.Name)
+		if e.MissingDocs {
+			b.WriteString("No upstream documentation was found.\n\n")
+		}
+		list("Properties missing descriptions", e.PropertiesMissingDescriptions)
+		list("Unparsed argument lines", e.UnparsedArgumentLines)
+		list("Nested blocks not found in the schema", e.UnmatchedNestedBlocks)
+		list("Dropped sections", e.DroppedSections)
+	}
+
+	if unused := r.unusedEditRules(); len(unused) > 0 {
+		b.WriteString("## Edit rules that never changed a file\n\n")
+		for _, u := range unused {
+			fmt.Fprintf(&b, "- rule %d (path `%s`) matched %d files and changed none\n", u.Index, u.Path, u.Matched)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
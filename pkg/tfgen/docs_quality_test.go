@@ -0,0 +1,198 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfgen/internal/paths"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func TestDocsQualityReportParse(t *testing.T) {
+	t.Parallel()
+
+	report := newDocsQualityReport("test", "1.0.0")
+	rules := editRules{
+		{Path: "*", Edit: func(_ string, b []byte) ([]byte, error) { return b, nil }},
+		{Path: "*.md", Edit: func(_ string, b []byte) ([]byte, error) {
+			return append(b, []byte("\n")...), nil
+		}},
+		{Path: "*.html.markdown", Edit: func(_ string, b []byte) ([]byte, error) { return nil, nil }},
+	}
+	report.registerEditRules(rules)
+
+	p := &tfMarkdownParser{
+		sink:             mockSink{t},
+		kind:             ResourceDocs,
+		markdownFileName: "test_res.md",
+		rawname:          "test_res",
+		editRules:        rules,
+		quality:          report,
+	}
+
+	_, err := p.parse([]byte(`---
+page_title: "test_res"
+---
+
+# test_res
+
+Provides a test resource.
+
+## Argument Reference
+
+~> **NOTE:** Something important.
+
+The following arguments are supported:
+
+* ` + "`name`" + ` - (Required) The name.
+
+## Timeouts
+
+* ` + "`create`" + ` - (Default 10m)
+`))
+	require.NoError(t, err)
+
+	e := report.Entities["resource:test_res"]
+	require.NotNil(t, e)
+	assert.Equal(t, []string{"Timeouts"}, e.DroppedSections)
+	assert.Equal(t, []string{"~> **NOTE:** Something important."}, e.UnparsedArgumentLines)
+
+	require.Len(t, report.EditRules, 3)
+	assert.Equal(t, EditRuleUsage{Index: 0, Path: "*", Matched: 1}, *report.EditRules[0])
+	assert.Equal(t, EditRuleUsage{Index: 1, Path: "*.md", Matched: 1, Changed: 1}, *report.EditRules[1])
+	assert.Equal(t, EditRuleUsage{Index: 2, Path: "*.html.markdown"}, *report.EditRules[2])
+
+	unused := report.unusedEditRules()
+	require.Len(t, unused, 2)
+	assert.Equal(t, 0, unused[0].Index)
+	assert.Equal(t, 2, unused[1].Index)
+}
+
+func TestDocsQualityReportSkipsDefaultEditRules(t *testing.T) {
+	t.Parallel()
+
+	custom := tfbridge.DocsEdit{Path: "*", Edit: func(_ string, b []byte) ([]byte, error) { return b, nil }}
+	rules := getEditRules(&tfbridge.DocRuleInfo{
+		EditRules: func(defaults []tfbridge.DocsEdit) []tfbridge.DocsEdit {
+			return append(defaults, custom)
+		},
+	})
+	require.Greater(t, len(rules), 1)
+
+	report := newDocsQualityReport("test", "1.0.0")
+	report.registerEditRules(rules)
+	_, err := rules.applyObserved("test_res.md", []byte("Run terraform plan."), report.editRuleApplied)
+	require.NoError(t, err)
+
+	index := len(rules) - 1
+	assert.Equal(t, []*EditRuleUsage{{Index: index, Path: "*", Matched: 1}}, report.EditRules)
+
+	dir := t.TempDir()
+	require.NoError(t, report.exportResults(dir))
+	md, err := os.ReadFile(filepath.Join(dir, "docsQuality.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "## Edit rules that never changed a file\n\n"+
+		fmt.Sprintf("- rule %d (path `*`) matched 1 files and changed none\n", index))
+}
+
+func TestDocsQualityReportCheckEntity(t *testing.T) {
+	t.Parallel()
+
+	sch := shimschema.SchemaMap{
+		"name": (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+		"rule": (&shimschema.Schema{
+			Type:     shim.TypeList,
+			Optional: true,
+			Elem: (&shimschema.Resource{
+				Schema: shimschema.SchemaMap{
+					"action": (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+				},
+			}).Shim(),
+		}).Shim(),
+	}
+
+	prop := func(key, doc string, typ *propertyType) *variable {
+		return &variable{
+			doc:          doc,
+			propertyName: paths.PropertyName{Key: key},
+			typ:          typ,
+		}
+	}
+	vars := []*variable{
+		prop("name", "The name.", &propertyType{kind: kindString}),
+		prop("rule", "A rule.", &propertyType{
+			kind: kindList,
+			element: &propertyType{
+				kind:       kindObject,
+				properties: []*variable{prop("action", "", &propertyType{kind: kindString})},
+			},
+		}),
+	}
+
+	docs := entityDocs{
+		Arguments: map[docsPath]*argumentDocs{
+			"name":          {description: "The name."},
+			"rule":          {description: "A rule."},
+			"rule.priority": {description: "Documented, but the path exists."},
+			"filter.value":  {description: "Documented, but not in the schema."},
+		},
+	}
+
+	report := newDocsQualityReport("test", "1.0.0")
+	report.checkEntity(ResourceDocs, "test_res", sch, docs, vars, vars)
+
+	e := report.Entities["resource:test_res"]
+	require.NotNil(t, e)
+	assert.Equal(t, []string{"rule.action"}, e.PropertiesMissingDescriptions)
+	assert.Equal(t, []string{"filter"}, e.UnmatchedNestedBlocks)
+
+	dir := t.TempDir()
+	require.NoError(t, report.exportResults(dir))
+
+	jsonBytes, err := os.ReadFile(filepath.Join(dir, "docsQuality.json"))
+	require.NoError(t, err)
+	var actual struct {
+		Entities []EntityDocsQuality
+	}
+	require.NoError(t, json.Unmarshal(jsonBytes, &actual))
+	assert.Equal(t, []EntityDocsQuality{*e}, actual.Entities)
+
+	md, err := os.ReadFile(filepath.Join(dir, "docsQuality.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "## resource `test_res`")
+	assert.Contains(t, string(md), "- `rule.action`")
+}
+
+func TestDocsQualityReportNil(t *testing.T) {
+	t.Parallel()
+
+	var report *DocsQualityReport
+	report.missingDocs(ResourceDocs, "test_res")
+	report.droppedSection(ResourceDocs, "test_res", "Timeouts")
+	report.unparsedArgumentLines(ResourceDocs, "test_res", []string{"line"})
+	report.editRuleApplied(0, true)
+	report.registerEditRules(getEditRules(&tfbridge.DocRuleInfo{}))
+	report.checkEntity(ResourceDocs, "test_res", shimschema.SchemaMap{}, entityDocs{})
+}
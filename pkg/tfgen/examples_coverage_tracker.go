@@ -29,6 +29,7 @@ package tfgen
 
 import (
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 )
@@ -54,6 +55,9 @@ type CoverageTracker struct {
 	ProviderName     string                        // Name of the provider
 	ProviderVersion  string                        // Version of the provider
 	EncounteredPages map[string]*DocumentationPage // Map linking page IDs to their data
+
+	// Guards EncounteredPages and the examples in it, as examples may be converted concurrently.
+	mu sync.Mutex
 }
 
 // A structure encompassing a single page, which contains one or more examples.
@@ -90,14 +94,15 @@ const (
 )
 
 func newCoverageTracker(ProviderName string, ProviderVersion string) *CoverageTracker {
-	return &CoverageTracker{ProviderName, ProviderVersion, make(map[string]*DocumentationPage)}
+	return &CoverageTracker{
+		ProviderName:     ProviderName,
+		ProviderVersion:  ProviderVersion,
+		EncounteredPages: make(map[string]*DocumentationPage),
+	}
 }
 
-// Find example by pageName and raw HCL source.
+// Find example by pageName and raw HCL source. Must be called with ct.mu held.
 func (ct *CoverageTracker) getExample(pageName string, hcl string) *Example {
-	if ct == nil {
-		return nil
-	}
 	page, ok := ct.EncounteredPages[pageName]
 	if !ok {
 		return nil
@@ -116,6 +121,8 @@ func (ct *CoverageTracker) getOrCreateExample(pageName string, hcl string) *Exam
 	if ct == nil {
 		return nil
 	}
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
 	// If the example with this HCL already exists, return it right away.
 	if e := ct.getExample(pageName, hcl); e != nil {
 		return e
@@ -199,6 +206,8 @@ func (ct *CoverageTracker) languageConversionPanic(
 func (ct *CoverageTracker) insertLanguageConversionResult(
 	e *Example, languageName string, newConversionResult LanguageConversionResult,
 ) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
 	if existingConversionResult, ok := e.ConversionResults[languageName]; ok {
 		// Example already has this language conversion attempt. Replace if new one has a
 		// lower severity
@@ -247,11 +256,15 @@ func formatDiagnostics(diagnostics hcl.Diagnostics) string {
 
 // Exporting the coverage results
 func (ct *CoverageTracker) exportResults(outputDirectory string) error {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
 	coverageExportUtil := newCoverageExportUtil(ct)
 	return coverageExportUtil.tryExport(outputDirectory)
 }
 
 func (ct *CoverageTracker) getShortResultSummary() string {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
 	coverageExportUtil := newCoverageExportUtil(ct)
 	return coverageExportUtil.produceHumanReadableSummary()
 }
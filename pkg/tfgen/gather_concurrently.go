@@ -0,0 +1,108 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+)
+
+// The result of gathering a single entity with gatherConcurrently.
+type gathered[T any] struct {
+	value T
+	err   error
+	diags *entityDiags
+}
+
+// report replays the diagnostics buffered while gathering the entity to g, and returns the result.
+func (r gathered[T]) report(g *Generator) (T, error) {
+	for _, d := range r.diags.entries {
+		d(g)
+	}
+	return r.value, r.err
+}
+
+// gatherConcurrently calls gather for each index in [0, n) on a pool of goroutines, and returns the results in
+// index order.
+//
+// Each call receives its own shallow copy of g whose diagnostics are buffered rather than written to g.sink. The
+// caller replays them with [gathered.report] in a stable order, so that the output of the generator does not depend
+// on scheduling. gather must only use state of g that is read-only or safe for concurrent use.
+func gatherConcurrently[T any](g *Generator, n int, gather func(g *Generator, i int) (T, error)) []gathered[T] {
+	results := make([]gathered[T], n)
+	indices := make(chan int)
+
+	var wg sync.WaitGroup
+	workers := runtime.GOMAXPROCS(0)
+	if workers > n {
+		workers = n
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				diags := &entityDiags{Sink: g.sink}
+				entityGenerator := *g
+				entityGenerator.sink = diags
+				value, err := gather(&entityGenerator, i)
+				results[i] = gathered[T]{value: value, err: err, diags: diags}
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	return results
+}
+
+// entityDiags is a diag.Sink that buffers the diagnostics of gathering a single entity. Each entry reports a
+// diagnostic to the generator it is replayed to.
+type entityDiags struct {
+	diag.Sink // The underlying sink, used to stringify diagnostics.
+
+	entries []func(g *Generator)
+}
+
+var _ diag.Sink = (*entityDiags)(nil)
+
+func (s *entityDiags) Logf(sev diag.Severity, d *diag.Diag, args ...interface{}) {
+	s.entries = append(s.entries, func(g *Generator) { g.sink.Logf(sev, d, args...) })
+}
+
+func (s *entityDiags) Debugf(d *diag.Diag, args ...interface{}) {
+	s.entries = append(s.entries, func(g *Generator) { g.sink.Debugf(d, args...) })
+}
+
+func (s *entityDiags) Infof(d *diag.Diag, args ...interface{}) {
+	s.entries = append(s.entries, func(g *Generator) { g.sink.Infof(d, args...) })
+}
+
+func (s *entityDiags) Infoerrf(d *diag.Diag, args ...interface{}) {
+	s.entries = append(s.entries, func(g *Generator) { g.sink.Infoerrf(d, args...) })
+}
+
+func (s *entityDiags) Errorf(d *diag.Diag, args ...interface{}) {
+	s.entries = append(s.entries, func(g *Generator) { g.sink.Errorf(d, args...) })
+}
+
+func (s *entityDiags) Warningf(d *diag.Diag, args ...interface{}) {
+	s.entries = append(s.entries, func(g *Generator) { g.sink.Warningf(d, args...) })
+}
@@ -0,0 +1,93 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+// Gathering entities concurrently must not change the schema or the order of diagnostics.
+func TestGatherConcurrentlyIsDeterministic(t *testing.T) {
+	t.Parallel()
+	const n = 64
+	str := (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim()
+	resources := shimschema.ResourceMap{}
+	dataSources := shimschema.ResourceMap{}
+	info := tfbridge.ProviderInfo{
+		Name:        "test",
+		Resources:   map[string]*tfbridge.ResourceInfo{},
+		DataSources: map[string]*tfbridge.DataSourceInfo{},
+	}
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("test_r%02d", i)
+		resources[name] = (&shimschema.Resource{Schema: shimschema.SchemaMap{"name": str}}).Shim()
+		dataSources[name] = (&shimschema.Resource{Schema: shimschema.SchemaMap{"name": str}}).Shim()
+		docs := &tfbridge.DocInfo{Markdown: []byte(fmt.Sprintf(
+			"# %s\n\nResource %d.\n\n## Argument Reference\n\n* `name` - (Optional) Name %d.\n", name, i, i))}
+		info.Resources[name] = &tfbridge.ResourceInfo{
+			Tok:    tokens.Type(fmt.Sprintf("test:index/r%02d:R%02d", i, i)),
+			Fields: map[string]*tfbridge.SchemaInfo{"missing": {}},
+			Docs:   docs,
+		}
+		info.DataSources[name] = &tfbridge.DataSourceInfo{
+			Tok:  tokens.ModuleMember(fmt.Sprintf("test:index/getR%02d:getR%02d", i, i)),
+			Docs: docs,
+		}
+	}
+	info.P = (&shimschema.Provider{ResourcesMap: resources, DataSourcesMap: dataSources}).Shim()
+
+	generate := func() (string, string) {
+		var stdout, stderr bytes.Buffer
+		sink := diag.DefaultSink(&stdout, &stderr, diag.FormatOptions{Color: colors.Never})
+		r, err := GenerateSchemaWithOptions(GenerateSchemaOptions{ProviderInfo: info, DiagnosticsSink: sink})
+		require.NoError(t, err)
+		spec, err := json.Marshal(r.PackageSpec)
+		require.NoError(t, err)
+		return string(spec), stdout.String() + stderr.String()
+	}
+
+	spec, diags := generate()
+	var warned []string
+	for _, line := range strings.Split(diags, "\n") {
+		if strings.Contains(line, "there is a custom mapping on resource") {
+			warned = append(warned, line)
+		}
+	}
+	require.Len(t, warned, n)
+	for i, line := range warned {
+		assert.Contains(t, line, fmt.Sprintf("'test_r%02d'", i))
+	}
+	assert.Contains(t, spec, "Name 42.")
+
+	for i := 0; i < 3; i++ {
+		again, againDiags := generate()
+		assert.Equal(t, spec, again)
+		assert.Equal(t, diags, againDiags)
+	}
+}
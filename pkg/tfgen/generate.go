@@ -72,6 +72,7 @@ type Generator struct {
 	skipDocs         bool
 	skipExamples     bool
 	coverageTracker  *CoverageTracker
+	docsQuality      *DocsQualityReport
 	editRules        editRules
 
 	convertedCode map[string][]byte
@@ -757,6 +758,8 @@ type GeneratorOptions struct {
 	SkipDocs           bool
 	SkipExamples       bool
 	CoverageTracker    *CoverageTracker
+	// DocsQualityReport, if set, collects per-entity documentation quality findings.
+	DocsQualityReport *DocsQualityReport
 }
 
 // NewGenerator returns a code-generator for the given language runtime and package info.
@@ -820,6 +823,9 @@ func NewGenerator(opts GeneratorOptions) (*Generator, error) {
 		provider:           providerShim,
 	}
 
+	editRules := getEditRules(info.DocRules)
+	opts.DocsQualityReport.registerEditRules(editRules)
+
 	return &Generator{
 		pkg:              pkg,
 		version:          version,
@@ -835,7 +841,8 @@ func NewGenerator(opts GeneratorOptions) (*Generator, error) {
 		skipDocs:         opts.SkipDocs,
 		skipExamples:     opts.SkipExamples,
 		coverageTracker:  opts.CoverageTracker,
-		editRules:        getEditRules(info.DocRules),
+		docsQuality:      opts.DocsQualityReport,
+		editRules:        editRules,
 	}, nil
 }
 
@@ -942,7 +949,7 @@ func (g *Generator) UnstableGenerateFromSchema(genSchemaResult *GenerateSchemaRe
 			files[info.Path] = (*metadata.Data)(info.Data).MarshalIndent()
 			if g.info.GenerateRuntimeMetadata {
 				runtimeInfo := info.ExtractRuntimeMetadata()
-				files[runtimeInfo.Path] = (*metadata.Data)(runtimeInfo.Data).Marshal()
+				files[runtimeInfo.Path] = (*metadata.Data)(runtimeInfo.Data).MarshalBinary()
 			}
 		}
 	case PCL:
@@ -1100,6 +1107,32 @@ func (g *Generator) gatherConfig() *module {
 	return config
 }
 
+// tagsAllVariables returns the output and state variables for the merged tags output that the bridge adds to
+// resources with default tags, or nils if it does not add one to the resource. See [tfbridge.DefaultTagsInfo].
+func (g *Generator) tagsAllVariables(
+	resourcePath *paths.ResourcePath, res shim.Resource, info *tfbridge.ResourceInfo,
+) (*variable, *variable) {
+	tags := tfbridge.NewResourceDefaultTags(g.info.DefaultTags, resourcePath.Key(), res.Schema(), info.Fields)
+	key := tags.TagsAllTerraformName()
+	if key == "" {
+		return nil, nil
+	}
+	sch := schema.SchemaMap{key: (&schema.Schema{
+		Type:     shim.TypeMap,
+		Computed: true,
+		Elem:     (&schema.Schema{Type: shim.TypeString}).Shim(),
+	}).Shim()}
+	const doc = "A map of tags assigned to the resource, including those inherited from the provider default tags."
+
+	out := g.propertyVariable(resourcePath.Outputs(), key, sch, nil, doc, "", true /*out*/, entityDocs{})
+	state := g.propertyVariable(resourcePath.State(), key, sch, nil, doc, "", false /*out*/, entityDocs{})
+	if out == nil || state == nil {
+		return nil, nil
+	}
+	state.opt = true
+	return out, state
+}
+
 // gatherProvider returns the provider resource for this package.
 func (g *Generator) gatherProvider() (*resourceType, error) {
 	cfg := g.provider().Schema()
@@ -1130,10 +1163,20 @@ func (g *Generator) gatherResources() (moduleMap, error) {
 	// let's keep a list of TF mapping errors that we can present to the user
 	var resourceMappingErrors error
 
+	// Gather each mapped resource concurrently, then process the results in order.
+	names := stableResources(resources)
+	results := gatherConcurrently(g, len(names), func(g *Generator, i int) (*resourceType, error) {
+		info := g.info.Resources[names[i]]
+		if info == nil {
+			return nil, nil
+		}
+		return g.gatherResource(names[i], resources.Get(names[i]), info, false)
+	})
+
 	// For each resource, create its own dedicated type and module export.
 	var reserr error
 	seen := make(map[string]bool)
-	for _, r := range stableResources(resources) {
+	for i, r := range names {
 		info := g.info.Resources[r]
 		if info == nil {
 			if sliceContains(g.info.IgnoreMappings, r) {
@@ -1151,7 +1194,7 @@ func (g *Generator) gatherResources() (moduleMap, error) {
 		}
 		seen[r] = true
 
-		res, err := g.gatherResource(r, resources.Get(r), info, false)
+		res, err := results[i].report(g)
 		if err != nil {
 			// Keep track of the error, but keep going, so we can expose more at once.
 			reserr = multierror.Append(reserr, err)
@@ -1165,12 +1208,12 @@ func (g *Generator) gatherResources() (moduleMap, error) {
 	}
 
 	// Emit a warning if there is a map but some names didn't match.
-	var names []string
+	var mapped []string
 	for name := range g.info.Resources {
-		names = append(names, name)
+		mapped = append(mapped, name)
 	}
-	sort.Strings(names)
-	for _, name := range names {
+	sort.Strings(mapped)
+	for _, name := range mapped {
 		if !seen[name] {
 			if !skipFailBuildOnExtraMapError {
 				resourceMappingErrors = multierror.Append(resourceMappingErrors,
@@ -1238,9 +1281,12 @@ func (g *Generator) gatherResource(rawname string,
 
 		propinfo := info.Fields[key]
 
+		// Write-only properties are never stored in state, so they are neither outputs nor part of the state type.
+		writeOnly := tfbridge.IsWriteOnly(propschema, propinfo)
+
 		// If we are generating a provider, we do not emit output property definitions as provider outputs are not
 		// yet implemented.
-		if !isProvider {
+		if !isProvider && !writeOnly {
 			// For all properties, generate the output property metadata. Note that this may differ slightly
 			// from the input in that the types may differ.
 			outprop := g.propertyVariable(resourcePath.Outputs(), key, schema.Schema(),
@@ -1253,7 +1299,7 @@ func (g *Generator) gatherResource(rawname string,
 		// If an input, generate the input property metadata.
 		if input(propschema, propinfo) {
 			if foundInAttributes && !isProvider {
-				argumentDescriptionsFromAttributes++
+				countMetric(&argumentDescriptionsFromAttributes)
 				msg := fmt.Sprintf("Argument desc from attributes: resource, rawname = '%s', property = '%s'", rawname, key)
 				g.debug(msg)
 			}
@@ -1269,6 +1315,9 @@ func (g *Generator) gatherResource(rawname string,
 		}
 
 		// Make a state variable.  This is always optional and simply lets callers perform lookups.
+		if writeOnly {
+			continue
+		}
 		stateVar := g.propertyVariable(resourcePath.State(), key, schema.Schema(), info.Fields,
 			doc, rawdoc, false /*out*/, entityDocs)
 		if stateVar != nil {
@@ -1277,6 +1326,14 @@ func (g *Generator) gatherResource(rawname string,
 		}
 	}
 
+	// Resources with default tags get an output with the merged tags, unless the upstream resource has one.
+	if !isProvider {
+		if v, s := g.tagsAllVariables(resourcePath, schema, info); v != nil {
+			res.outprops = append(res.outprops, v)
+			stateVars = append(stateVars, s)
+		}
+	}
+
 	className := res.name
 
 	// Generate a state type for looking up instances of this resource.
@@ -1295,6 +1352,10 @@ func (g *Generator) gatherResource(rawname string,
 		properties: res.inprops,
 	}
 
+	if !isProvider {
+		g.docsQuality.checkEntity(ResourceDocs, rawname, schema.Schema(), entityDocs, res.inprops, res.outprops)
+	}
+
 	// Ensure there weren't any custom fields that were unrecognized.
 	for key := range info.Fields {
 		if _, has := schema.Schema().GetOk(key); !has {
@@ -1327,10 +1388,20 @@ func (g *Generator) gatherDataSources() (moduleMap, error) {
 	// let's keep a list of TF mapping errors that we can present to the user
 	var dataSourceMappingErrors error
 
+	// Gather each mapped data source concurrently, then process the results in order.
+	names := stableResources(sources)
+	results := gatherConcurrently(g, len(names), func(g *Generator, i int) (*resourceFunc, error) {
+		dsinfo := g.info.DataSources[names[i]]
+		if dsinfo == nil {
+			return nil, nil
+		}
+		return g.gatherDataSource(names[i], sources.Get(names[i]), dsinfo)
+	})
+
 	// For each data source, create its own dedicated function and module export.
 	var dserr error
 	seen := make(map[string]bool)
-	for _, ds := range stableResources(sources) {
+	for i, ds := range names {
 		dsinfo := g.info.DataSources[ds]
 		if dsinfo == nil {
 			if sliceContains(g.info.IgnoreMappings, ds) {
@@ -1348,7 +1419,7 @@ func (g *Generator) gatherDataSources() (moduleMap, error) {
 		}
 		seen[ds] = true
 
-		fun, err := g.gatherDataSource(ds, sources.Get(ds), dsinfo)
+		fun, err := results[i].report(g)
 		if err != nil {
 			// Keep track of the error, but keep going, so we can expose more at once.
 			dserr = multierror.Append(dserr, err)
@@ -1362,12 +1433,12 @@ func (g *Generator) gatherDataSources() (moduleMap, error) {
 	}
 
 	// Emit a warning if there is a map but some names didn't match.
-	var names []string
+	var mapped []string
 	for name := range g.info.DataSources {
-		names = append(names, name)
+		mapped = append(mapped, name)
 	}
-	sort.Strings(names)
-	for _, name := range names {
+	sort.Strings(mapped)
+	for _, name := range mapped {
 		if !seen[name] {
 			if failBuildOnExtraMapError {
 				dataSourceMappingErrors = multierror.Append(dataSourceMappingErrors,
@@ -1430,7 +1501,7 @@ func (g *Generator) gatherDataSource(rawname string,
 		if input(sch, cust) {
 			doc, foundInAttributes := getDescriptionFromParsedDocs(entityDocs, arg)
 			if foundInAttributes {
-				argumentDescriptionsFromAttributes++
+				countMetric(&argumentDescriptionsFromAttributes)
 				msg := fmt.Sprintf("Argument desc taken from attributes: data source, rawname = '%s', property = '%s'",
 					rawname, arg)
 				g.debug(msg)
@@ -1486,6 +1557,8 @@ func (g *Generator) gatherDataSource(rawname string,
 		}
 	}
 
+	g.docsQuality.checkEntity(DataSourceDocs, rawname, ds.Schema(), entityDocs, fun.args, fun.rets)
+
 	return fun, nil
 }
 
@@ -1550,12 +1623,21 @@ func (g *Generator) checkNoDocsError(err error) bool {
 		return false
 	}
 
+	// When gathering concurrently, decide whether to warn once the diagnostics are reported in order.
+	if diags, ok := g.sink.(*entityDiags); ok {
+		diags.entries = append(diags.entries, func(g *Generator) { g.warnNoDocsRepo(e) })
+		return true
+	}
+	g.warnNoDocsRepo(e)
+	return true
+}
+
+func (g *Generator) warnNoDocsRepo(err GetRepoPathErr) {
 	// If we have already warned, we can just discard the message
 	if !g.noDocsRepo {
-		g.logMissingRepoPath(e)
+		g.logMissingRepoPath(err)
 	}
 	g.noDocsRepo = true
-	return true
 }
 
 func (g *Generator) logMissingRepoPath(err GetRepoPathErr) {
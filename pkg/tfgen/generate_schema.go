@@ -39,6 +39,7 @@ import (
 	tsgen "github.com/pulumi/pulumi/pkg/v3/codegen/nodejs"
 	pygen "github.com/pulumi/pulumi/pkg/v3/codegen/python"
 	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 
@@ -241,6 +242,7 @@ func genPulumiSchema(
 		if err != nil {
 			return pschema.PackageSpec{}, errors.Wrapf(err, "failed to create muxer schema")
 		}
+		dispatchTable.Config = info.MuxConfigPolicies
 		err = metadata.Set(md, "mux", dispatchTable)
 		if err != nil {
 			return pschema.PackageSpec{}, fmt.Errorf("[pkg/tfgen] failed to add muxer to MetadataInfo.Data: %w", err)
@@ -608,18 +610,32 @@ func (g *schemaGenerator) genProperty(prop *variable) pschema.PropertySpec {
 					reflect.Float64,
 					reflect.String:
 					// These are fine.
+				case
+					reflect.Map,
+					reflect.Pointer,
+					reflect.Slice,
+					reflect.Array,
+					reflect.Struct:
+					if !compositeDefaultMatchesType(rt, prop.typ) {
+						contract.Failf(
+							"Property %v has a DefaultInfo Value %v of kind %v which does not match its type.",
+							prop.name,
+							prop.info.Default.Value,
+							kind.String(),
+						)
+					}
+					// Pulumi schema only binds primitive default values, and DefaultSpec is limited to
+					// environment variables, so composite defaults are applied by the provider at runtime
+					// and documented in the property description instead.
+					description = appendCompositeDefaultDoc(description, defaultValue)
+					defaultValue = nil
 				case
 					reflect.Uintptr,
 					reflect.Complex64,
 					reflect.Complex128,
-					reflect.Array,
 					reflect.Chan,
 					reflect.Func,
 					reflect.Interface,
-					reflect.Map,
-					reflect.Pointer,
-					reflect.Slice,
-					reflect.Struct,
 					reflect.UnsafePointer:
 					fallthrough
 				default:
@@ -633,8 +649,16 @@ func (g *schemaGenerator) genProperty(prop *variable) pschema.PropertySpec {
 			}
 
 			if len(defaults.EnvVars) != 0 {
-				defaultInfo = &pschema.DefaultSpec{
-					Environment: defaults.EnvVars,
+				if t := prop.typ; t != nil &&
+					(t.kind == kindList || t.kind == kindSet || t.kind == kindMap || t.kind == kindObject) {
+					// The SDK generators read environment defaults as scalars, which would not type
+					// check against composite inputs, so these are left to the provider to parse with
+					// tfbridge.ParseEnvDefault and only documented in the schema.
+					description = appendEnvDefaultDoc(description, defaults.EnvVars, prop.typ)
+				} else {
+					defaultInfo = &pschema.DefaultSpec{
+						Environment: defaults.EnvVars,
+					}
 				}
 			}
 		}
@@ -669,6 +693,70 @@ func (g *schemaGenerator) genProperty(prop *variable) pschema.PropertySpec {
 	}
 }
 
+// compositeDefaultMatchesType checks that a slice, map or struct default value of type rt can be assigned to a
+// property of type typ.
+func compositeDefaultMatchesType(rt reflect.Type, typ *propertyType) bool {
+	for rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if typ == nil {
+		return false
+	}
+	if rt == reflect.TypeOf(resource.PropertyValue{}) {
+		return true
+	}
+	switch rt.Kind() {
+	case reflect.Slice, reflect.Array:
+		return typ.kind == kindList || typ.kind == kindSet
+	case reflect.Map, reflect.Struct:
+		return typ.kind == kindMap || typ.kind == kindObject
+	default:
+		return false
+	}
+}
+
+// appendCompositeDefaultDoc documents a slice, map or struct DefaultInfo.Value as JSON at the end of description.
+func appendCompositeDefaultDoc(description string, value interface{}) string {
+	var pv resource.PropertyValue
+	if v, ok := value.(resource.PropertyValue); ok {
+		pv = v
+	} else {
+		pv = resource.NewPropertyValue(value)
+	}
+	b, err := json.Marshal(pv.Mappable())
+	contract.AssertNoErrorf(err, "failed to marshal default value %v", value)
+
+	if description != "" && !strings.HasSuffix(description, "\n") {
+		description += "\n"
+	}
+	return description + fmt.Sprintf("Default value: `%s`.\n", b)
+}
+
+// appendEnvDefaultDoc documents the environment variables that a list, map or object property can be read from.
+func appendEnvDefaultDoc(description string, envVars []string, typ *propertyType) string {
+	names := make([]string, len(envVars))
+	for i, n := range envVars {
+		names[i] = "`" + n + "`"
+	}
+	var vars string
+	switch len(names) {
+	case 1:
+		vars = "the " + names[0] + " environment variable"
+	default:
+		vars = "the " + strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1] +
+			" environment variables"
+	}
+	format := "JSON"
+	if (typ.kind == kindList || typ.kind == kindSet) && typ.element != nil && typ.element.kind != kindObject {
+		format = "JSON or a comma-separated list"
+	}
+
+	if description != "" && !strings.HasSuffix(description, "\n") {
+		description += "\n"
+	}
+	return description + fmt.Sprintf("It can also be sourced from %s, encoded as %s.\n", vars, format)
+}
+
 func (g *schemaGenerator) genConfig(variables []*variable) pschema.ConfigSpec {
 	spec := pschema.ConfigSpec{
 		Variables: make(map[string]pschema.PropertySpec),
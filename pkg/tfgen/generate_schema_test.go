@@ -27,12 +27,15 @@ import (
 	bridgetesting "github.com/pulumi/pulumi-terraform-bridge/v3/internal/testing"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfgen/internal/testprovider"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	shimschema "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
 	"github.com/pulumi/pulumi-terraform-bridge/v3/unstable/metadata"
 	"github.com/pulumi/pulumi-terraform-bridge/x/muxer"
 	csgen "github.com/pulumi/pulumi/pkg/v3/codegen/dotnet"
 	gogen "github.com/pulumi/pulumi/pkg/v3/codegen/go"
 	tsgen "github.com/pulumi/pulumi/pkg/v3/codegen/nodejs"
 	pygen "github.com/pulumi/pulumi/pkg/v3/codegen/python"
+	pschema "github.com/pulumi/pulumi/pkg/v3/codegen/schema"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 )
@@ -458,13 +461,197 @@ func TestDefaultInfoFails(t *testing.T) {
 		assert.Contains(
 			t,
 			r,
-			"Property id has a DefaultInfo Value [default_id] of kind slice which is not currently supported.",
+			"Property id has a DefaultInfo Value [default_id] of kind slice which does not match its type.",
 		)
 	}()
 	// Should panic
 	_, _ = GenerateSchema(provider, diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never}))
 }
 
+func TestCompositeDefaultInfo(t *testing.T) {
+	provider := tfbridge.ProviderInfo{
+		Name: "test",
+		P: (&shimschema.Provider{
+			ResourcesMap: shimschema.ResourceMap{
+				"test_res": (&shimschema.Resource{
+					Schema: shimschema.SchemaMap{
+						"zones": (&shimschema.Schema{
+							Type:        shim.TypeList,
+							Optional:    true,
+							Description: "Zones to deploy to.",
+							Elem:        (&shimschema.Schema{Type: shim.TypeString}).Shim(),
+						}).Shim(),
+					},
+				}).Shim(),
+			},
+		}).Shim(),
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"test_res": {
+				Tok: "test:index:Res",
+				Fields: map[string]*tfbridge.SchemaInfo{
+					"zones": {Default: &tfbridge.DefaultInfo{Value: []string{"a", "b"}}},
+				},
+			},
+		},
+	}
+
+	spec, err := GenerateSchema(provider, diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{
+		Color: colors.Never,
+	}))
+	require.NoError(t, err)
+
+	prop := spec.Resources["test:index:Res"].InputProperties["zones"]
+	assert.Nil(t, prop.Default)
+	assert.Equal(t, "Zones to deploy to.\nDefault value: `[\"a\",\"b\"]`.\n", prop.Description)
+	_, diags, err := pschema.BindSpec(spec, nil)
+	require.NoError(t, err)
+	assert.False(t, diags.HasErrors(), "%v", diags)
+
+	// The schema has no room for a composite default, which is why it is documented instead.
+	prop.Default = []interface{}{"a", "b"}
+	spec.Resources["test:index:Res"].InputProperties["zones"] = prop
+	_, diags, err = pschema.BindSpec(spec, nil)
+	require.NoError(t, err)
+	assert.True(t, diags.HasErrors())
+}
+
+func TestCompositeEnvDefaultInfo(t *testing.T) {
+	provider := tfbridge.ProviderInfo{
+		Name: "test",
+		P: (&shimschema.Provider{
+			Schema: shimschema.SchemaMap{
+				"region": (&shimschema.Schema{
+					Type:     shim.TypeString,
+					Optional: true,
+				}).Shim(),
+				"zones": (&shimschema.Schema{
+					Type:     shim.TypeList,
+					Optional: true,
+					Elem:     (&shimschema.Schema{Type: shim.TypeString}).Shim(),
+				}).Shim(),
+				"assume_role": (&shimschema.Schema{
+					Type:        shim.TypeList,
+					Optional:    true,
+					MaxItems:    1,
+					Description: "Role to assume.",
+					Elem: (&shimschema.Resource{
+						Schema: shimschema.SchemaMap{
+							"role_arn": (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+						},
+					}).Shim(),
+				}).Shim(),
+			},
+		}).Shim(),
+		Config: map[string]*tfbridge.SchemaInfo{
+			"region": {Default: &tfbridge.DefaultInfo{EnvVars: []string{"TEST_REGION"}}},
+			"zones":  {Default: &tfbridge.DefaultInfo{EnvVars: []string{"TEST_ZONES"}}},
+			"assume_role": {Default: &tfbridge.DefaultInfo{
+				EnvVars: []string{"TEST_ASSUME_ROLE", "TEST_ROLE"},
+			}},
+		},
+	}
+
+	spec, err := GenerateSchema(provider, diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{
+		Color: colors.Never,
+	}))
+	require.NoError(t, err)
+
+	region := spec.Config.Variables["region"]
+	require.NotNil(t, region.DefaultInfo)
+	assert.Equal(t, []string{"TEST_REGION"}, region.DefaultInfo.Environment)
+
+	zones := spec.Config.Variables["zones"]
+	assert.Nil(t, zones.DefaultInfo)
+	assert.Equal(t, "It can also be sourced from the `TEST_ZONES` environment variable, "+
+		"encoded as JSON or a comma-separated list.\n", zones.Description)
+
+	assumeRole := spec.Config.Variables["assumeRole"]
+	assert.Nil(t, assumeRole.DefaultInfo)
+	assert.Equal(t, "Role to assume.\nIt can also be sourced from the `TEST_ASSUME_ROLE` or `TEST_ROLE` "+
+		"environment variables, encoded as JSON.\n", assumeRole.Description)
+}
+
+func TestWriteOnlyProperties(t *testing.T) {
+	provider := tfbridge.ProviderInfo{
+		Name: "test",
+		P: (&shimschema.Provider{
+			ResourcesMap: shimschema.ResourceMap{
+				"test_db": (&shimschema.Resource{
+					Schema: shimschema.SchemaMap{
+						"name":     (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+						"password": (&shimschema.Schema{Type: shim.TypeString, Optional: true}).Shim(),
+					},
+				}).Shim(),
+			},
+		}).Shim(),
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"test_db": {
+				Tok: "test:index:Db",
+				Fields: map[string]*tfbridge.SchemaInfo{
+					"password": {WriteOnly: true},
+				},
+			},
+		},
+	}
+
+	spec, err := GenerateSchema(provider, diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{
+		Color: colors.Never,
+	}))
+	require.NoError(t, err)
+
+	res := spec.Resources["test:index:Db"]
+	assert.Contains(t, res.InputProperties, "password")
+	assert.NotContains(t, res.Properties, "password")
+	assert.NotContains(t, res.StateInputs.Properties, "password")
+	assert.Contains(t, res.Properties, "name")
+}
+
+func TestDefaultTagsAddsTagsAll(t *testing.T) {
+	tags := func() shim.Schema {
+		return (&shimschema.Schema{
+			Type:     shim.TypeMap,
+			Optional: true,
+			Elem:     (&shimschema.Schema{Type: shim.TypeString}).Shim(),
+		}).Shim()
+	}
+	provider := tfbridge.ProviderInfo{
+		Name: "test",
+		P: (&shimschema.Provider{
+			ResourcesMap: shimschema.ResourceMap{
+				"test_bucket": (&shimschema.Resource{
+					Schema: shimschema.SchemaMap{"tags": tags()},
+				}).Shim(),
+				"test_queue": (&shimschema.Resource{
+					Schema: shimschema.SchemaMap{"tags": tags()},
+				}).Shim(),
+			},
+		}).Shim(),
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"test_bucket": {Tok: "test:index:Bucket"},
+			"test_queue":  {Tok: "test:index:Queue"},
+		},
+		DefaultTags: &tfbridge.DefaultTagsInfo{
+			ConfigKey: "defaultTags",
+			Resources: map[string]*tfbridge.ResourceTagsInfo{"test_bucket": {}},
+		},
+	}
+
+	spec, err := GenerateSchema(provider, diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{
+		Color: colors.Never,
+	}))
+	require.NoError(t, err)
+
+	bucket := spec.Resources["test:index:Bucket"]
+	require.Contains(t, bucket.Properties, "tagsAll")
+	assert.Equal(t, "object", bucket.Properties["tagsAll"].Type)
+	assert.Equal(t, "string", bucket.Properties["tagsAll"].AdditionalProperties.Type)
+	assert.Contains(t, bucket.Required, "tagsAll")
+	assert.Contains(t, bucket.StateInputs.Properties, "tagsAll")
+	assert.NotContains(t, bucket.InputProperties, "tagsAll")
+
+	assert.NotContains(t, spec.Resources["test:index:Queue"].Properties, "tagsAll")
+}
+
 func TestRegress1626(t *testing.T) {
 	info := testprovider.ProviderMiniTalos()
 	sink := diag.DefaultSink(io.Discard, io.Discard, diag.FormatOptions{Color: colors.Never})
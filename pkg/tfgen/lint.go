@@ -0,0 +1,286 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pygen "github.com/pulumi/pulumi/pkg/v3/codegen/python"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/walk"
+)
+
+// A LintFinding is a likely mistake in the overrides of a provider, found by [Lint].
+type LintFinding struct {
+	// The TF token of the resource or data source, or "" for the provider configuration and
+	// provider-wide findings.
+	TFToken string
+	// The location of the property in the schema of TFToken, or empty for findings that are
+	// not about a property.
+	Path walk.SchemaPath
+	// A description of the finding.
+	Message string
+}
+
+func (f LintFinding) String() string {
+	var b strings.Builder
+	if f.TFToken == "" {
+		b.WriteString("provider")
+	} else {
+		b.WriteString(f.TFToken)
+	}
+	if len(f.Path) > 0 {
+		path, err := f.Path.EncodeSchemaPath()
+		if err != nil {
+			path = f.Path.GoString()
+		}
+		b.WriteString(": " + path)
+	}
+	b.WriteString(": " + f.Message)
+	return b.String()
+}
+
+// Lint reports overrides in info that are structurally valid but are likely mistakes.
+//
+// While [tfbridge.ProviderInfo.Validate] rejects overrides that cannot be applied, Lint flags
+// overrides that are inconsistent with the TF schema or that produce surprising Pulumi
+// schemas, such as defaults on computed-only fields or property names that clash. Findings
+// are sorted by TF token and path.
+func Lint(info *tfbridge.ProviderInfo) []LintFinding {
+	l := &linter{}
+
+	l.tfToken = ""
+	l.lintObject(walk.NewSchemaPath(), info.P.Schema(), info.Config)
+
+	resources := info.P.ResourcesMap()
+	for tfToken, r := range info.Resources {
+		if tf, ok := resources.GetOk(tfToken); ok && r != nil {
+			l.tfToken = tfToken
+			l.lintObject(walk.NewSchemaPath(), tf.Schema(), r.Fields)
+		}
+	}
+	dataSources := info.P.DataSourcesMap()
+	for tfToken, d := range info.DataSources {
+		if tf, ok := dataSources.GetOk(tfToken); ok && d != nil {
+			l.tfToken = tfToken
+			l.lintObject(walk.NewSchemaPath(), tf.Schema(), d.Fields)
+		}
+	}
+
+	for _, tfToken := range info.IgnoreMappings {
+		l.tfToken = tfToken
+		_, isResource := resources.GetOk(tfToken)
+		_, isDataSource := dataSources.GetOk(tfToken)
+		if !isResource && !isDataSource {
+			l.report(nil, "IgnoreMappings entry matches no resource or data source")
+		}
+		if _, ok := info.Resources[tfToken]; ok {
+			l.report(nil, "IgnoreMappings entry is mapped in Resources anyway")
+		}
+		if _, ok := info.DataSources[tfToken]; ok {
+			l.report(nil, "IgnoreMappings entry is mapped in DataSources anyway")
+		}
+	}
+
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		a, b := l.findings[i], l.findings[j]
+		if a.TFToken != b.TFToken {
+			return a.TFToken < b.TFToken
+		}
+		return a.Path.GoString() < b.Path.GoString()
+	})
+	return l.findings
+}
+
+type linter struct {
+	tfToken  string
+	findings []LintFinding
+}
+
+func (l *linter) report(path walk.SchemaPath, format string, args ...any) {
+	l.findings = append(l.findings, LintFinding{
+		TFToken: l.tfToken,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// Lint the properties of the object at path.
+func (l *linter) lintObject(path walk.SchemaPath, schemaMap shim.SchemaMap, fields map[string]*tfbridge.SchemaInfo) {
+	if schemaMap == nil {
+		return
+	}
+	var keys []string
+	schemaMap.Range(func(key string, _ shim.Schema) bool {
+		keys = append(keys, key)
+		return true
+	})
+	sort.Strings(keys)
+
+	names := map[string]string{}
+	for _, key := range keys {
+		propPath := path.GetAttr(key)
+		tfs, ps := schemaMap.Get(key), fields[key]
+		if ps != nil && ps.Omit {
+			continue
+		}
+
+		name := tfbridge.TerraformToPulumiNameV2(key, schemaMap, fields)
+		if other, ok := names[name]; ok {
+			l.report(propPath, "both %q and %q are named %q", other, key, name)
+		} else {
+			names[name] = key
+		}
+		// Go and .NET PascalCase property names and TypeScript accepts keywords as property names,
+		// so Python is the only SDK that renames properties that are reserved words.
+		if pygen.Keywords.Has(name) {
+			l.report(propPath, "%q is a reserved word in Python, where it is renamed to %q",
+				name, pygen.EnsureKeywordSafe(name))
+		}
+
+		l.lintProperty(propPath, tfs, ps)
+	}
+}
+
+// Lint the property at path and its nested properties.
+func (l *linter) lintProperty(path walk.SchemaPath, tfs shim.Schema, ps *tfbridge.SchemaInfo) {
+	if ps != nil {
+		computedOnly := tfs.Computed() && !tfs.Optional() && !tfs.Required()
+		if ps.Default != nil && computedOnly {
+			l.report(path, "DefaultInfo has no effect on a computed-only field")
+		}
+		if ps.Default != nil && ps.Default.AutoNamed && tfs.Type() != shim.TypeString {
+			l.report(path, "AutoName requires a string field, found %v", tfs.Type())
+		}
+		if ps.Secret != nil && !*ps.Secret && tfs.Sensitive() {
+			l.report(path, "Secret is disabled on a field that is Sensitive in TF")
+		}
+		if ps.Asset != nil && tfs.Type() != shim.TypeString {
+			l.report(path, "Asset translation requires a string field, found %v", tfs.Type())
+		}
+		for _, t := range append([]tokens.Type{ps.Type}, ps.AltTypes...) {
+			if t != "" && !typeOverrideCompatible(t, tfs, ps) {
+				l.report(path, "type override %q is incompatible with the TF type %v", t, tfs.Type())
+			}
+		}
+	}
+
+	var elemInfo *tfbridge.SchemaInfo
+	if ps != nil {
+		elemInfo = ps.Elem
+	}
+	switch elem := tfs.Elem().(type) {
+	case shim.Resource:
+		if tfs.Type() == shim.TypeMap {
+			// A single-nested block: its fields are specified directly on ps.
+			var fields map[string]*tfbridge.SchemaInfo
+			if ps != nil {
+				fields = ps.Fields
+			}
+			l.lintObject(path, elem.Schema(), fields)
+			return
+		}
+		var fields map[string]*tfbridge.SchemaInfo
+		if elemInfo != nil {
+			fields = elemInfo.Fields
+		}
+		l.lintObject(path.Element(), elem.Schema(), fields)
+	case shim.Schema:
+		l.lintProperty(path.Element(), elem, elemInfo)
+	}
+}
+
+// The shape of a value, as far as type overrides are concerned.
+type overrideShape int
+
+const (
+	shapeUnknown overrideShape = iota
+	shapeScalar
+	shapeArray
+	shapeObject
+)
+
+// Check that the type override t can describe values of the TF type of tfs.
+//
+// Overrides that reference a schema type, such as an enum or an object type, are assumed to
+// match any scalar or object. Primitive overrides may differ from the TF type, since the
+// bridge converts between scalars.
+func typeOverrideCompatible(t tokens.Type, tfs shim.Schema, ps *tfbridge.SchemaInfo) bool {
+	var tfShape overrideShape
+	switch tfs.Type() {
+	case shim.TypeBool, shim.TypeInt, shim.TypeFloat, shim.TypeString:
+		tfShape = shapeScalar
+	case shim.TypeList, shim.TypeSet:
+		tfShape = shapeArray
+		if tfbridge.IsMaxItemsOne(tfs, ps) {
+			tfShape = shapeUnknown
+		}
+	case shim.TypeMap:
+		tfShape = shapeObject
+	}
+
+	var overrideShape overrideShape
+	switch {
+	case strings.HasSuffix(string(t), "[]"):
+		overrideShape = shapeArray
+	case tokens.Token(t).Simple():
+		switch t {
+		case "string", "integer", "number", "boolean":
+			overrideShape = shapeScalar
+		case "array":
+			overrideShape = shapeArray
+		case "object":
+			overrideShape = shapeObject
+		}
+	default:
+		// A reference to an enum or object type.
+		if tfShape == shapeArray {
+			return false
+		}
+	}
+
+	return tfShape == shapeUnknown || overrideShape == shapeUnknown || tfShape == overrideShape
+}
+
+func newLintCmd(prov tfbridge.ProviderInfo) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint",
+		Args:  cobra.NoArgs,
+		Short: "Report likely mistakes in the provider mapping",
+		Long: "Report likely mistakes in the provider mapping.\n" +
+			"\n" +
+			"Lint checks the overrides of the provider against the Terraform schema, reporting\n" +
+			"overrides that are valid but have no effect or produce surprising Pulumi schemas.\n" +
+			"Each finding is printed with the Terraform token and schema path it applies to.\n",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			findings := Lint(&prov)
+			for _, f := range findings {
+				fmt.Fprintln(cmd.OutOrStdout(), f.String())
+			}
+			if len(findings) > 0 {
+				return fmt.Errorf("found %d lint findings", len(findings))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+func lintTestProvider() tfbridge.ProviderInfo {
+	str := (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim()
+	num := (&schema.Schema{Type: shim.TypeInt, Optional: true}).Shim()
+	computed := (&schema.Schema{Type: shim.TypeString, Computed: true}).Shim()
+	sensitive := (&schema.Schema{Type: shim.TypeString, Optional: true, Sensitive: true}).Shim()
+	list := (&schema.Schema{Type: shim.TypeList, Optional: true, Elem: str}).Shim()
+	block := (&schema.Schema{
+		Type:     shim.TypeList,
+		Optional: true,
+		Elem:     (&schema.Resource{Schema: schema.SchemaMap{"count": num, "lambda": str}}).Shim(),
+	}).Shim()
+
+	return tfbridge.ProviderInfo{
+		P: (&schema.Provider{
+			Schema: schema.SchemaMap{"token": sensitive},
+			ResourcesMap: schema.ResourceMap{
+				"pkg_res": (&schema.Resource{Schema: schema.SchemaMap{
+					"name":     str,
+					"other":    str,
+					"endpoint": computed,
+					"size":     num,
+					"code":     num,
+					"tags":     list,
+					"block":    block,
+				}}).Shim(),
+			},
+			DataSourcesMap: schema.ResourceMap{
+				"pkg_data": (&schema.Resource{Schema: schema.SchemaMap{"from": str}}).Shim(),
+			},
+		}).Shim(),
+		Config: map[string]*tfbridge.SchemaInfo{
+			"token": {Secret: tfbridge.False()},
+		},
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"pkg_res": {
+				Tok: "pkg:index:Res",
+				Fields: map[string]*tfbridge.SchemaInfo{
+					"name":     tfbridge.AutoName("name", 10, "-"),
+					"other":    {Name: "name"},
+					"endpoint": {Default: &tfbridge.DefaultInfo{Value: "e"}},
+					"size":     {Default: &tfbridge.DefaultInfo{AutoNamed: true}},
+					"code":     {Asset: &tfbridge.AssetTranslation{Kind: tfbridge.FileAsset}},
+					"tags":     {Type: "string", AltTypes: []tokens.Type{"pkg:index/tag:Tag[]"}},
+					"block": {Elem: &tfbridge.SchemaInfo{Fields: map[string]*tfbridge.SchemaInfo{
+						"count": {Type: "pkg:index/count:Count[]"},
+					}}},
+				},
+			},
+		},
+		DataSources: map[string]*tfbridge.DataSourceInfo{
+			"pkg_data": {Tok: "pkg:index:getData"},
+		},
+		IgnoreMappings: []string{"pkg_res", "pkg_removed"},
+	}
+}
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+	prov := lintTestProvider()
+
+	var findings []string
+	for _, f := range Lint(&prov) {
+		findings = append(findings, f.String())
+	}
+	assert.Equal(t, []string{
+		`provider: token: Secret is disabled on a field that is Sensitive in TF`,
+		`pkg_data: from: "from" is a reserved word in Python, where it is renamed to "from_"`,
+		`pkg_removed: IgnoreMappings entry matches no resource or data source`,
+		`pkg_res: IgnoreMappings entry is mapped in Resources anyway`,
+		`pkg_res: block.$.count: type override "pkg:index/count:Count[]" is incompatible with the TF type Int`,
+		`pkg_res: block.$.lambda: "lambda" is a reserved word in Python, where it is renamed to "lambda_"`,
+		`pkg_res: code: Asset translation requires a string field, found Int`,
+		`pkg_res: endpoint: DefaultInfo has no effect on a computed-only field`,
+		`pkg_res: other: both "name" and "other" are named "name"`,
+		`pkg_res: size: AutoName requires a string field, found Int`,
+		`pkg_res: tags: type override "string" is incompatible with the TF type List`,
+	}, findings)
+}
+
+func TestLintCmd(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	cmd := newLintCmd(lintTestProvider())
+	cmd.SetOut(&out)
+	cmd.SetArgs(nil)
+	err := cmd.Execute()
+	require.ErrorContains(t, err, "found 11 lint findings")
+	assert.Contains(t, out.String(), "pkg_res: size: AutoName requires a string field, found Int\n")
+
+	clean := lintTestProvider()
+	clean.Config, clean.Resources, clean.DataSources, clean.IgnoreMappings = nil, nil, nil, nil
+	clean.P = (&schema.Provider{}).Shim()
+	out.Reset()
+	cmd = newLintCmd(clean)
+	cmd.SetOut(&out)
+	cmd.SetArgs(nil)
+	require.NoError(t, cmd.Execute())
+	assert.Empty(t, out.String())
+}
@@ -149,16 +149,25 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo,
 			coverageOutputDir, coverageTrackingOutputEnabled := os.LookupEnv("COVERAGE_OUTPUT_DIR")
 			coverageTracker = newCoverageTracker(prov.Name, prov.Version)
 
+			// Creating a per-entity documentation quality report if the
+			// DOCS_QUALITY_OUTPUT_DIR env is set
+			var docsQuality *DocsQualityReport
+			docsQualityOutputDir, docsQualityOutputEnabled := os.LookupEnv("DOCS_QUALITY_OUTPUT_DIR")
+			if docsQualityOutputEnabled {
+				docsQuality = newDocsQualityReport(prov.Name, prov.Version)
+			}
+
 			opts := GeneratorOptions{
-				Package:         pkg,
-				Version:         version,
-				Language:        Language(args[0]),
-				ProviderInfo:    prov,
-				Root:            root,
-				Debug:           debug,
-				SkipDocs:        skipDocs,
-				SkipExamples:    skipExamples,
-				CoverageTracker: coverageTracker,
+				Package:           pkg,
+				Version:           version,
+				Language:          Language(args[0]),
+				ProviderInfo:      prov,
+				Root:              root,
+				Debug:             debug,
+				SkipDocs:          skipDocs,
+				SkipExamples:      skipExamples,
+				CoverageTracker:   coverageTracker,
+				DocsQualityReport: docsQuality,
 			}
 
 			err := gen(opts)
@@ -172,6 +181,13 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo,
 			fmt.Println(coverageTracker.getShortResultSummary())
 			printDocStats()
 
+			// Exporting the documentation quality report to the directory specified by DOCS_QUALITY_OUTPUT_DIR
+			if docsQualityOutputEnabled {
+				if qualityErr := docsQuality.exportResults(docsQualityOutputDir); err == nil {
+					err = qualityErr
+				}
+			}
+
 			return err
 		}),
 		PersistentPostRun: func(cmd *cobra.Command, args []string) {
@@ -206,5 +222,9 @@ func newTFGenCmd(pkg string, version string, prov tfbridge.ProviderInfo,
 	err := cmd.PersistentFlags().MarkHidden("overlays")
 	contract.AssertNoErrorf(err, "err != nil")
 
+	cmd.AddCommand(newLintCmd(prov))
+	cmd.AddCommand(newImportTFStateCmd(prov))
+	cmd.CompletionOptions.DisableDefaultCmd = true
+
 	return cmd
 }
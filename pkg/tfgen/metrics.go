@@ -16,6 +16,7 @@ package tfgen
 
 import (
 	"fmt"
+	"sync"
 
 	schemaTools "github.com/pulumi/schema-tools/pkg"
 )
@@ -33,8 +34,25 @@ var (
 	entitiesMissingDocs                int
 
 	schemaStats schemaTools.PulumiSchemaStats
+
+	// Guards the metrics above, which are updated while gathering entities concurrently.
+	metricsMu sync.Mutex
 )
 
+// countMetric increments the metric m.
+func countMetric(m *int) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	*m++
+}
+
+// countIgnoredDocHeader records that a doc section with header was ignored.
+func countIgnoredDocHeader(header string) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	ignoredDocHeaders[header]++
+}
+
 // printDocStats outputs metrics relating to document parsing and conversion
 func printDocStats() {
 	fmt.Println("General metrics:")
@@ -0,0 +1,260 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+)
+
+// An ImportFile is a document accepted by `pulumi import --file`.
+type ImportFile struct {
+	// Names referenced by Parent and Provider of the resources, mapped to the URNs of existing
+	// resources.
+	NameTable map[string]resource.URN `json:"nameTable,omitempty"`
+	// The resources to import.
+	Resources []ImportSpec `json:"resources"`
+}
+
+// An ImportSpec describes a resource to import in an [ImportFile].
+type ImportSpec struct {
+	Type     tokens.Type `json:"type"`
+	Name     string      `json:"name"`
+	ID       resource.ID `json:"id"`
+	Parent   string      `json:"parent,omitempty"`
+	Provider string      `json:"provider,omitempty"`
+}
+
+// Options for [ConvertTerraformState].
+type TerraformStateOptions struct {
+	// The URN of an existing resource to parent the imported resources to, if any.
+	ParentURN resource.URN
+	// The URN of an existing provider to import the resources with, if any. The default
+	// provider is used otherwise.
+	ProviderURN resource.URN
+}
+
+const (
+	importParentName   = "parent"
+	importProviderName = "provider"
+)
+
+// The subset of the Terraform state format, version 4, read by ConvertTerraformState.
+type tfState struct {
+	Version   int `json:"version"`
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   any            `json:"index_key"`
+			Deposed    string         `json:"deposed"`
+			Attributes map[string]any `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+}
+
+// ConvertTerraformState reads a Terraform state file (version 4) and returns the import file
+// that imports its resources into Pulumi.
+//
+// Each instance of a managed resource mapped in info.Resources becomes an [ImportSpec]. Data
+// sources, deposed instances and resources of other providers are skipped. The ID of each
+// resource is computed by [tfbridge.ResourceInfo.ComputeID] if set, or else read from the "id"
+// attribute, as the provider does when it reads the resource. Logical names are derived from
+// the module path, name and index of the Terraform resource.
+func ConvertTerraformState(
+	ctx context.Context, info *tfbridge.ProviderInfo, state io.Reader, opts TerraformStateOptions,
+) (*ImportFile, error) {
+	var st tfState
+	if err := json.NewDecoder(state).Decode(&st); err != nil {
+		return nil, fmt.Errorf("reading Terraform state: %w", err)
+	}
+	if st.Version != 4 {
+		return nil, fmt.Errorf("unsupported Terraform state version %d, expected 4", st.Version)
+	}
+
+	file := &ImportFile{Resources: []ImportSpec{}}
+	var parent, provider string
+	if opts.ParentURN != "" || opts.ProviderURN != "" {
+		file.NameTable = map[string]resource.URN{}
+	}
+	if opts.ParentURN != "" {
+		file.NameTable[importParentName] = opts.ParentURN
+		parent = importParentName
+	}
+	if opts.ProviderURN != "" {
+		file.NameTable[importProviderName] = opts.ProviderURN
+		provider = importProviderName
+	}
+
+	resources := info.P.ResourcesMap()
+	names := map[tokens.Type]map[string]bool{}
+	for _, r := range st.Resources {
+		res, ok := info.Resources[r.Type]
+		if r.Mode != "managed" || !ok || res == nil || res.Tok == "" {
+			continue
+		}
+		tf, ok := resources.GetOk(r.Type)
+		if !ok {
+			continue
+		}
+		for _, inst := range r.Instances {
+			if inst.Deposed != "" {
+				continue
+			}
+			address := tfAddress(r.Module, r.Type, r.Name, inst.IndexKey)
+			id, err := tfStateID(ctx, info, res, tf.Schema(), inst.Attributes)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", address, err)
+			}
+			if names[res.Tok] == nil {
+				names[res.Tok] = map[string]bool{}
+			}
+			file.Resources = append(file.Resources, ImportSpec{
+				Type:     res.Tok,
+				Name:     uniqueName(names[res.Tok], importName(r.Module, r.Name, inst.IndexKey)),
+				ID:       id,
+				Parent:   parent,
+				Provider: provider,
+			})
+		}
+	}
+	return file, nil
+}
+
+func tfStateID(
+	ctx context.Context, info *tfbridge.ProviderInfo, res *tfbridge.ResourceInfo,
+	schemaMap shim.SchemaMap, attributes map[string]any,
+) (resource.ID, error) {
+	switch {
+	case res.ComputeID != nil:
+		state := tfbridge.MakeTerraformOutputs(ctx, jsonValues{info.P}, attributes, schemaMap, res.Fields,
+			nil, false, false)
+		return res.ComputeID(ctx, state)
+	default:
+		id, ok := attributes["id"].(string)
+		if !ok || id == "" {
+			return "", fmt.Errorf("no id attribute, consider setting ResourceInfo.ComputeID")
+		}
+		return resource.ID(id), nil
+	}
+}
+
+// Adapts a schema-only provider to convert values decoded from JSON, which never contain sets.
+type jsonValues struct{ shim.Provider }
+
+func (jsonValues) IsSet(context.Context, interface{}) ([]interface{}, bool) { return nil, false }
+
+// The Terraform address of a resource instance, for error messages.
+func tfAddress(module, typ, name string, indexKey any) string {
+	address := typ + "." + name
+	if module != "" {
+		address = module + "." + address
+	}
+	switch k := indexKey.(type) {
+	case nil:
+	case string:
+		address += fmt.Sprintf("[%q]", k)
+	default:
+		address += fmt.Sprintf("[%v]", k)
+	}
+	return address
+}
+
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9-]+`)
+
+// The logical name of a resource instance: its module path, name and index joined with "_".
+//
+// For example, module.network["a"].aws_vpc.main[0] is named network_a_main_0.
+func importName(module, name string, indexKey any) string {
+	var parts []string
+	if module != "" {
+		module = strings.ReplaceAll(strings.TrimPrefix(module, "module."), ".module.", ".")
+		parts = append(parts, module)
+	}
+	parts = append(parts, name)
+	if indexKey != nil {
+		parts = append(parts, fmt.Sprint(indexKey))
+	}
+	return strings.Trim(invalidNameChars.ReplaceAllString(strings.Join(parts, "_"), "_"), "_")
+}
+
+// Make name unique among taken by appending a counter, and take it.
+func uniqueName(taken map[string]bool, name string) string {
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	taken[unique] = true
+	return unique
+}
+
+func newImportTFStateCmd(prov tfbridge.ProviderInfo) *cobra.Command {
+	var opts TerraformStateOptions
+	var parent, provider, file string
+	cmd := &cobra.Command{
+		Use:   "import-tfstate <TFSTATE>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Convert a Terraform state file into a Pulumi import file",
+		Long: "Convert a Terraform state file into a Pulumi import file.\n" +
+			"\n" +
+			"The managed resources of this provider in <TFSTATE>, a terraform.tfstate file, are\n" +
+			"written as a JSON document that can be passed to `pulumi import --file`.\n",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer contract.IgnoreClose(f)
+
+			opts.ParentURN, opts.ProviderURN = resource.URN(parent), resource.URN(provider)
+			importFile, err := ConvertTerraformState(cmd.Context(), &prov, f, opts)
+			if err != nil {
+				return err
+			}
+
+			bytes, err := json.MarshalIndent(importFile, "", "    ")
+			if err != nil {
+				return err
+			}
+			bytes = append(bytes, '\n')
+			if file != "" {
+				return os.WriteFile(file, bytes, 0o600)
+			}
+			_, err = cmd.OutOrStdout().Write(bytes)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&parent, "parent", "", "The URN of an existing resource to parent the imported resources to")
+	cmd.Flags().StringVar(&provider, "provider", "", "The URN of an existing provider to import the resources with")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Write the import file to this path instead of stdout")
+	return cmd
+}
@@ -0,0 +1,193 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tfgen
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfbridge"
+	shim "github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/schema"
+)
+
+const testTFState = `{
+  "version": 4,
+  "terraform_version": "1.5.7",
+  "resources": [
+    {
+      "mode": "managed",
+      "type": "pkg_vpc",
+      "name": "main",
+      "provider": "provider[\"registry.terraform.io/hashicorp/pkg\"]",
+      "instances": [
+        {"index_key": 0, "attributes": {"id": "vpc-0", "cidr_block": "10.0.0.0/16"}},
+        {"index_key": 1, "attributes": {"id": "vpc-1", "cidr_block": "10.1.0.0/16"}},
+        {"deposed": "abc", "attributes": {"id": "vpc-old"}}
+      ]
+    },
+    {
+      "module": "module.network[\"west\"]",
+      "mode": "managed",
+      "type": "pkg_vpc",
+      "name": "main",
+      "instances": [{"attributes": {"id": "vpc-west"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "pkg_vpc",
+      "name": "main_0",
+      "instances": [{"attributes": {"id": "vpc-dup"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "pkg_route",
+      "name": "r",
+      "instances": [{"attributes": {"id": "r-rtb-1-1080289494", "route_table_id": "rtb-1", "destination": "0.0.0.0/0"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "pkg_bytes",
+      "name": "b",
+      "instances": [{"attributes": {"base64": "aGk="}}]
+    },
+    {
+      "mode": "data",
+      "type": "pkg_vpc",
+      "name": "existing",
+      "instances": [{"attributes": {"id": "vpc-data"}}]
+    },
+    {
+      "mode": "managed",
+      "type": "other_thing",
+      "name": "x",
+      "instances": [{"attributes": {"id": "other"}}]
+    }
+  ]
+}`
+
+func tfStateTestProvider() tfbridge.ProviderInfo {
+	str := (&schema.Schema{Type: shim.TypeString, Optional: true}).Shim()
+	return tfbridge.ProviderInfo{
+		P: (&schema.Provider{
+			ResourcesMap: schema.ResourceMap{
+				"pkg_vpc": (&schema.Resource{Schema: schema.SchemaMap{
+					"cidr_block": str,
+				}}).Shim(),
+				"pkg_route": (&schema.Resource{Schema: schema.SchemaMap{
+					"route_table_id": str,
+					"destination":    str,
+				}}).Shim(),
+				"pkg_bytes": (&schema.Resource{Schema: schema.SchemaMap{
+					"base64": str,
+				}}).Shim(),
+			},
+		}).Shim(),
+		Resources: map[string]*tfbridge.ResourceInfo{
+			"pkg_vpc":   {Tok: "pkg:index/vpc:Vpc"},
+			"pkg_route": {Tok: "pkg:index/route:Route"},
+			"pkg_bytes": {
+				Tok:       "pkg:index/bytes:Bytes",
+				ComputeID: tfbridge.DelegateIDField("base64", "pkg", "https://example.com"),
+			},
+		},
+	}
+}
+
+func TestConvertTerraformState(t *testing.T) {
+	t.Parallel()
+	prov := tfStateTestProvider()
+
+	file, err := ConvertTerraformState(context.Background(), &prov, strings.NewReader(testTFState),
+		TerraformStateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, &ImportFile{Resources: []ImportSpec{
+		{Type: "pkg:index/vpc:Vpc", Name: "main_0", ID: "vpc-0"},
+		{Type: "pkg:index/vpc:Vpc", Name: "main_1", ID: "vpc-1"},
+		{Type: "pkg:index/vpc:Vpc", Name: "network_west_main", ID: "vpc-west"},
+		{Type: "pkg:index/vpc:Vpc", Name: "main_0_2", ID: "vpc-dup"},
+		{Type: "pkg:index/route:Route", Name: "r", ID: "r-rtb-1-1080289494"},
+		{Type: "pkg:index/bytes:Bytes", Name: "b", ID: "aGk="},
+	}}, file)
+
+	file, err = ConvertTerraformState(context.Background(), &prov, strings.NewReader(testTFState),
+		TerraformStateOptions{
+			ParentURN:   "urn:pulumi:dev::proj::my:Component::c",
+			ProviderURN: "urn:pulumi:dev::proj::pulumi:providers:pkg::p",
+		})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]resource.URN{
+		"parent":   "urn:pulumi:dev::proj::my:Component::c",
+		"provider": "urn:pulumi:dev::proj::pulumi:providers:pkg::p",
+	}, file.NameTable)
+	for _, r := range file.Resources {
+		assert.Equal(t, "parent", r.Parent)
+		assert.Equal(t, "provider", r.Provider)
+	}
+}
+
+func TestConvertTerraformStateErrors(t *testing.T) {
+	t.Parallel()
+	prov := tfStateTestProvider()
+
+	_, err := ConvertTerraformState(context.Background(), &prov, strings.NewReader(`{"version": 3}`),
+		TerraformStateOptions{})
+	assert.ErrorContains(t, err, "unsupported Terraform state version 3")
+
+	_, err = ConvertTerraformState(context.Background(), &prov, strings.NewReader(`{
+	  "version": 4,
+	  "resources": [{"module": "module.m", "mode": "managed", "type": "pkg_vpc", "name": "v",
+	    "instances": [{"index_key": "k", "attributes": {"cidr_block": "10.0.0.0/16"}}]}]
+	}`), TerraformStateOptions{})
+	assert.ErrorContains(t, err, `module.m.pkg_vpc.v["k"]: no id attribute`)
+}
+
+func TestImportTFStateCmd(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "terraform.tfstate")
+	require.NoError(t, os.WriteFile(statePath, []byte(testTFState), 0o600))
+
+	var out bytes.Buffer
+	cmd := newImportTFStateCmd(tfStateTestProvider())
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{statePath, "--provider", "urn:pulumi:dev::proj::pulumi:providers:pkg::p"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), `"nameTable": {
+        "provider": "urn:pulumi:dev::proj::pulumi:providers:pkg::p"
+    }`)
+	assert.Contains(t, out.String(), `{
+            "type": "pkg:index/vpc:Vpc",
+            "name": "main_0",
+            "id": "vpc-0",
+            "provider": "provider"
+        }`)
+
+	importPath := filepath.Join(dir, "import.json")
+	cmd = newImportTFStateCmd(tfStateTestProvider())
+	cmd.SetArgs([]string{statePath, "--file", importPath})
+	require.NoError(t, cmd.Execute())
+	written, err := os.ReadFile(importPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(written), `"id": "r-rtb-1-1080289494"`)
+}
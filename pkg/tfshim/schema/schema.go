@@ -30,6 +30,7 @@ type Schema struct {
 	Removed       string
 	Deprecated    string
 	Sensitive     bool
+	WriteOnly     bool
 }
 
 func (s *Schema) Shim() shim.Schema {
@@ -41,6 +42,8 @@ type SchemaShim struct {
 	V *Schema
 }
 
+var _ shim.SchemaWithWriteOnly = SchemaShim{}
+
 func (s SchemaShim) Type() shim.ValueType {
 	return s.V.Type
 }
@@ -125,6 +128,10 @@ func (s SchemaShim) Sensitive() bool {
 	return s.V.Sensitive
 }
 
+func (s SchemaShim) WriteOnly() bool {
+	return s.V.WriteOnly
+}
+
 func (s SchemaShim) UnknownValue() interface{} {
 	return UnknownVariableValue
 }
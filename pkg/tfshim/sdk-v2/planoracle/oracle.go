@@ -0,0 +1,389 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package planoracle plans and applies changes to resources of a tfprotov5 provider server in process, following
+// the semantics of Terraform CLI. It is meant to stand in for `terraform plan` and `terraform apply` in tests that
+// compare bridged providers with Terraform, so that they can run without a Terraform binary.
+//
+// The oracle models `terraform plan -refresh=false` for a single resource instance: configuration is decoded from
+// JSON Configuration Syntax with the resource schema, merged with the prior state using Terraform's ProposedNew,
+// and planned with PlanResourceChange. Provider configuration, refresh, state upgrades, data sources,
+// create_before_destroy and plan validity checks are not modeled.
+package planoracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2/internal/tf/configs/configschema"
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2/internal/tf/plans/objchange"
+)
+
+// Oracle drives a tfprotov5 provider server the way Terraform CLI does.
+type Oracle struct {
+	server    tfprotov5.ProviderServer
+	resources map[string]*configschema.Block
+}
+
+// New fetches the schema of server and configures it with an empty provider configuration.
+func New(ctx context.Context, server tfprotov5.ProviderServer) (*Oracle, error) {
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		return nil, err
+	}
+	if err := diagnosticsError("GetProviderSchema", schemaResp.Diagnostics); err != nil {
+		return nil, err
+	}
+
+	o := &Oracle{server: server, resources: map[string]*configschema.Block{}}
+	for name, s := range schemaResp.ResourceSchemas {
+		o.resources[name] = convertBlock(s.Block)
+	}
+
+	var providerBlock *configschema.Block
+	if schemaResp.Provider != nil {
+		providerBlock = convertBlock(schemaResp.Provider.Block)
+	} else {
+		providerBlock = &configschema.Block{}
+	}
+	config, err := decodeConfig(providerBlock, map[string]any{})
+	if err != nil {
+		return nil, fmt.Errorf("decoding provider config: %w", err)
+	}
+	dv, err := encodeValue(config)
+	if err != nil {
+		return nil, err
+	}
+	configureResp, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{
+		TerraformVersion: "1.3.9",
+		Config:           dv,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := diagnosticsError("ConfigureProvider", configureResp.Diagnostics); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Resource starts tracking a new resource instance of type typeName, with no prior state.
+func (o *Oracle) Resource(typeName string) (*Resource, error) {
+	block, ok := o.resources[typeName]
+	if !ok {
+		return nil, fmt.Errorf("unknown resource type %q", typeName)
+	}
+	return &Resource{
+		oracle:   o,
+		typeName: typeName,
+		schema:   block,
+		state:    cty.NullVal(block.ImpliedType()),
+	}, nil
+}
+
+// Resource is a resource instance whose state is carried from one apply to the next.
+type Resource struct {
+	oracle   *Oracle
+	typeName string
+	schema   *configschema.Block
+	state    cty.Value
+	private  []byte
+}
+
+// State returns the current state of the resource, which is null before the first apply and after a delete.
+func (r *Resource) State() cty.Value {
+	return r.state
+}
+
+// Plan is a planned change to a resource.
+type Plan struct {
+	// The actions Terraform would take, as reported in resource_changes[].change.actions by
+	// `terraform show -json`: ["no-op"], ["create"], ["update"], ["delete"] or ["delete", "create"].
+	Actions []string
+
+	// The paths that force a replacement, for ["delete", "create"].
+	ReplacePaths []cty.Path
+
+	Prior   cty.Value
+	Planned cty.Value
+	Config  cty.Value
+
+	resource *Resource
+
+	// The prior state passed to ApplyResourceChange, which is null for the create half of a replacement.
+	applyPrior    cty.Value
+	replacedState cty.Value
+	plannedPriv   []byte
+}
+
+// Plan plans the change from the current state of r to config, as `terraform plan -refresh=false` would.
+//
+// config is the body of the resource in JSON Configuration Syntax, for example a map[string]any that marshals to
+// the value of "example" in {"resource": {"type": {"example": ...}}}. A nil config plans the deletion of r.
+//
+// See https://developer.hashicorp.com/terraform/language/syntax/json
+func (r *Resource) Plan(ctx context.Context, config any) (*Plan, error) {
+	prior := r.state
+	p := &Plan{
+		Prior:         prior,
+		resource:      r,
+		applyPrior:    prior,
+		replacedState: cty.NullVal(r.schema.ImpliedType()),
+	}
+
+	if config == nil {
+		p.Config = cty.NullVal(r.schema.ImpliedType())
+		p.Planned = p.Config
+		if prior.IsNull() {
+			p.Actions = []string{"no-op"}
+		} else {
+			p.Actions = []string{"delete"}
+		}
+		return p, nil
+	}
+
+	configVal, err := decodeConfig(r.schema, config)
+	if err != nil {
+		return nil, err
+	}
+	p.Config = configVal
+
+	if err := r.validate(ctx, configVal); err != nil {
+		return nil, err
+	}
+
+	planned, private, requiresReplace, err := r.planResourceChange(ctx, prior, configVal)
+	if err != nil {
+		return nil, err
+	}
+	p.Planned, p.plannedPriv = planned, private
+
+	switch {
+	case prior.IsNull():
+		p.Actions = []string{"create"}
+	default:
+		p.ReplacePaths = changedPaths(requiresReplace, prior, planned)
+		switch {
+		case len(p.ReplacePaths) > 0:
+			// Terraform plans the create half of a replacement as a create from a null prior state.
+			p.Actions = []string{"delete", "create"}
+			p.replacedState = prior
+			p.applyPrior = cty.NullVal(r.schema.ImpliedType())
+			p.Planned, p.plannedPriv, _, err = r.planResourceChange(ctx, p.applyPrior, configVal)
+			if err != nil {
+				return nil, err
+			}
+		case equal(planned, prior):
+			p.Actions = []string{"no-op"}
+		default:
+			p.Actions = []string{"update"}
+		}
+	}
+	return p, nil
+}
+
+// Apply applies p, as `terraform apply` of a saved plan would, and updates the state of the resource.
+func (r *Resource) Apply(ctx context.Context, p *Plan) error {
+	if p.resource != r {
+		return fmt.Errorf("plan was made for a different resource")
+	}
+	if !equal(p.Prior, r.state) {
+		return fmt.Errorf("stale plan: the state of the resource changed since it was planned")
+	}
+
+	switch strings.Join(p.Actions, ",") {
+	case "no-op":
+		return nil
+	case "delete":
+		return r.applyResourceChange(ctx, r.state, p.Planned, p.Config, nil)
+	case "delete,create":
+		null := cty.NullVal(r.schema.ImpliedType())
+		if err := r.applyResourceChange(ctx, p.replacedState, null, null, nil); err != nil {
+			return err
+		}
+		return r.applyResourceChange(ctx, p.applyPrior, p.Planned, p.Config, p.plannedPriv)
+	default:
+		return r.applyResourceChange(ctx, p.applyPrior, p.Planned, p.Config, p.plannedPriv)
+	}
+}
+
+func (r *Resource) validate(ctx context.Context, config cty.Value) error {
+	dv, err := encodeValue(config)
+	if err != nil {
+		return err
+	}
+	resp, err := r.oracle.server.ValidateResourceTypeConfig(ctx, &tfprotov5.ValidateResourceTypeConfigRequest{
+		TypeName: r.typeName,
+		Config:   dv,
+	})
+	if err != nil {
+		return err
+	}
+	return diagnosticsError("ValidateResourceTypeConfig", resp.Diagnostics)
+}
+
+func (r *Resource) planResourceChange(
+	ctx context.Context, prior, config cty.Value,
+) (cty.Value, []byte, []*tftypes.AttributePath, error) {
+	proposed := objchange.ProposedNew(r.schema, prior, config)
+
+	var req tfprotov5.PlanResourceChangeRequest
+	req.TypeName = r.typeName
+	for _, v := range []struct {
+		dst **tfprotov5.DynamicValue
+		val cty.Value
+	}{{&req.PriorState, prior}, {&req.ProposedNewState, proposed}, {&req.Config, config}} {
+		dv, err := encodeValue(v.val)
+		if err != nil {
+			return cty.NilVal, nil, nil, err
+		}
+		*v.dst = dv
+	}
+	if !prior.IsNull() {
+		req.PriorPrivate = r.private
+	}
+
+	resp, err := r.oracle.server.PlanResourceChange(ctx, &req)
+	if err != nil {
+		return cty.NilVal, nil, nil, err
+	}
+	if err := diagnosticsError("PlanResourceChange", resp.Diagnostics); err != nil {
+		return cty.NilVal, nil, nil, err
+	}
+	planned, err := decodeValue(resp.PlannedState, r.schema.ImpliedType())
+	if err != nil {
+		return cty.NilVal, nil, nil, err
+	}
+	return planned, resp.PlannedPrivate, resp.RequiresReplace, nil
+}
+
+func (r *Resource) applyResourceChange(ctx context.Context, prior, planned, config cty.Value, private []byte) error {
+	var req tfprotov5.ApplyResourceChangeRequest
+	req.TypeName = r.typeName
+	req.PlannedPrivate = private
+	for _, v := range []struct {
+		dst **tfprotov5.DynamicValue
+		val cty.Value
+	}{{&req.PriorState, prior}, {&req.PlannedState, planned}, {&req.Config, config}} {
+		dv, err := encodeValue(v.val)
+		if err != nil {
+			return err
+		}
+		*v.dst = dv
+	}
+
+	resp, err := r.oracle.server.ApplyResourceChange(ctx, &req)
+	if err != nil {
+		return err
+	}
+	if err := diagnosticsError("ApplyResourceChange", resp.Diagnostics); err != nil {
+		return err
+	}
+	state, err := decodeValue(resp.NewState, r.schema.ImpliedType())
+	if err != nil {
+		return err
+	}
+	r.state, r.private = state, resp.Private
+	return nil
+}
+
+// changedPaths returns the paths of requiresReplace whose value differs between prior and planned, following
+// Terraform core: a provider may report a path as forcing replacement even though it did not change.
+func changedPaths(requiresReplace []*tftypes.AttributePath, prior, planned cty.Value) []cty.Path {
+	var changed []cty.Path
+	for _, ap := range requiresReplace {
+		path := convertPath(ap)
+		priorV, priorErr := path.Apply(prior)
+		plannedV, plannedErr := path.Apply(planned)
+		switch {
+		case priorErr != nil && plannedErr != nil:
+			continue
+		case priorErr != nil:
+			priorV = cty.NullVal(plannedV.Type())
+		case plannedErr != nil:
+			plannedV = cty.NullVal(priorV.Type())
+		}
+		if !equal(priorV, plannedV) {
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+func equal(a, b cty.Value) bool {
+	eq := a.Equals(b)
+	return eq.IsKnown() && eq.True()
+}
+
+// decodeConfig decodes a resource body in JSON Configuration Syntax with the decoder spec Terraform uses.
+func decodeConfig(block *configschema.Block, config any) (cty.Value, error) {
+	src, err := json.Marshal(config)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	file, diags := hcljson.Parse(src, "config.tf.json")
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	val, diags := hcldec.Decode(file.Body, block.DecoderSpec(), &hcl.EvalContext{})
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	return val, nil
+}
+
+func encodeValue(v cty.Value) (*tfprotov5.DynamicValue, error) {
+	b, err := ctymsgpack.Marshal(v, v.Type())
+	if err != nil {
+		return nil, err
+	}
+	return &tfprotov5.DynamicValue{MsgPack: b}, nil
+}
+
+func decodeValue(dv *tfprotov5.DynamicValue, ty cty.Type) (cty.Value, error) {
+	switch {
+	case dv == nil:
+		return cty.NullVal(ty), nil
+	case len(dv.MsgPack) > 0:
+		return ctymsgpack.Unmarshal(dv.MsgPack, ty)
+	case len(dv.JSON) > 0:
+		return ctyjson.Unmarshal(dv.JSON, ty)
+	default:
+		return cty.NullVal(ty), nil
+	}
+}
+
+func diagnosticsError(method string, diags []*tfprotov5.Diagnostic) error {
+	var errs []string
+	for _, d := range diags {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			errs = append(errs, fmt.Sprintf("%s: %s", d.Summary, d.Detail))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s failed: %s", method, strings.Join(errs, "; "))
+}
@@ -0,0 +1,120 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package planoracle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func testResource(t *testing.T) *Resource {
+	res := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {Type: schema.TypeString, Optional: true},
+			"zone": {Type: schema.TypeString, Optional: true, ForceNew: true},
+			"tags": {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+					"port": {Type: schema.TypeInt, Required: true},
+				}},
+			},
+		},
+		CreateContext: func(_ context.Context, d *schema.ResourceData, _ any) diag.Diagnostics {
+			d.SetId("r1")
+			return nil
+		},
+		ReadContext: func(context.Context, *schema.ResourceData, any) diag.Diagnostics { return nil },
+		UpdateContext: func(context.Context, *schema.ResourceData, any) diag.Diagnostics {
+			return nil
+		},
+		DeleteContext: func(context.Context, *schema.ResourceData, any) diag.Diagnostics { return nil },
+	}
+	p := &schema.Provider{ResourcesMap: map[string]*schema.Resource{"test_res": res}}
+
+	ctx := context.Background()
+	o, err := New(ctx, p.GRPCProvider())
+	require.NoError(t, err)
+	r, err := o.Resource("test_res")
+	require.NoError(t, err)
+	return r
+}
+
+func planAndApply(t *testing.T, r *Resource, config any) *Plan {
+	ctx := context.Background()
+	p, err := r.Plan(ctx, config)
+	require.NoError(t, err)
+	require.NoError(t, r.Apply(ctx, p))
+	return p
+}
+
+func TestLifecycle(t *testing.T) {
+	t.Parallel()
+	r := testResource(t)
+
+	p := planAndApply(t, r, map[string]any{"name": "a", "zone": "z1", "tags": []any{"x", "y"}})
+	assert.Equal(t, []string{"create"}, p.Actions)
+	assert.Equal(t, cty.StringVal("r1"), r.State().GetAttr("id"))
+
+	p = planAndApply(t, r, map[string]any{"name": "a", "zone": "z1", "tags": []any{"y", "x"}})
+	assert.Equal(t, []string{"no-op"}, p.Actions, "reordering a set is not a change")
+
+	p = planAndApply(t, r, map[string]any{"name": "b", "zone": "z1", "rule": []any{map[string]any{"port": 80}}})
+	assert.Equal(t, []string{"update"}, p.Actions)
+	assert.Empty(t, p.ReplacePaths)
+	assert.Equal(t, cty.StringVal("b"), r.State().GetAttr("name"))
+
+	p = planAndApply(t, r, map[string]any{"name": "b", "zone": "z2", "rule": []any{map[string]any{"port": 80}}})
+	assert.Equal(t, []string{"delete", "create"}, p.Actions)
+	assert.Equal(t, []cty.Path{cty.GetAttrPath("zone")}, p.ReplacePaths)
+	assert.Equal(t, cty.StringVal("z2"), r.State().GetAttr("zone"))
+
+	p = planAndApply(t, r, nil)
+	assert.Equal(t, []string{"delete"}, p.Actions)
+	assert.True(t, r.State().IsNull())
+
+	p = planAndApply(t, r, nil)
+	assert.Equal(t, []string{"no-op"}, p.Actions)
+}
+
+func TestPlanValidatesConfig(t *testing.T) {
+	t.Parallel()
+	r := testResource(t)
+
+	_, err := r.Plan(context.Background(), map[string]any{"rule": []any{map[string]any{}}})
+	assert.Error(t, err)
+
+	_, err = r.Plan(context.Background(), map[string]any{"unknown_attr": "x"})
+	assert.Error(t, err)
+}
+
+func TestApplyRejectsStalePlan(t *testing.T) {
+	t.Parallel()
+	r := testResource(t)
+	ctx := context.Background()
+
+	stale, err := r.Plan(ctx, map[string]any{"name": "a"})
+	require.NoError(t, err)
+	planAndApply(t, r, map[string]any{"name": "b"})
+
+	assert.ErrorContains(t, r.Apply(ctx, stale), "stale plan")
+}
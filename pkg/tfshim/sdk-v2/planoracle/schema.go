@@ -0,0 +1,127 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package planoracle
+
+import (
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/pulumi/pulumi-terraform-bridge/v3/pkg/tfshim/sdk-v2/internal/tf/configs/configschema"
+)
+
+func convertBlock(b *tfprotov5.SchemaBlock) *configschema.Block {
+	block := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{},
+		BlockTypes: map[string]*configschema.NestedBlock{},
+	}
+	if b == nil {
+		return block
+	}
+	block.Deprecated = b.Deprecated
+	for _, a := range b.Attributes {
+		block.Attributes[a.Name] = &configschema.Attribute{
+			Type:       convertType(a.Type),
+			Required:   a.Required,
+			Optional:   a.Optional,
+			Computed:   a.Computed,
+			Sensitive:  a.Sensitive,
+			Deprecated: a.Deprecated,
+		}
+	}
+	for _, nb := range b.BlockTypes {
+		block.BlockTypes[nb.TypeName] = &configschema.NestedBlock{
+			Block:    *convertBlock(nb.Block),
+			Nesting:  convertNesting(nb.Nesting),
+			MinItems: int(nb.MinItems),
+			MaxItems: int(nb.MaxItems),
+		}
+	}
+	return block
+}
+
+func convertNesting(n tfprotov5.SchemaNestedBlockNestingMode) configschema.NestingMode {
+	switch n {
+	case tfprotov5.SchemaNestedBlockNestingModeSingle:
+		return configschema.NestingSingle
+	case tfprotov5.SchemaNestedBlockNestingModeGroup:
+		return configschema.NestingGroup
+	case tfprotov5.SchemaNestedBlockNestingModeList:
+		return configschema.NestingList
+	case tfprotov5.SchemaNestedBlockNestingModeSet:
+		return configschema.NestingSet
+	case tfprotov5.SchemaNestedBlockNestingModeMap:
+		return configschema.NestingMap
+	default:
+		contract.Failf("unsupported nesting mode %v", n)
+		return configschema.NestingSingle
+	}
+}
+
+func convertType(t tftypes.Type) cty.Type {
+	switch {
+	case t.Is(tftypes.String):
+		return cty.String
+	case t.Is(tftypes.Number):
+		return cty.Number
+	case t.Is(tftypes.Bool):
+		return cty.Bool
+	case t.Is(tftypes.DynamicPseudoType):
+		return cty.DynamicPseudoType
+	case t.Is(tftypes.List{}):
+		return cty.List(convertType(t.(tftypes.List).ElementType))
+	case t.Is(tftypes.Set{}):
+		return cty.Set(convertType(t.(tftypes.Set).ElementType))
+	case t.Is(tftypes.Map{}):
+		return cty.Map(convertType(t.(tftypes.Map).ElementType))
+	case t.Is(tftypes.Object{}):
+		attrs := map[string]cty.Type{}
+		for k, at := range t.(tftypes.Object).AttributeTypes {
+			attrs[k] = convertType(at)
+		}
+		return cty.Object(attrs)
+	case t.Is(tftypes.Tuple{}):
+		var elems []cty.Type
+		for _, et := range t.(tftypes.Tuple).ElementTypes {
+			elems = append(elems, convertType(et))
+		}
+		return cty.Tuple(elems)
+	default:
+		contract.Failf("unsupported type %v", t)
+		return cty.NilType
+	}
+}
+
+// convertPath converts a path from a RequiresReplace response. Set elements are addressed by value.
+func convertPath(ap *tftypes.AttributePath) cty.Path {
+	var path cty.Path
+	for _, step := range ap.Steps() {
+		switch step := step.(type) {
+		case tftypes.AttributeName:
+			path = path.GetAttr(string(step))
+		case tftypes.ElementKeyString:
+			path = path.Index(cty.StringVal(string(step)))
+		case tftypes.ElementKeyInt:
+			path = path.Index(cty.NumberVal(new(big.Float).SetInt64(int64(step))))
+		case tftypes.ElementKeyValue:
+			// Values cannot be converted without their type, so the path stops at the enclosing set.
+			return path
+		}
+	}
+	return path
+}
@@ -140,6 +140,13 @@ type Schema interface {
 	SetHash(v interface{}) int
 }
 
+// SchemaWithWriteOnly is implemented by schemas that may declare write-only attributes. The values of write-only
+// attributes are sent to the provider but are never stored in state.
+type SchemaWithWriteOnly interface {
+	Schema
+	WriteOnly() bool
+}
+
 type SchemaMap interface {
 	Len() int
 	Get(key string) Schema
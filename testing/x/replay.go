@@ -15,8 +15,10 @@
 package testing
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"os"
 	"strings"
 	"testing"
@@ -177,19 +179,29 @@ func replay[Req protoreflect.ProtoMessage, Resp protoreflect.ProtoMessage](
 ) {
 	ctx := context.Background()
 
-	err := jsonpb.Unmarshal([]byte(entry.Request), req)
+	request, expected := entry.Request, entry.Response
+	if entry.Masked != nil {
+		// Masked values are not the real values, so they are left out rather than sent to the provider.
+		request = removeJSONPaths(t, request, entry.Masked.Request)
+		expected = removeJSONPaths(t, expected, entry.Masked.Response)
+	}
+
+	err := jsonpb.Unmarshal([]byte(request), req)
 	assert.NoError(t, err)
 
 	resp, err := serve(ctx, req)
-	if err != nil && entry.Errors != nil {
-		assert.Equal(t, *entry.Errors, err.Error())
+	if err != nil && len(entry.Errors) > 0 {
+		assert.Contains(t, []string(entry.Errors), err.Error())
 		return
 	}
 	require.NoError(t, err)
 	bytes, err := jsonpb.Marshal(resp)
 	assert.NoError(t, err)
 
-	var expected, actual json.RawMessage = entry.Response, bytes
+	var actual json.RawMessage = bytes
+	if entry.Masked != nil {
+		actual = removeJSONPaths(t, actual, entry.Masked.Response)
+	}
 
 	AssertJSONMatchesPattern(t, expected, actual, WithUnorderedArrayPaths(map[string]bool{`#["failures"]`: true}))
 }
@@ -202,12 +214,15 @@ func replay[Req protoreflect.ProtoMessage, Resp protoreflect.ProtoMessage](
 // This produces the testdata/log.json file, which can then be used for Replay-style testing:
 //
 //	ReplayFile(t, server, "testdata/log.json")
+//
+// ReplayFile also accepts files with one JSON entry per line, as recorded by bridged providers when the
+// PULUMI_TFBRIDGE_GRPC_RECORD environment variable is set. Values that such recordings list as masked are left out
+// of the replayed requests and are not compared in the responses.
 func ReplayFile(t *testing.T, server pulumirpc.ResourceProviderServer, traceFile string) {
 	bytes, err := os.ReadFile(traceFile)
 	require.NoError(t, err)
 
-	var entries []jsonLogEntry
-	err = json.Unmarshal(bytes, &entries)
+	entries, err := parseLogEntries(bytes)
 	require.NoError(t, err)
 
 	count := 0
@@ -235,9 +250,77 @@ func ReplayFile(t *testing.T, server pulumirpc.ResourceProviderServer, traceFile
 	assert.Greater(t, count, 0)
 }
 
+// parseLogEntries parses either a JSON array of entries or a sequence of JSON entries, such as JSON lines.
+func parseLogEntries(data []byte) ([]jsonLogEntry, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var entries []jsonLogEntry
+		err := json.Unmarshal(trimmed, &entries)
+		return entries, err
+	}
+	var entries []jsonLogEntry
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var entry jsonLogEntry
+		err := dec.Decode(&entry)
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
 type jsonLogEntry struct {
 	Method   string          `json:"method"`
 	Request  json.RawMessage `json:"request,omitempty"`
 	Response json.RawMessage `json:"response,omitempty"`
-	Errors   *string         `json:"errors,omitempty"`
+	Errors   jsonLogErrors   `json:"errors,omitempty"`
+	Masked   *jsonLogMasked  `json:"masked,omitempty"`
+}
+
+// jsonLogMasked lists the JSON paths of the values that a recording replaced by "*" because they are secret, such as
+// configuration variables.
+type jsonLogMasked struct {
+	Request  [][]string `json:"request,omitempty"`
+	Response [][]string `json:"response,omitempty"`
+}
+
+// removeJSONPaths removes the values at paths from the JSON object msg. Paths that are not present are ignored.
+func removeJSONPaths(t *testing.T, msg json.RawMessage, paths [][]string) json.RawMessage {
+	if len(paths) == 0 || len(msg) == 0 {
+		return msg
+	}
+	var v interface{}
+	require.NoError(t, json.Unmarshal(msg, &v))
+	for _, path := range paths {
+		obj, ok := v.(map[string]interface{})
+		for i := 0; ok && i < len(path)-1; i++ {
+			obj, ok = obj[path[i]].(map[string]interface{})
+		}
+		if ok && len(path) > 0 {
+			delete(obj, path[len(path)-1])
+		}
+	}
+	result, err := json.Marshal(v)
+	require.NoError(t, err)
+	return result
+}
+
+// jsonLogErrors accepts both a single error string and the list of errors written by PULUMI_DEBUG_GPRC.
+type jsonLogErrors []string
+
+func (e *jsonLogErrors) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*e = jsonLogErrors{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*e = many
+	return nil
 }
@@ -0,0 +1,97 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+)
+
+func TestParseLogEntries(t *testing.T) {
+	expected := []jsonLogEntry{
+		{Method: "/pulumirpc.ResourceProvider/Check", Request: []byte(`{}`), Response: []byte(`{}`)},
+		{Method: "/pulumirpc.ResourceProvider/Delete", Request: []byte(`{}`), Errors: jsonLogErrors{"oops"}},
+	}
+
+	t.Run("array", func(t *testing.T) {
+		entries, err := parseLogEntries([]byte(`[
+		  {"method": "/pulumirpc.ResourceProvider/Check", "request": {}, "response": {}},
+		  {"method": "/pulumirpc.ResourceProvider/Delete", "request": {}, "errors": "oops"}
+		]`))
+		require.NoError(t, err)
+		assert.Equal(t, expected, entries)
+	})
+
+	t.Run("lines", func(t *testing.T) {
+		entries, err := parseLogEntries([]byte(
+			`{"method":"/pulumirpc.ResourceProvider/Check","request":{},"response":{}}` + "\n" +
+				`{"method":"/pulumirpc.ResourceProvider/Delete","request":{},"errors":["oops"]}` + "\n"))
+		require.NoError(t, err)
+		assert.Equal(t, expected, entries)
+	})
+}
+
+func TestReplayMaskedValues(t *testing.T) {
+	Replay(t, &maskedConfigServer{}, `{
+	  "method": "/pulumirpc.ResourceProvider/CheckConfig",
+	  "request": {"news": {"accessKey": "*", "region": "us-west-2"}},
+	  "response": {"inputs": {"accessKey": "*", "region": "us-west-2"}},
+	  "masked": {"request": [["news", "accessKey"]], "response": [["inputs", "accessKey"]]}
+	}`)
+
+	Replay(t, &maskedConfigServer{}, `{
+	  "method": "/pulumirpc.ResourceProvider/Configure",
+	  "request": {
+	    "variables": {"test:config:accessKey": "*", "test:config:region": "*"},
+	    "args": {"accessKey": "*", "region": "us-west-2"}
+	  },
+	  "response": {},
+	  "masked": {"request": [
+	    ["args", "accessKey"],
+	    ["variables", "test:config:accessKey"],
+	    ["variables", "test:config:region"]
+	  ]}
+	}`)
+}
+
+// maskedConfigServer fails if it receives a masked value, and fills in a default for the missing access key.
+type maskedConfigServer struct {
+	pulumirpc.UnimplementedResourceProviderServer
+}
+
+func (*maskedConfigServer) CheckConfig(
+	_ context.Context, req *pulumirpc.CheckRequest,
+) (*pulumirpc.CheckResponse, error) {
+	news := req.GetNews()
+	if news.GetFields()["accessKey"] != nil {
+		return nil, fmt.Errorf("unexpected masked accessKey")
+	}
+	return &pulumirpc.CheckResponse{Inputs: news}, nil
+}
+
+func (*maskedConfigServer) Configure(
+	_ context.Context, req *pulumirpc.ConfigureRequest,
+) (*pulumirpc.ConfigureResponse, error) {
+	if len(req.GetVariables()) > 0 || req.GetArgs().GetFields()["accessKey"] != nil {
+		return nil, fmt.Errorf("unexpected masked configuration")
+	}
+	return &pulumirpc.ConfigureResponse{}, nil
+}
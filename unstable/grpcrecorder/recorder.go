@@ -0,0 +1,325 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpcrecorder records the gRPC traffic of a provider into fixtures that testing/x.Replay and
+// testing/x.ReplayFile can consume.
+package grpcrecorder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/sig"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// Setting this environment variable to a file path makes bridged providers append every request they serve,
+	// together with its response, to that file.
+	//
+	// See New for the format.
+	EnvVar = "PULUMI_TFBRIDGE_GRPC_RECORD"
+)
+
+// FromEnv wraps server with a recorder writing to the file named by EnvVar, or returns server unchanged if EnvVar
+// is not set.
+func FromEnv(
+	server pulumirpc.ResourceProviderServer, opts ...Option,
+) (pulumirpc.ResourceProviderServer, error) {
+	path := os.Getenv(EnvVar)
+	if path == "" {
+		return server, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s=%q for recording: %w", EnvVar, path, err)
+	}
+	return New(server, f, opts...), nil
+}
+
+// An Option configures a recorder.
+type Option func(*recorder)
+
+// WithSecretConfigKeys masks the values of the given provider configuration keys in CheckConfig, DiffConfig and
+// Configure calls. Keys are Pulumi property names, such as those returned by tfbridge.SecretConfigKeys.
+func WithSecretConfigKeys(keys ...string) Option {
+	return func(r *recorder) {
+		for _, k := range keys {
+			r.secretConfig[k] = struct{}{}
+		}
+	}
+}
+
+// New returns a server that forwards all calls to server, writing each request and its response to w.
+//
+// Entries are written as JSON lines in the format of PULUMI_DEBUG_GPRC logs:
+//
+//	{"method": "/pulumirpc.ResourceProvider/Create", "request": {...}, "response": {...}}
+//
+// Failed calls record the error message in the "errors" list instead of a response.
+//
+// Recordings are meant to be checked in as test fixtures, so they are scrubbed before being written:
+//
+//   - The plaintext of secret values is replaced by "*" in both requests and responses.
+//   - The values of all configuration variables sent to Configure are replaced by "*", as are the values of the
+//     provider configuration keys passed to WithSecretConfigKeys.
+//   - Timestamps and UUIDs in responses are replaced by "*", which Replay treats as matching any value.
+//
+// Masked configuration values are listed in the "masked" field of the entry, as JSON paths into the request and
+// the response, so that testing/x.Replay leaves them out instead of sending "*" as a real value:
+//
+//	{"method": "/pulumirpc.ResourceProvider/Configure", "request": {...}, "response": {...},
+//	 "masked": {"request": [["variables", "aws:config:secretKey"], ["args", "secretKey"]]}}
+//
+// Requests are otherwise recorded verbatim so that they can be replayed. GetSchema, GetPluginInfo, Attach,
+// Cancel and StreamInvoke calls are not recorded.
+func New(server pulumirpc.ResourceProviderServer, w io.Writer, opts ...Option) pulumirpc.ResourceProviderServer {
+	r := &recorder{ResourceProviderServer: server, w: w, secretConfig: map[string]struct{}{}}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+type recorder struct {
+	pulumirpc.ResourceProviderServer
+
+	secretConfig map[string]struct{}
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+type entry struct {
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Errors   []string        `json:"errors,omitempty"`
+	Masked   *masked         `json:"masked,omitempty"`
+}
+
+// The JSON paths of the configuration values that were masked in an entry.
+type masked struct {
+	Request  [][]string `json:"request,omitempty"`
+	Response [][]string `json:"response,omitempty"`
+}
+
+func record[Req, Resp proto.Message](
+	ctx context.Context, r *recorder, method string, req Req,
+	serve func(context.Context, Req) (Resp, error),
+) (Resp, error) {
+	resp, err := serve(ctx, req)
+	if werr := r.write(method, req, resp, err); werr != nil {
+		// Recording is a debugging aid and must never fail the call itself.
+		glog.Warningf("failed to record %s: %v", method, werr)
+	}
+	return resp, err
+}
+
+func (r *recorder) write(method string, req, resp proto.Message, err error) error {
+	e := entry{Method: "/pulumirpc.ResourceProvider/" + method}
+
+	config := map[string]struct{}(nil)
+	switch method {
+	case "CheckConfig", "DiffConfig", "Configure":
+		config = r.secretConfig
+	}
+
+	var m masked
+	var werr error
+	e.Request, m.Request, werr = scrub(req, false, config)
+	if werr != nil {
+		return werr
+	}
+	if err != nil {
+		e.Errors = []string{err.Error()}
+	} else {
+		e.Response, m.Response, werr = scrub(resp, true, config)
+		if werr != nil {
+			return werr
+		}
+	}
+	if len(m.Request) > 0 || len(m.Response) > 0 {
+		e.Masked = &m
+	}
+
+	line, werr := json.Marshal(e)
+	if werr != nil {
+		return werr
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, werr = r.w.Write(append(line, '\n'))
+	return werr
+}
+
+var volatile = regexp.MustCompile(
+	// RFC 3339 timestamps, as used by most cloud APIs.
+	`^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?$` +
+		// UUIDs.
+		`|^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// scrub marshals m to JSON, masking secrets, and volatile values if patterns is set.
+//
+// If config is non-nil, m is a provider configuration message: the values of the secret keys in config are masked
+// in its property maps, and all configuration variables are masked. The paths of the masked values are returned.
+func scrub(m proto.Message, patterns bool, config map[string]struct{}) (json.RawMessage, [][]string, error) {
+	bytes, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(bytes, &v); err != nil {
+		return nil, nil, err
+	}
+	var maskedPaths [][]string
+	if obj, ok := v.(map[string]interface{}); ok && config != nil {
+		maskedPaths = scrubConfig(obj, config)
+	}
+	scrubbed, err := json.Marshal(scrubValue(v, patterns))
+	return scrubbed, maskedPaths, err
+}
+
+// scrubConfig masks configuration values in the JSON form of a CheckConfig, DiffConfig or Configure message, and
+// returns the sorted paths of the masked values.
+func scrubConfig(m map[string]interface{}, secretKeys map[string]struct{}) [][]string {
+	var paths [][]string
+	if vars, ok := m["variables"].(map[string]interface{}); ok {
+		// Configure variables are plain strings, so secret values cannot be told apart from the others.
+		for k := range vars {
+			vars[k] = "*"
+			paths = append(paths, []string{"variables", k})
+		}
+	}
+	for _, field := range []string{"args", "olds", "news", "oldInputs", "inputs"} {
+		props, ok := m[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for k := range props {
+			if _, secret := secretKeys[k]; secret {
+				props[k] = "*"
+				paths = append(paths, []string{field, k})
+			}
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return strings.Join(paths[i], "\x00") < strings.Join(paths[j], "\x00")
+	})
+	return paths
+}
+
+func scrubValue(v interface{}, patterns bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if v[sig.Key] == sig.Secret {
+			if _, has := v["value"]; has {
+				v["value"] = "*"
+			}
+			return v
+		}
+		for k, e := range v {
+			v[k] = scrubValue(e, patterns)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = scrubValue(e, patterns)
+		}
+		return v
+	case string:
+		if patterns && volatile.MatchString(v) {
+			return "*"
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func (r *recorder) CheckConfig(ctx context.Context, req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	return record(ctx, r, "CheckConfig", req, r.ResourceProviderServer.CheckConfig)
+}
+
+func (r *recorder) DiffConfig(ctx context.Context, req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	return record(ctx, r, "DiffConfig", req, r.ResourceProviderServer.DiffConfig)
+}
+
+func (r *recorder) Configure(
+	ctx context.Context, req *pulumirpc.ConfigureRequest,
+) (*pulumirpc.ConfigureResponse, error) {
+	return record(ctx, r, "Configure", req, r.ResourceProviderServer.Configure)
+}
+
+func (r *recorder) Invoke(ctx context.Context, req *pulumirpc.InvokeRequest) (*pulumirpc.InvokeResponse, error) {
+	return record(ctx, r, "Invoke", req, r.ResourceProviderServer.Invoke)
+}
+
+func (r *recorder) Call(ctx context.Context, req *pulumirpc.CallRequest) (*pulumirpc.CallResponse, error) {
+	return record(ctx, r, "Call", req, r.ResourceProviderServer.Call)
+}
+
+func (r *recorder) Check(ctx context.Context, req *pulumirpc.CheckRequest) (*pulumirpc.CheckResponse, error) {
+	return record(ctx, r, "Check", req, r.ResourceProviderServer.Check)
+}
+
+func (r *recorder) Diff(ctx context.Context, req *pulumirpc.DiffRequest) (*pulumirpc.DiffResponse, error) {
+	return record(ctx, r, "Diff", req, r.ResourceProviderServer.Diff)
+}
+
+func (r *recorder) Create(ctx context.Context, req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
+	return record(ctx, r, "Create", req, r.ResourceProviderServer.Create)
+}
+
+func (r *recorder) Read(ctx context.Context, req *pulumirpc.ReadRequest) (*pulumirpc.ReadResponse, error) {
+	return record(ctx, r, "Read", req, r.ResourceProviderServer.Read)
+}
+
+func (r *recorder) Update(ctx context.Context, req *pulumirpc.UpdateRequest) (*pulumirpc.UpdateResponse, error) {
+	return record(ctx, r, "Update", req, r.ResourceProviderServer.Update)
+}
+
+func (r *recorder) Delete(ctx context.Context, req *pulumirpc.DeleteRequest) (*emptypb.Empty, error) {
+	return record(ctx, r, "Delete", req, r.ResourceProviderServer.Delete)
+}
+
+func (r *recorder) Construct(
+	ctx context.Context, req *pulumirpc.ConstructRequest,
+) (*pulumirpc.ConstructResponse, error) {
+	return record(ctx, r, "Construct", req, r.ResourceProviderServer.Construct)
+}
+
+func (r *recorder) GetMapping(
+	ctx context.Context, req *pulumirpc.GetMappingRequest,
+) (*pulumirpc.GetMappingResponse, error) {
+	return record(ctx, r, "GetMapping", req, r.ResourceProviderServer.GetMapping)
+}
+
+func (r *recorder) GetMappings(
+	ctx context.Context, req *pulumirpc.GetMappingsRequest,
+) (*pulumirpc.GetMappingsResponse, error) {
+	return record(ctx, r, "GetMappings", req, r.ResourceProviderServer.GetMappings)
+}
@@ -0,0 +1,173 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcrecorder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pulumi/providertest/replay"
+	pulumirpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	server := New(&fakeServer{}, &buf)
+
+	secret, err := structpb.NewValue(map[string]interface{}{
+		"4dabf18193072939515e22adb298388d": "1b47061264138c4ac30d75fd1eb44270",
+		"value":                            "hunter2",
+	})
+	require.NoError(t, err)
+
+	_, err = server.Create(ctx, &pulumirpc.CreateRequest{
+		Urn: "urn:pulumi:dev::test::test:index:Res::r",
+		Properties: &structpb.Struct{Fields: map[string]*structpb.Value{
+			"password": secret,
+			"name":     structpb.NewStringValue("r"),
+		}},
+	})
+	require.NoError(t, err)
+
+	_, err = server.Delete(ctx, &pulumirpc.DeleteRequest{
+		Urn: "urn:pulumi:dev::test::test:index:Res::r",
+		Id:  "fail",
+	})
+	require.Error(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.NotContains(t, buf.String(), "hunter2")
+
+	replay.AssertJSONMatchesPattern(t, []byte(`{
+	  "method": "/pulumirpc.ResourceProvider/Create",
+	  "request": {
+	    "urn": "urn:pulumi:dev::test::test:index:Res::r",
+	    "properties": {
+	      "name": "r",
+	      "password": {
+	        "4dabf18193072939515e22adb298388d": "1b47061264138c4ac30d75fd1eb44270",
+	        "value": "*"
+	      }
+	    }
+	  },
+	  "response": {
+	    "id": "*",
+	    "properties": {
+	      "name": "r",
+	      "created": "*"
+	    }
+	  }
+	}`), []byte(lines[0]))
+	assert.Contains(t, lines[0], `"id":"*"`)
+	assert.Contains(t, lines[0], `"created":"*"`)
+
+	// The recording replays against the same server.
+	for _, line := range lines {
+		replay.Replay(t, &fakeServer{}, line)
+	}
+}
+
+func TestRecorderMasksConfiguration(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	server := New(&fakeServer{}, &buf, WithSecretConfigKeys("accessKey"))
+
+	args := &structpb.Struct{Fields: map[string]*structpb.Value{
+		"accessKey": structpb.NewStringValue("hunter2"),
+		"region":    structpb.NewStringValue("us-west-2"),
+	}}
+
+	_, err := server.CheckConfig(ctx, &pulumirpc.CheckRequest{News: args})
+	require.NoError(t, err)
+
+	_, err = server.Configure(ctx, &pulumirpc.ConfigureRequest{
+		Variables: map[string]string{
+			"test:config:accessKey": "hunter2",
+			"test:config:token":     "s3cr3t",
+		},
+		Args: args,
+	})
+	require.NoError(t, err)
+
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.NotContains(t, buf.String(), "s3cr3t")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	replay.AssertJSONMatchesPattern(t, []byte(`{
+	  "method": "/pulumirpc.ResourceProvider/CheckConfig",
+	  "request": {"news": {"accessKey": "*", "region": "us-west-2"}},
+	  "response": {"inputs": {"accessKey": "*", "region": "us-west-2"}},
+	  "masked": {"request": [["news", "accessKey"]], "response": [["inputs", "accessKey"]]}
+	}`), []byte(lines[0]))
+	replay.AssertJSONMatchesPattern(t, []byte(`{
+	  "method": "/pulumirpc.ResourceProvider/Configure",
+	  "request": {
+	    "variables": {"test:config:accessKey": "*", "test:config:token": "*"},
+	    "args": {"accessKey": "*", "region": "us-west-2"}
+	  },
+	  "response": {},
+	  "masked": {"request": [
+	    ["args", "accessKey"],
+	    ["variables", "test:config:accessKey"],
+	    ["variables", "test:config:token"]
+	  ]}
+	}`), []byte(lines[1]))
+}
+
+type fakeServer struct {
+	pulumirpc.UnimplementedResourceProviderServer
+}
+
+func (*fakeServer) Create(
+	_ context.Context, req *pulumirpc.CreateRequest,
+) (*pulumirpc.CreateResponse, error) {
+	return &pulumirpc.CreateResponse{
+		Id: "0b9e3e4c-5d0d-4a4e-9a52-3b1c7a2f2d11",
+		Properties: &structpb.Struct{Fields: map[string]*structpb.Value{
+			"name":    req.GetProperties().GetFields()["name"],
+			"created": structpb.NewStringValue("2024-03-01T12:00:00Z"),
+		}},
+	}, nil
+}
+
+func (*fakeServer) Delete(_ context.Context, req *pulumirpc.DeleteRequest) (*emptypb.Empty, error) {
+	return nil, fmt.Errorf("cannot delete %q", req.GetId())
+}
+
+func (*fakeServer) CheckConfig(
+	_ context.Context, req *pulumirpc.CheckRequest,
+) (*pulumirpc.CheckResponse, error) {
+	return &pulumirpc.CheckResponse{Inputs: req.GetNews()}, nil
+}
+
+func (*fakeServer) Configure(
+	_ context.Context, req *pulumirpc.ConfigureRequest,
+) (*pulumirpc.ConfigureResponse, error) {
+	return &pulumirpc.ConfigureResponse{}, nil
+}
@@ -15,14 +15,38 @@
 package metadata
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/segmentio/encoding/json"
 )
 
 // The underlying value of a metadata blob.
-type Data struct{ m map[string]json.RawMessage }
+type Data struct {
+	m map[string]json.RawMessage
+
+	// The gzip compressed JSON of the keys stored by Compress. A key is never in both m and compressed.
+	compressed map[string][]byte
+
+	// Decompressed values of compressed keys, populated by Get on first use.
+	mu           sync.Mutex
+	decompressed map[string]json.RawMessage
+}
 
+// New parses data, as written by [Data.Marshal], [Data.MarshalIndent] or [Data.MarshalBinary].
 func New(data []byte) (*Data, error) {
+	if bytes.HasPrefix(data, []byte(binaryMagic)) {
+		return unmarshalBinary(data[len(binaryMagic):])
+	}
+
 	m := map[string]json.RawMessage{}
 	if len(data) > 0 {
 		_, err := json.Parse(data, &m, json.ZeroCopy)
@@ -31,7 +55,19 @@ func New(data []byte) (*Data, error) {
 		}
 	}
 
-	return &Data{m}, nil
+	d := &Data{m: m}
+	for k, v := range m {
+		payload, isCompressed := compressedPayload(v)
+		if !isCompressed {
+			continue
+		}
+		compressed, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid compressed metadata key %q: %w", k, err)
+		}
+		d.setCompressed(k, compressed)
+	}
+	return d, nil
 }
 
 func (d *Data) Marshal() []byte {
@@ -46,17 +82,27 @@ func (d *Data) marshal(indent bool) []byte {
 	if d == nil {
 		d = &Data{m: make(map[string]json.RawMessage)}
 	}
+	m := d.m
+	if len(d.compressed) > 0 {
+		m = make(map[string]json.RawMessage, len(d.m)+len(d.compressed))
+		for k, v := range d.m {
+			m[k] = v
+		}
+		for k, v := range d.compressed {
+			m[k] = compressedJSON(v)
+		}
+	}
 	var bytes []byte
 	var err error
 	if indent {
-		bytes, err = json.MarshalIndent(d.m, "", "    ")
+		bytes, err = json.MarshalIndent(m, "", "    ")
 	} else {
-		bytes, err = json.Marshal(d.m)
+		bytes, err = json.Marshal(m)
 	}
-	// `d.m` is a `map[string]json.RawMessage`. `json.MarshalIndent` errors only when
+	// `m` is a `map[string]json.RawMessage`. `json.MarshalIndent` errors only when
 	// it is asked to serialize an unmarshalable type (complex, function or channel)
 	// or a cyclic data structure. Because `string` and `json.RawMessage` are
-	// trivially marshallable and cannot contain cycles, all values of `d.m` can be
+	// trivially marshallable and cannot contain cycles, all values of `m` can be
 	// marshaled without error.
 	//
 	// See https://pkg.go.dev/encoding/json#Marshal for details.
@@ -64,12 +110,103 @@ func (d *Data) marshal(indent bool) []byte {
 	return bytes
 }
 
+// The prefix of the binary encoding. The leading NUL byte can never start a JSON document.
+const binaryMagic = "\x00pulumi-metadata\x01"
+
+// The kinds of values in the binary encoding.
+const (
+	binaryJSON byte = iota
+	binaryGzip
+)
+
+// MarshalBinary encodes d more compactly than [Data.Marshal], for metadata that is only read
+// by providers at runtime.
+//
+// Compressed keys are stored as raw gzip streams instead of base64 encoded JSON strings, and
+// uncompressed values are stored as compact JSON. Each key is encoded as the length prefixed
+// key, a byte for the kind of value and the length prefixed value, in key order.
+func (d *Data) MarshalBinary() []byte {
+	if d == nil {
+		d = &Data{}
+	}
+	keys := make([]string, 0, len(d.m)+len(d.compressed))
+	for k := range d.m {
+		keys = append(keys, k)
+	}
+	for k := range d.compressed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString(binaryMagic)
+	writeBytes := func(b []byte) {
+		buf.Write(binary.AppendUvarint(nil, uint64(len(b))))
+		buf.Write(b)
+	}
+	for _, k := range keys {
+		writeBytes([]byte(k))
+		if v, ok := d.compressed[k]; ok {
+			buf.WriteByte(binaryGzip)
+			writeBytes(v)
+			continue
+		}
+		var compact bytes.Buffer
+		err := json.Compact(&compact, d.m[k])
+		// Values are either parsed by New or marshaled by Set, so they are valid JSON.
+		contract.AssertNoErrorf(err, "internal: failed to compact metadata key %q", k)
+		buf.WriteByte(binaryJSON)
+		writeBytes(compact.Bytes())
+	}
+	return buf.Bytes()
+}
+
+var errTruncatedBinary = errors.New("truncated binary metadata")
+
+func unmarshalBinary(data []byte) (*Data, error) {
+	d := &Data{m: map[string]json.RawMessage{}}
+	readBytes := func() ([]byte, error) {
+		n, size := binary.Uvarint(data)
+		if size <= 0 || uint64(len(data)-size) < n {
+			return nil, errTruncatedBinary
+		}
+		b := data[size : size+int(n)]
+		data = data[size+int(n):]
+		return b, nil
+	}
+	for len(data) > 0 {
+		key, err := readBytes()
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			return nil, errTruncatedBinary
+		}
+		kind := data[0]
+		data = data[1:]
+		value, err := readBytes()
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case binaryJSON:
+			d.m[string(key)] = json.RawMessage(value)
+		case binaryGzip:
+			d.setCompressed(string(key), value)
+		default:
+			return nil, fmt.Errorf("unknown kind %d of metadata key %q", kind, key)
+		}
+	}
+	return d, nil
+}
+
 // Set a piece of metadata to a value.
 //
 // Set errors only if value fails to serialize.
 func Set(d *Data, key string, value any) error {
 	if value == nil {
 		delete(d.m, key)
+		d.forgetCompressed(key)
 		return nil
 	}
 	data, err := json.Marshal(value)
@@ -78,26 +215,152 @@ func Set(d *Data, key string, value any) error {
 	}
 	msg := json.RawMessage(data)
 	d.m[key] = msg
+	d.forgetCompressed(key)
 	return nil
 }
 
+// Get the value of a piece of metadata.
+//
+// Values stored by Compress are decompressed transparently. A compressed value is only decompressed on the first Get
+// of its key, so that compressed keys that are not used do not cost anything.
 func Get[T any](d *Data, key string) (T, bool, error) {
-	data, ok := d.m[key]
 	var t T
-	if !ok {
-		return t, false, nil
+	data, ok, err := d.get(key)
+	if !ok || err != nil {
+		return t, ok, err
 	}
-	_, err := json.Parse(data, &t, json.ZeroCopy)
+	_, err = json.Parse(data, &t, json.ZeroCopy)
 	return t, true, err
 }
 
+// The key of the only field of the JSON object that holds a compressed value in the JSON
+// encoding. The field holds the base64 encoded gzip compressed JSON of the value.
+const compressedField = "$gzip"
+
+// Compress replaces the value of key with a compressed encoding if the value is at least minSize bytes long.
+//
+// Compressed values are still valid JSON when marshaled with [Data.Marshal], and are stored
+// as raw gzip streams by [Data.MarshalBinary]. Get decompresses them transparently.
+func Compress(d *Data, key string, minSize int) error {
+	data, ok := d.m[key]
+	if !ok || len(data) < minSize {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	if len(compressedJSON(buf.Bytes())) >= len(data) {
+		// Small or high entropy values do not benefit from compression.
+		return nil
+	}
+	delete(d.m, key)
+	d.setCompressed(key, buf.Bytes())
+	return nil
+}
+
+// Get the raw value of key, decompressing it if necessary.
+func (d *Data) get(key string) (json.RawMessage, bool, error) {
+	if data, ok := d.m[key]; ok {
+		return data, true, nil
+	}
+	compressed, ok := d.compressed[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if decompressed, ok := d.decompressed[key]; ok {
+		return decompressed, true, nil
+	}
+	decompressed, err := decompress(compressed)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to decompress metadata key %q: %w", key, err)
+	}
+	if d.decompressed == nil {
+		d.decompressed = map[string]json.RawMessage{}
+	}
+	d.decompressed[key] = decompressed
+	return decompressed, true, nil
+}
+
+func (d *Data) setCompressed(key string, compressed []byte) {
+	if d.compressed == nil {
+		d.compressed = map[string][]byte{}
+	}
+	delete(d.m, key)
+	d.compressed[key] = compressed
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.decompressed, key)
+}
+
+func (d *Data) forgetCompressed(key string) {
+	delete(d.compressed, key)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.decompressed, key)
+}
+
+// compressedJSON returns the JSON encoding of a value stored by Compress.
+func compressedJSON(compressed []byte) json.RawMessage {
+	data, err := json.Marshal(map[string]string{
+		compressedField: base64.StdEncoding.EncodeToString(compressed),
+	})
+	contract.AssertNoErrorf(err, "internal: failed to marshal compressed metadata")
+	return data
+}
+
+// compressedPayload returns the payload of data if data holds a value stored by Compress.
+func compressedPayload(data json.RawMessage) (string, bool) {
+	// Check the prefix before parsing, so that New does not parse every object value twice.
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if !bytes.HasPrefix(trimmed, []byte("{")) {
+		return "", false
+	}
+	if !bytes.HasPrefix(bytes.TrimLeft(trimmed[1:], " \t\r\n"), []byte(`"`+compressedField+`"`)) {
+		return "", false
+	}
+	var v map[string]string
+	if _, err := json.Parse(data, &v, 0); err != nil || len(v) != 1 {
+		return "", false
+	}
+	payload, ok := v[compressedField]
+	return payload, ok
+}
+
+func decompress(compressed []byte) (json.RawMessage, error) {
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
 func CloneKey(key string, from, to *Data) {
+	if compressed, ok := from.compressed[key]; ok {
+		to.setCompressed(key, bytes.Clone(compressed))
+		return
+	}
 	data, ok := from.m[key]
 	if !ok {
 		delete(to.m, key)
+		to.forgetCompressed(key)
 		return
 	}
 	to.m[key] = cloneRawMessage(data)
+	to.forgetCompressed(key)
 }
 
 func Clone(data *Data) *Data {
@@ -108,7 +371,11 @@ func Clone(data *Data) *Data {
 	for k, v := range data.m {
 		m[k] = cloneRawMessage(v)
 	}
-	return &Data{m}
+	clone := &Data{m: m}
+	for k, v := range data.compressed {
+		clone.setCompressed(k, bytes.Clone(v))
+	}
+	return clone
 }
 
 func cloneRawMessage(m json.RawMessage) json.RawMessage {
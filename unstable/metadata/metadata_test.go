@@ -59,3 +59,91 @@ func TestMarshalIndent(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, []string{"hello", "world"}, read)
 }
+
+func TestCompress(t *testing.T) {
+	data, err := New(nil)
+	require.NoError(t, err)
+
+	large := make([]string, 1000)
+	for i := range large {
+		large[i] = "repetitive-value"
+	}
+	require.NoError(t, Set(data, "large", large))
+	require.NoError(t, Set(data, "small", []string{"hello"}))
+	uncompressedSize := len(data.m["large"])
+
+	require.NoError(t, Compress(data, "large", 100))
+	require.NoError(t, Compress(data, "small", 100))
+	require.NoError(t, Compress(data, "missing", 100))
+
+	assert.NotContains(t, data.m, "large")
+	assert.Less(t, len(data.compressed["large"]), uncompressedSize/10)
+	assert.Equal(t, `["hello"]`, string(data.m["small"]))
+	assert.NotContains(t, data.compressed, "small")
+	assert.Contains(t, string(data.Marshal()), `"large":{"$gzip":`)
+
+	// Compressed values survive marshaling and are decompressed on first use.
+	for _, marshalled := range [][]byte{data.Marshal(), data.MarshalIndent(), data.MarshalBinary()} {
+		parsed, err := New(marshalled)
+		require.NoError(t, err)
+		assert.Empty(t, parsed.decompressed)
+
+		for i := 0; i < 2; i++ {
+			read, ok, err := Get[[]string](parsed, "large")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, large, read)
+		}
+		assert.Len(t, parsed.decompressed, 1)
+
+		read, ok, err := Get[[]string](parsed, "small")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []string{"hello"}, read)
+	}
+
+	// Cloned keys stay compressed.
+	clone, err := New(nil)
+	require.NoError(t, err)
+	CloneKey("large", data, clone)
+	assert.Equal(t, data.compressed["large"], clone.compressed["large"])
+	assert.Equal(t, data.compressed, Clone(data).compressed)
+
+	// Overwriting a compressed key discards its decompressed value.
+	require.NoError(t, Set(data, "large", []string{"replaced"}))
+	read, _, err := Get[[]string](data, "large")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"replaced"}, read)
+}
+
+func TestMarshalBinary(t *testing.T) {
+	data, err := New(nil)
+	require.NoError(t, err)
+	require.NoError(t, Set(data, "hi", []string{"hello", "world"}))
+	require.NoError(t, Set(data, "n", 1))
+
+	marshalled := data.MarshalBinary()
+	assert.Equal(t, binaryMagic+"\x02hi\x00\x11[\"hello\",\"world\"]\x01n\x00\x011", string(marshalled))
+
+	parsed, err := New(marshalled)
+	require.NoError(t, err)
+	read, ok, err := Get[[]string](parsed, "hi")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"hello", "world"}, read)
+	assert.Equal(t, `{"hi":["hello","world"],"n":1}`, string(parsed.Marshal()))
+
+	empty, err := New((*Data)(nil).MarshalBinary())
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(empty.Marshal()))
+
+	for _, invalid := range []string{
+		binaryMagic + "\x02hi",
+		binaryMagic + "\x02hi\x00",
+		binaryMagic + "\x02hi\x00\x05[]",
+		binaryMagic + "\x02hi\x07\x02[]",
+	} {
+		_, err := New([]byte(invalid))
+		assert.Error(t, err, "%q", invalid)
+	}
+}
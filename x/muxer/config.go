@@ -0,0 +1,243 @@
+// Copyright 2016-2024, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package muxer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	rpc "github.com/pulumi/pulumi/sdk/v3/proto/go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ConfigResolution names a strategy for resolving a config key that muxed servers disagree on.
+type ConfigResolution string
+
+const (
+	// Use the value returned by ConfigPolicy.Server.
+	ConfigPreferServer ConfigResolution = "preferServer"
+	// Fail CheckConfig if servers return different values.
+	ConfigRequireAgreement ConfigResolution = "requireAgreement"
+	// Merge list values as a union and map values key by key, with earlier servers winning for conflicting
+	// map keys. Values that are neither lists nor maps must agree.
+	ConfigMerge ConfigResolution = "merge"
+)
+
+// ConfigPolicy controls how the muxer resolves a single config key when the CheckConfig results of its servers
+// disagree.
+//
+// Without a policy, the value of the first server that returned the key is used.
+type ConfigPolicy struct {
+	Resolution ConfigResolution `json:"resolution"`
+
+	// The index of the preferred server, for ConfigPreferServer.
+	Server int `json:"server,omitempty"`
+}
+
+// PreferServer returns a policy that resolves conflicts in favor of the server at index i.
+func PreferServer(i int) ConfigPolicy {
+	return ConfigPolicy{Resolution: ConfigPreferServer, Server: i}
+}
+
+// RequireAgreement returns a policy that reports a CheckFailure when servers disagree.
+func RequireAgreement() ConfigPolicy {
+	return ConfigPolicy{Resolution: ConfigRequireAgreement}
+}
+
+// MergeValues returns a policy that merges list and map values returned by different servers.
+func MergeValues() ConfigPolicy {
+	return ConfigPolicy{Resolution: ConfigMerge}
+}
+
+func (p ConfigPolicy) validate(servers int) error {
+	switch p.Resolution {
+	case ConfigPreferServer:
+		if p.Server < 0 || p.Server >= servers {
+			return fmt.Errorf("preferred server %d is out of range for %d servers", p.Server, servers)
+		}
+	case ConfigRequireAgreement, ConfigMerge:
+	default:
+		return fmt.Errorf("unknown resolution %q", p.Resolution)
+	}
+	return nil
+}
+
+func validateConfigPolicies(policies map[string]ConfigPolicy, servers int) error {
+	keys := make([]string, 0, len(policies))
+	for k := range policies {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := policies[k].validate(servers); err != nil {
+			return fmt.Errorf("invalid config policy for %q: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// A config value returned by the server at index server.
+type serverValue struct {
+	server int
+	value  *structpb.Value
+}
+
+// resolveConfigValue picks the value of the config key k from the values returned by each server, in server
+// order. If the values cannot be reconciled under the policy for k, a CheckFailure is returned alongside the value
+// of the first server.
+func resolveConfigValue(
+	k string, policy *ConfigPolicy, values []serverValue,
+) (*structpb.Value, *rpc.CheckFailure) {
+	first := values[0]
+	conflict := firstConflict(values)
+	if conflict == nil {
+		return first.value, nil
+	}
+
+	if policy == nil {
+		glog.V(9).Infof("[muxer] CheckConfig results do not agree on the '%s' property:"+
+			"\n    server %d: %s"+
+			"\n    server %d: %s"+
+			"\nPicking the server %d response",
+			k, first.server, showStruct(first.value), conflict.server, showStruct(conflict.value), first.server)
+		return first.value, nil
+	}
+
+	disagree := func() *rpc.CheckFailure {
+		// The values themselves are left out, since they may be secret.
+		return &rpc.CheckFailure{
+			Property: k,
+			Reason: fmt.Sprintf("muxed providers disagree on the value of %q: "+
+				"server %d and server %d returned different values (config policy %q)",
+				k, first.server, conflict.server, policy.Resolution),
+		}
+	}
+
+	switch policy.Resolution {
+	case ConfigPreferServer:
+		for _, v := range values {
+			if v.server == policy.Server {
+				return v.value, nil
+			}
+		}
+		return first.value, nil
+	case ConfigMerge:
+		if merged, ok := mergeConfigValues(values); ok {
+			return merged, nil
+		}
+		return first.value, disagree()
+	default:
+		return first.value, disagree()
+	}
+}
+
+func firstConflict(values []serverValue) *serverValue {
+	for i := range values[1:] {
+		if !proto.Equal(values[0].value, values[i+1].value) {
+			return &values[i+1]
+		}
+	}
+	return nil
+}
+
+// mergeConfigValues merges values that are either all lists or all maps.
+func mergeConfigValues(values []serverValue) (*structpb.Value, bool) {
+	switch values[0].value.GetKind().(type) {
+	case *structpb.Value_ListValue:
+		var merged []*structpb.Value
+		for _, v := range values {
+			l, ok := v.value.GetKind().(*structpb.Value_ListValue)
+			if !ok {
+				return nil, false
+			}
+		elements:
+			for _, e := range l.ListValue.GetValues() {
+				for _, existing := range merged {
+					if proto.Equal(existing, e) {
+						continue elements
+					}
+				}
+				merged = append(merged, e)
+			}
+		}
+		return structpb.NewListValue(&structpb.ListValue{Values: merged}), true
+	case *structpb.Value_StructValue:
+		merged := map[string]*structpb.Value{}
+		for _, v := range values {
+			s, ok := v.value.GetKind().(*structpb.Value_StructValue)
+			if !ok {
+				return nil, false
+			}
+			for k, e := range s.StructValue.GetFields() {
+				if _, has := merged[k]; !has {
+					merged[k] = e
+				}
+			}
+		}
+		return structpb.NewStructValue(&structpb.Struct{Fields: merged}), true
+	default:
+		return nil, false
+	}
+}
+
+// ownsConfigDiff reports whether the server at index i may report diffs for the top-level config key of path.
+func ownsConfigDiff(policies map[string]ConfigPolicy, i int, path string) bool {
+	k := path
+	if j := strings.IndexAny(path, ".["); j >= 0 {
+		k = path[:j]
+	}
+	p, ok := policies[k]
+	return !ok || p.Resolution != ConfigPreferServer || p.Server == i
+}
+
+// filterConfigDiff removes the parts of resp that concern config keys preferred from a server other than i.
+func filterConfigDiff(policies map[string]ConfigPolicy, i int, resp *rpc.DiffResponse) *rpc.DiffResponse {
+	if len(policies) == 0 {
+		return resp
+	}
+	filter := func(keys []string) []string {
+		var out []string
+		for _, k := range keys {
+			if ownsConfigDiff(policies, i, k) {
+				out = append(out, k)
+			}
+		}
+		return out
+	}
+
+	filtered := proto.Clone(resp).(*rpc.DiffResponse)
+	filtered.Replaces = filter(resp.GetReplaces())
+	filtered.Diffs = filter(resp.GetDiffs())
+	filtered.Stables = filter(resp.GetStables())
+	if resp.GetDetailedDiff() != nil {
+		filtered.DetailedDiff = map[string]*rpc.PropertyDiff{}
+		for k, v := range resp.GetDetailedDiff() {
+			if ownsConfigDiff(policies, i, k) {
+				filtered.DetailedDiff[k] = v
+			}
+		}
+	}
+
+	// If all changes reported by this server were filtered out, it no longer has any changes to report.
+	if resp.GetChanges() == rpc.DiffResponse_DIFF_SOME &&
+		len(filtered.Replaces) == 0 && len(filtered.Diffs) == 0 && len(filtered.DetailedDiff) == 0 &&
+		(len(resp.GetReplaces()) > 0 || len(resp.GetDiffs()) > 0 || len(resp.GetDetailedDiff()) > 0) {
+		filtered.Changes = rpc.DiffResponse_DIFF_NONE
+	}
+	return filtered
+}
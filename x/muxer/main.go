@@ -52,12 +52,17 @@ import (
 // based endpoints filter the schema so each provider is only shown keys that it expects
 // to see. It is possible for multiple subsidiary providers to accept the same key.
 //
-//   - CheckConfig: Broadcast to each server. Any diffs between returned results errors.
+//   - CheckConfig: Broadcast to each server. When servers return different values for a
+//     key, the ConfigPolicy for that key in DispatchTable.Config decides which value is
+//     used, or whether a CheckFailure is reported. Without a policy, the value of the
+//     first server that returned the key is used.
 //
 //   - DiffConfig: Broadcast to each server. Results are then merged with the most drastic
-//     action dominating.
+//     action dominating. For keys with a ConfigPreferServer policy, only the diff reported
+//     by the preferred server is considered.
 //
-//   - Configure: Broadcast to each server for individual configuration. When computing
+//   - Configure: Broadcast to each server for individual configuration. Each server is
+//     configured with the inputs resolved by CheckConfig. When computing
 //     the returned set of capabilities, each option is set to the AND of the subsidiary
 //     servers. This means that the Muxed server is only as capable as the least capable
 //     of its subsidiaries.
@@ -133,6 +138,10 @@ func (m Main) Server(host *provider.HostClient, module, version string) (rpc.Res
 		dispatchTable = mComputed.dispatchTable
 	}
 
+	if err := validateConfigPolicies(dispatchTable.Config, len(servers)); err != nil {
+		return nil, err
+	}
+
 	server := mux(host, dispatchTable, pulumiSchema, m.GetMappingHandler, servers...)
 
 	return server, nil
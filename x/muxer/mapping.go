@@ -67,6 +67,9 @@ type dispatchTable struct {
 	// Resources and functions can only map to a single provider
 	Resources map[string]int `json:"resources"`
 	Functions map[string]int `json:"functions"`
+
+	// How to resolve config keys that servers disagree on, keyed by the Pulumi name of the config key.
+	Config map[string]ConfigPolicy `json:"config,omitempty"`
 }
 
 func newDispatchTable() dispatchTable {
@@ -19,9 +19,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"sync"
 
-	"github.com/golang/glog"
 	"github.com/hashicorp/go-multierror"
 	"github.com/pulumi/pulumi/pkg/v3/resource/provider"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
@@ -131,8 +131,19 @@ func (m *muxer) CheckConfig(ctx context.Context, req *rpc.CheckRequest) (*rpc.Ch
 	inputs := &structpb.Struct{Fields: map[string]*structpb.Value{}}
 	failures := []*rpc.CheckFailure{}
 	uniqueFailures := map[string]struct{}{}
+	addFailure := func(e *rpc.CheckFailure) {
+		// Here we de-duplicate rpc failures.
+		s := e.GetProperty() + ":" + e.GetReason()
+		if _, has := uniqueFailures[s]; has {
+			return
+		}
+		uniqueFailures[s] = struct{}{}
+		failures = append(failures, e)
+	}
 	var errs multierror.Error
 	uniqueErrors := map[string]struct{}{}
+	values := map[string][]serverValue{}
+	var keys []string
 	for i, r := range asyncJoin(subs) {
 		if err := r.B; err != nil {
 			errString := err.Error()
@@ -145,29 +156,29 @@ func (m *muxer) CheckConfig(ctx context.Context, req *rpc.CheckRequest) (*rpc.Ch
 			continue
 		}
 
-		// Add missing inputs, but don't override existing inputs.
 		for k, v := range r.A.GetInputs().GetFields() {
-			existingValue, has := inputs.Fields[k]
-			if has && !proto.Equal(existingValue, v) {
-				// If different servers return different values, pick arbitrarily.
-				glog.V(9).Infof("[muxer] CheckConfig results do not agree on the '%s' property:"+
-					"\n    server %d: %s"+
-					"\n    server %d: %s"+
-					"\nPicking the server %d response",
-					k, i-1, showStruct(existingValue), i, showStruct(v), i-1)
-			} else {
-				inputs.Fields[k] = v
+			if _, has := values[k]; !has {
+				keys = append(keys, k)
 			}
+			values[k] = append(values[k], serverValue{server: i, value: v})
 		}
 
-		// Here we de-duplicate rpc failures.
 		for _, e := range r.A.GetFailures() {
-			s := e.GetProperty() + ":" + e.GetReason()
-			if _, has := uniqueFailures[s]; has {
-				continue
-			}
-			uniqueFailures[s] = struct{}{}
-			failures = append(failures, e)
+			addFailure(e)
+		}
+	}
+
+	// Resolve keys in a stable order, so that failures are reported deterministically.
+	sort.Strings(keys)
+	for _, k := range keys {
+		var policy *ConfigPolicy
+		if p, ok := m.dispatchTable.Config[k]; ok {
+			policy = &p
+		}
+		v, failure := resolveConfigValue(k, policy, values[k])
+		inputs.Fields[k] = v
+		if failure != nil {
+			addFailure(failure)
 		}
 	}
 
@@ -237,13 +248,14 @@ func (m *muxer) DiffConfig(ctx context.Context, req *rpc.DiffRequest) (*rpc.Diff
 		hasDetailedDiff = true
 	)
 
-	for _, r := range responses {
+	for i, r := range responses {
 		if err := r.B; err != nil {
 			errs.Errors = append(errs.Errors, err)
 			continue
 		}
 
-		resp := r.A
+		// Keys with a preferred server only report the diff of that server.
+		resp := filterConfigDiff(m.dispatchTable.Config, i, r.A)
 
 		if resp.DeleteBeforeReplace {
 			deleteBeforeReplace = true
@@ -197,3 +197,118 @@ func (s diffConfigServer) DiffConfig(
 	}
 	return s.UnimplementedResourceProviderServer.DiffConfig(ctx, req)
 }
+
+func TestCheckConfigPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	list := func(elems ...string) *structpb.Value {
+		values := make([]*structpb.Value, len(elems))
+		for i, e := range elems {
+			values[i] = structpb.NewStringValue(e)
+		}
+		return structpb.NewListValue(&structpb.ListValue{Values: values})
+	}
+	obj := func(fields map[string]string) *structpb.Value {
+		s := &structpb.Struct{Fields: map[string]*structpb.Value{}}
+		for k, v := range fields {
+			s.Fields[k] = structpb.NewStringValue(v)
+		}
+		return structpb.NewStructValue(s)
+	}
+
+	server0 := &checkConfigServer{inputs: map[string]*structpb.Value{
+		"region":    structpb.NewStringValue("us-east-1"),
+		"preferred": structpb.NewStringValue("zero"),
+		"strict":    structpb.NewStringValue("zero"),
+		"tags":      obj(map[string]string{"a": "0", "b": "0"}),
+		"zones":     list("a", "b"),
+		"agreed":    structpb.NewStringValue("same"),
+	}}
+	server1 := &checkConfigServer{inputs: map[string]*structpb.Value{
+		"region":    structpb.NewStringValue("US-EAST-1"),
+		"preferred": structpb.NewStringValue("one"),
+		"strict":    structpb.NewStringValue("one"),
+		"tags":      obj(map[string]string{"b": "1", "c": "1"}),
+		"zones":     list("b", "c"),
+		"agreed":    structpb.NewStringValue("same"),
+	}}
+
+	var table dispatchTable
+	table.Config = map[string]ConfigPolicy{
+		"preferred": PreferServer(1),
+		"strict":    RequireAgreement(),
+		"tags":      MergeValues(),
+		"zones":     MergeValues(),
+		"agreed":    RequireAgreement(),
+	}
+	m := &muxer{
+		dispatchTable: table,
+		servers:       []server{server0, server1},
+	}
+
+	resp, err := m.CheckConfig(ctx, &pulumirpc.CheckRequest{})
+	require.NoError(t, err)
+
+	fields := resp.GetInputs().GetFields()
+	// Without a policy, the first server wins.
+	assert.Equal(t, "us-east-1", fields["region"].GetStringValue())
+	assert.Equal(t, "one", fields["preferred"].GetStringValue())
+	assert.Equal(t, "zero", fields["strict"].GetStringValue())
+	assert.Equal(t, "same", fields["agreed"].GetStringValue())
+	assert.Equal(t, map[string]interface{}{"a": "0", "b": "0", "c": "1"}, fields["tags"].AsInterface())
+	assert.Equal(t, []interface{}{"a", "b", "c"}, fields["zones"].AsInterface())
+
+	require.Len(t, resp.GetFailures(), 1)
+	assert.Equal(t, "strict", resp.GetFailures()[0].GetProperty())
+	assert.Contains(t, resp.GetFailures()[0].GetReason(), "server 0 and server 1 returned different values")
+}
+
+type checkConfigServer struct {
+	pulumirpc.UnimplementedResourceProviderServer
+	inputs map[string]*structpb.Value
+}
+
+func (s *checkConfigServer) CheckConfig(
+	ctx context.Context, req *pulumirpc.CheckRequest,
+) (*pulumirpc.CheckResponse, error) {
+	return &pulumirpc.CheckResponse{Inputs: &structpb.Struct{Fields: s.inputs}}, nil
+}
+
+func TestDiffConfigPreferServer(t *testing.T) {
+	ctx := context.Background()
+
+	var table dispatchTable
+	table.Config = map[string]ConfigPolicy{"region": PreferServer(0)}
+	m := &muxer{
+		dispatchTable: table,
+		servers: []pulumirpc.ResourceProviderServer{
+			&diffConfigServer{resp: &pulumirpc.DiffResponse{
+				Stables: []string{"region"},
+				Changes: pulumirpc.DiffResponse_DIFF_NONE,
+			}},
+			// The second server normalizes region differently, and would otherwise force a replace.
+			&diffConfigServer{resp: &pulumirpc.DiffResponse{
+				Replaces: []string{"region"},
+				Changes:  pulumirpc.DiffResponse_DIFF_SOME,
+				DetailedDiff: map[string]*pulumirpc.PropertyDiff{
+					"region": {Kind: pulumirpc.PropertyDiff_UPDATE_REPLACE},
+				},
+			}},
+		},
+	}
+
+	resp, err := m.DiffConfig(ctx, &pulumirpc.DiffRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, pulumirpc.DiffResponse_DIFF_NONE, resp.GetChanges())
+	assert.Empty(t, resp.GetReplaces())
+	assert.Empty(t, resp.GetDetailedDiff())
+	assert.Equal(t, []string{"region"}, resp.GetStables())
+}
+
+func TestConfigPolicyValidation(t *testing.T) {
+	assert.NoError(t, validateConfigPolicies(map[string]ConfigPolicy{"region": PreferServer(1)}, 2))
+	assert.ErrorContains(t, validateConfigPolicies(map[string]ConfigPolicy{"region": PreferServer(2)}, 2),
+		`invalid config policy for "region": preferred server 2 is out of range for 2 servers`)
+	assert.ErrorContains(t, validateConfigPolicies(map[string]ConfigPolicy{"region": {Resolution: "other"}}, 2),
+		`unknown resolution "other"`)
+}